@@ -1,10 +1,19 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
 	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/freshwebio/esengine/grammar"
+	"github.com/freshwebio/esengine/parser"
 	"github.com/namsral/flag"
 )
 
@@ -15,6 +24,20 @@ func main() {
 		switch os.Args[1] {
 		case "build":
 			build()
+		case "validate":
+			validate()
+		case "transform":
+			transform()
+		case "diagram":
+			diagram()
+		case "sample":
+			sample()
+		case "diff":
+			diff()
+		case "stats":
+			stats()
+		case "check":
+			check()
 		default:
 			usage()
 		}
@@ -22,17 +45,402 @@ func main() {
 }
 
 func usage() {
-	fmt.Print(`
+	fmt.Print(`esegrammar is a tool for building parse tables from the ECMAScript syntactic grammar.
+
+Usage:
+
+	esegrammar <command> [arguments]
+
+The commands are:
+
+	build       generate the symbols and parse table for a grammar
+	validate    report problems with a grammar without building it
+	transform   write a grammar as YAML once it has been transformed for building
+	diagram     draw a dependency graph or railroad diagrams of a grammar
+	sample      generate random programs from a grammar for fuzzing
+	diff        report the changes to the productions between two grammars
+	stats       report the size of a grammar at each stage of its transformation
+	check       report whether inputs are accepted or rejected by a grammar
+
 `)
 }
 
 func build() {
 	outputFile := flag.String("output", "", "The target file containing the generated symbols and parse table")
 	grammarFile := flag.String("grammar", "", "The file containing the grammar")
-	grammarFmt := flag.String("format", "yaml", "The storage format of the input grammar")
+	grammarFmt := flag.String("format", "yaml", "The storage format of the input grammar, one of yaml, json or ebnf")
 	pkg := flag.String("package", "", "The go package the file's contents will belong to")
+	algorithm := flag.String("algorithm", "ll", "The parse table construction algorithm, either ll or lalr")
+	supplemental := flag.String("supplemental", "", "The file containing the supplemental grammar which refines the cover productions")
+	cacheDir := flag.String("cache", "", "The directory the build artefacts are cached in, caching is disabled when empty")
+	verify := flag.Bool("verify", false, "Check the transformed grammar accepts the same sentences as the grammar before building its parse table")
+	verifyLength := flag.Int("verify-length", grammar.DefaultEquivalenceLength, "The number of terminals of the longest sentence compared when verifying")
 	// Exclude build from the arguments that are parsed, otherwise no arguments
 	// will be parsed.
 	flag.CommandLine.Parse(os.Args[2:])
-	grammar.Build(*grammarFile, *grammarFmt, *outputFile, *pkg)
+	err := grammar.BuildFile(*grammarFile, *outputFile, &grammar.BuildOptions{
+		Format:       *grammarFmt,
+		Package:      *pkg,
+		Algorithm:    *algorithm,
+		Supplemental: *supplemental,
+		CacheDir:     *cacheDir,
+		Verify:       *verify,
+		VerifyLength: *verifyLength,
+	})
+	if err != nil {
+		var conflictErr *grammar.ConflictError
+		if errors.As(err, &conflictErr) {
+			for _, conflict := range conflictErr.Conflicts {
+				fmt.Fprintln(os.Stderr, conflict)
+			}
+			for _, conflict := range conflictErr.LRConflicts {
+				fmt.Fprintln(os.Stderr, conflict)
+			}
+		}
+		var equivalenceErr *grammar.EquivalenceError
+		if errors.As(err, &equivalenceErr) {
+			for _, difference := range equivalenceErr.Differences {
+				fmt.Fprintln(os.Stderr, difference)
+			}
+		}
+		log.Fatal(err)
+	}
+}
+
+func validate() {
+	grammarFile := flag.String("grammar", "", "The file containing the grammar")
+	grammarFmt := flag.String("format", "yaml", "The storage format of the input grammar, one of yaml, json or ebnf")
+	supplemental := flag.String("supplemental", "", "The file containing the supplemental grammar which refines the cover productions")
+	flag.CommandLine.Parse(os.Args[2:])
+	problems := grammar.ValidateFile(*grammarFile, *grammarFmt)
+	if *supplemental != "" {
+		g, err := grammar.LoadFile(*grammarFile, *grammarFmt)
+		if err != nil {
+			log.Fatal(err)
+		}
+		supplementalGrammar, err := grammar.LoadFile(*supplemental, *grammarFmt)
+		if err != nil {
+			log.Fatal(err)
+		}
+		problems = append(problems, grammar.ValidateSupplemental(g, supplementalGrammar)...)
+	}
+	for _, problem := range problems {
+		fmt.Fprintln(os.Stderr, problem)
+	}
+	if len(problems) > 0 {
+		log.Fatalf("%v problems were found in %v", len(problems), *grammarFile)
+	}
+}
+
+func transform() {
+	outputFile := flag.String("output", "", "The target file for the transformed grammar, defaults to stdout")
+	grammarFile := flag.String("grammar", "", "The file containing the grammar")
+	grammarFmt := flag.String("format", "yaml", "The storage format of the input grammar, one of yaml, json or ebnf")
+	supplemental := flag.String("supplemental", "", "The file containing the supplemental grammar which refines the cover productions")
+	flag.CommandLine.Parse(os.Args[2:])
+	g, err := grammar.LoadFile(*grammarFile, *grammarFmt)
+	if err == nil && *supplemental != "" {
+		err = grammar.LoadSupplementalFile(g, *supplemental, *grammarFmt)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	grammar.Transform(g)
+	if *outputFile == "" {
+		err = grammar.WriteYAML(os.Stdout, g)
+	} else {
+		err = writeFile(*outputFile, func(output *os.File) error {
+			return grammar.WriteYAML(output, g)
+		})
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func diagram() {
+	outputFile := flag.String("output", "", "The target file, or directory for svg diagrams, defaults to stdout")
+	grammarFile := flag.String("grammar", "", "The file containing the grammar")
+	grammarFmt := flag.String("format", "yaml", "The storage format of the input grammar, one of yaml, json or ebnf")
+	diagramType := flag.String("type", "dot", "The type of diagram, one of dot, html or svg")
+	transformed := flag.Bool("transformed", false, "Draw the grammar once it has been transformed for building")
+	flag.CommandLine.Parse(os.Args[2:])
+	g, err := grammar.LoadFile(*grammarFile, *grammarFmt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *transformed {
+		grammar.Transform(g)
+	}
+	if *diagramType == "svg" {
+		// Each production is drawn in its own document.
+		if *outputFile == "" {
+			log.Fatal("an output directory is required for svg diagrams")
+		}
+		err = os.MkdirAll(*outputFile, 0755)
+		for i := 0; err == nil && i < len(g.Productions); i++ {
+			err = writeFile(filepath.Join(*outputFile, g.Productions[i].Name+".svg"), func(output *os.File) error {
+				return grammar.WriteRailroadSVG(output, g.Productions[i])
+			})
+		}
+	} else {
+		write := grammar.WriteDOT
+		if *diagramType == "html" {
+			write = grammar.WriteRailroadHTML
+		} else if *diagramType != "dot" {
+			log.Fatalf("unknown diagram type %v", *diagramType)
+		}
+		if *outputFile == "" {
+			err = write(os.Stdout, g)
+		} else {
+			err = writeFile(*outputFile, func(output *os.File) error {
+				return write(output, g)
+			})
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func sample() {
+	outputFile := flag.String("output", "", "The target file for the generated programs, defaults to stdout")
+	grammarFile := flag.String("grammar", "", "The file containing the grammar")
+	grammarFmt := flag.String("format", "yaml", "The storage format of the input grammar, one of yaml, json or ebnf")
+	start := flag.String("start", "Script", "The start symbol the programs are derived from")
+	seed := flag.Int64("seed", 1, "The seed of the first program, each following program uses the next seed")
+	count := flag.Int("count", 1, "The number of programs to generate")
+	depth := flag.Int("depth", grammar.DefaultSampleDepth, "The depth of derivation after which the shallowest alternatives are chosen")
+	size := flag.Int("size", grammar.DefaultSampleSize, "The number of terminals after which the shallowest alternatives are chosen")
+	flag.CommandLine.Parse(os.Args[2:])
+	g, err := grammar.LoadFile(*grammarFile, *grammarFmt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	write := func(output io.Writer) error {
+		for i := 0; i < *count; i++ {
+			// Each program has its own seed so a failure can be
+			// reproduced on its own with -seed and -count 1.
+			program, err := grammar.Sample(g, *start, &grammar.SampleOptions{
+				Seed:     *seed + int64(i),
+				MaxDepth: *depth,
+				MaxSize:  *size,
+			})
+			if err != nil {
+				return err
+			}
+			if _, err = fmt.Fprintf(output, "// seed: %v\n%v\n\n", *seed+int64(i), program); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if *outputFile == "" {
+		err = write(os.Stdout)
+	} else {
+		err = writeFile(*outputFile, func(output *os.File) error {
+			return write(output)
+		})
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func diff() {
+	grammarFmt := flag.String("format", "yaml", "The storage format of both grammars, one of yaml, json or ebnf")
+	transformed := flag.Bool("transformed", false, "Also report the changes once both grammars have been transformed for building")
+	conflicts := flag.Bool("conflicts", false, "Also report the conflicts introduced and resolved by the changes")
+	flag.CommandLine.Parse(os.Args[2:])
+	if flag.NArg() != 2 {
+		log.Fatal("usage: esegrammar diff [arguments] old-grammar new-grammar")
+	}
+	before, err := grammar.LoadFile(flag.Arg(0), *grammarFmt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	after, err := grammar.LoadFile(flag.Arg(1), *grammarFmt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, change := range grammar.Diff(before, after) {
+		fmt.Println(change)
+	}
+	if !*transformed && !*conflicts {
+		return
+	}
+	grammar.Transform(before)
+	grammar.Transform(after)
+	if *transformed {
+		fmt.Println("\nTransformed:")
+		for _, change := range grammar.Diff(before, after) {
+			fmt.Println(change)
+		}
+	}
+	if *conflicts {
+		introduced, resolved := grammar.DiffConflicts(detectConflicts(before), detectConflicts(after))
+		fmt.Println("\nIntroduced conflicts:")
+		for _, conflict := range introduced {
+			fmt.Print(conflict)
+		}
+		fmt.Println("\nResolved conflicts:")
+		for _, conflict := range resolved {
+			fmt.Print(conflict)
+		}
+	}
+}
+
+func stats() {
+	outputFile := flag.String("output", "", "The target file for the report, defaults to stdout")
+	grammarFile := flag.String("grammar", "", "The file containing the grammar")
+	grammarFmt := flag.String("format", "yaml", "The storage format of the input grammar, one of yaml, json or ebnf")
+	asJSON := flag.Bool("json", false, "Write the report as JSON for tracking between builds")
+	lookahead := flag.Int("k", grammar.DefaultStatsLookahead, "The largest lookahead tried when working out the lookahead the grammar needs")
+	flag.CommandLine.Parse(os.Args[2:])
+	g, err := grammar.LoadFile(*grammarFile, *grammarFmt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	report := grammar.GrammarStats(g, &grammar.StatsOptions{MaxLookahead: *lookahead})
+	write := func(output io.Writer) error {
+		if *asJSON {
+			encoder := json.NewEncoder(output)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(report)
+		}
+		return writeStats(output, report, *lookahead)
+	}
+	if *outputFile == "" {
+		err = write(os.Stdout)
+	} else {
+		err = writeFile(*outputFile, func(output *os.File) error {
+			return write(output)
+		})
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Deals with writing the statistics of each stage as a table, a lookahead beyond
+// the largest one tried is written as >k and stages that were not analysed as -.
+func writeStats(output io.Writer, report []*grammar.Stats, maxLookahead int) error {
+	table := tabwriter.NewWriter(output, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "stage\tproductions\talternatives\tsymbols\tterminals\tparameterised\tcombinations\tlookahead\tconflicts")
+	for _, stage := range report {
+		lookahead, conflicts := "-", "-"
+		if stage.Lookahead > maxLookahead {
+			lookahead, conflicts = fmt.Sprintf(">%v", maxLookahead), fmt.Sprint(stage.Conflicts)
+		} else if stage.Lookahead > 0 {
+			lookahead, conflicts = fmt.Sprint(stage.Lookahead), fmt.Sprint(stage.Conflicts)
+		}
+		fmt.Fprintf(
+			table, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+			stage.Stage, stage.Productions, stage.Alternatives, stage.Symbols, stage.Terminals,
+			stage.ParameterisedProductions, stage.ParamCombinations, lookahead, conflicts,
+		)
+	}
+	return table.Flush()
+}
+
+func check() {
+	grammarFile := flag.String("grammar", "", "The file containing the grammar")
+	grammarFmt := flag.String("format", "yaml", "The storage format of the input grammar, one of yaml, json or ebnf")
+	inputFile := flag.String("input", "", "The file containing one input per line, prefixed with + or - when it must be accepted or rejected, defaults to the remaining arguments")
+	start := flag.String("start", "Script", "The start symbol the inputs are derived from, optionally with enabled parameters such as Statement[Yield, Return]")
+	tokens := flag.Bool("tokens", false, "Read each input as the names of its terminals separated by spaces instead of source text")
+	transformed := flag.Bool("transformed", false, "Check the inputs against the grammar once it has been transformed for building")
+	flag.CommandLine.Parse(os.Args[2:])
+	g, err := grammar.LoadFile(*grammarFile, *grammarFmt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	checker, err := grammar.NewChecker(g, *start, &grammar.CheckOptions{Transformed: *transformed})
+	if err != nil {
+		log.Fatal(err)
+	}
+	inputs := flag.Args()
+	if *inputFile != "" {
+		data, err := ioutil.ReadFile(*inputFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		inputs = strings.Split(string(data), "\n")
+	}
+	failures := 0
+	for _, line := range inputs {
+		// Inputs prefixed with + must be accepted and those prefixed with - rejected,
+		// blank lines and lines starting with # are skipped.
+		input := strings.TrimSpace(line)
+		if input == "" || strings.HasPrefix(input, "#") {
+			continue
+		}
+		expected := ""
+		if strings.HasPrefix(input, "+ ") || strings.HasPrefix(input, "- ") {
+			expected, input = input[:1], strings.TrimSpace(input[2:])
+		}
+		var checkTokens []grammar.CheckToken
+		if *tokens {
+			checkTokens = grammar.TerminalTokens(input)
+		} else if checkTokens, err = lexTokens(input); err != nil {
+			log.Fatalf("%v: %v", input, err)
+		}
+		result := checker.Check(checkTokens)
+		status := "ok"
+		if (expected == "+" && !result.Accepted) || (expected == "-" && result.Accepted) {
+			status = "FAIL"
+			failures++
+		}
+		if expected == "" {
+			status = "-"
+		}
+		fmt.Printf("%v\t%v\t%v\n", status, result, input)
+	}
+	if failures > 0 {
+		log.Fatalf("%v inputs were not accepted or rejected as expected", failures)
+	}
+}
+
+// Deals with tokenising source text into the tokens checked against a grammar,
+// line terminators are dropped and recorded on the token that follows them.
+func lexTokens(source string) ([]grammar.CheckToken, error) {
+	lexed, err := parser.NewLexer().Tokenise([]rune(source), parser.InputElementDiv)
+	if err != nil {
+		return nil, err
+	}
+	tokens := []grammar.CheckToken{}
+	lineTerminator := false
+	for _, token := range lexed {
+		if token.Kind == parser.LineTerminatorToken {
+			lineTerminator = true
+			continue
+		}
+		tokens = append(tokens, grammar.CheckToken{
+			Terminals:            parser.TerminalNames(token),
+			Text:                 token.Value,
+			LineTerminatorBefore: lineTerminator,
+		})
+		lineTerminator = false
+	}
+	return tokens, nil
+}
+
+// Provides the LL(1) conflicts of a grammar which has been transformed for building.
+func detectConflicts(g *grammar.Grammar) []*grammar.Conflict {
+	return grammar.DetectConflicts(g, grammar.Analyse(g, 1, grammar.StartSymbols(g)...))
+}
+
+// Deals with creating the file at the given path and
+// writing its contents with the provided function.
+func writeFile(path string, write func(*os.File) error) error {
+	output, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = write(output)
+	closeErr := output.Close()
+	if err == nil {
+		err = closeErr
+	}
+	return err
 }
@@ -0,0 +1,422 @@
+package grammar
+
+import (
+	"strings"
+)
+
+// TerminalSequence provides a sequence of terminal symbol names
+// of at most k symbols used in FIRST_k and FOLLOW_k sets.
+// The empty sequence represents the empty string.
+type TerminalSequence []string
+
+// String provides the space separated representation
+// of the terminal sequence.
+func (s TerminalSequence) String() string {
+	if len(s) == 0 {
+		return Epsilon
+	}
+	return strings.Join(s, " ")
+}
+
+// HasPrefix determines whether the terminal sequence
+// starts with the given prefix.
+func (s TerminalSequence) HasPrefix(prefix []string) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	match := true
+	i := 0
+	for match && i < len(prefix) {
+		if s[i] != prefix[i] {
+			match = false
+		} else {
+			i++
+		}
+	}
+	return match
+}
+
+// SequenceSet provides an insertion ordered set of terminal sequences.
+type SequenceSet struct {
+	keys      map[string]bool
+	Sequences []TerminalSequence
+}
+
+// NewSequenceSet creates a new set containing the provided sequences.
+func NewSequenceSet(sequences ...TerminalSequence) *SequenceSet {
+	set := &SequenceSet{keys: map[string]bool{}}
+	for _, seq := range sequences {
+		set.Add(seq)
+	}
+	return set
+}
+
+// Add deals with adding a sequence to the set, returning
+// true when the sequence was not already in the set.
+func (s *SequenceSet) Add(seq TerminalSequence) bool {
+	key := sequenceKey(seq)
+	if s.keys[key] {
+		return false
+	}
+	s.keys[key] = true
+	s.Sequences = append(s.Sequences, seq)
+	return true
+}
+
+// AddAll deals with adding every sequence in the other set,
+// returning true when at least one sequence was new.
+func (s *SequenceSet) AddAll(other *SequenceSet) bool {
+	changed := false
+	for _, seq := range other.Sequences {
+		if s.Add(seq) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Contains determines whether the given sequence is in the set.
+func (s *SequenceSet) Contains(seq TerminalSequence) bool {
+	return s.keys[sequenceKey(seq)]
+}
+
+// Len provides the number of sequences in the set.
+func (s *SequenceSet) Len() int {
+	return len(s.Sequences)
+}
+
+// Intersect provides the sequences that are present in both sets.
+func (s *SequenceSet) Intersect(other *SequenceSet) *SequenceSet {
+	shared := NewSequenceSet()
+	for _, seq := range s.Sequences {
+		if other.Contains(seq) {
+			shared.Add(seq)
+		}
+	}
+	return shared
+}
+
+// Strings provides the string representation of each sequence in the set.
+func (s *SequenceSet) Strings() []string {
+	strs := []string{}
+	for _, seq := range s.Sequences {
+		strs = append(strs, seq.String())
+	}
+	return strs
+}
+
+func sequenceKey(seq TerminalSequence) string {
+	return strings.Join(seq, "\x00")
+}
+
+// Analysis holds the FIRST_k and FOLLOW_k sets computed
+// for each production of a grammar.
+type Analysis struct {
+	// K is the maximum length of the terminal sequences held in the sets.
+	K int
+	// Starts holds the names of the productions treated as start symbols,
+	// these are followed by the end of input.
+	Starts      []string
+	First       map[string]*SequenceSet
+	Follow      map[string]*SequenceSet
+	productions map[string]*Production
+}
+
+// Analyse deals with computing the FIRST_k and FOLLOW_k sets for every
+// production of the given grammar.
+// When no start symbols are provided the first production of the grammar
+// is used as the start symbol.
+func Analyse(grammar *Grammar, k int, starts ...string) *Analysis {
+	a := newAnalysis(grammar, k, starts)
+	a.computeFirst(grammar, nil)
+	a.computeFollow(grammar, nil)
+	return a
+}
+
+// Provides the analysis of the given grammar with empty FIRST_k
+// and FOLLOW_k sets for every production, ready to be computed.
+func newAnalysis(grammar *Grammar, k int, starts []string) *Analysis {
+	if k < 1 {
+		k = 1
+	}
+	if len(starts) == 0 && len(grammar.Productions) > 0 {
+		starts = []string{grammar.Productions[0].Name}
+	}
+	a := &Analysis{
+		K:           k,
+		Starts:      starts,
+		First:       map[string]*SequenceSet{},
+		Follow:      map[string]*SequenceSet{},
+		productions: map[string]*Production{},
+	}
+	for _, prod := range grammar.Productions {
+		a.productions[prod.Name] = prod
+		a.First[prod.Name] = NewSequenceSet()
+		a.Follow[prod.Name] = NewSequenceSet()
+	}
+	return a
+}
+
+// FirstOf provides the FIRST_k set of the production with the given name.
+func (a *Analysis) FirstOf(name string) *SequenceSet {
+	if set, exists := a.First[name]; exists {
+		return set
+	}
+	return NewSequenceSet()
+}
+
+// FollowOf provides the FOLLOW_k set of the production with the given name.
+func (a *Analysis) FollowOf(name string) *SequenceSet {
+	if set, exists := a.Follow[name]; exists {
+		return set
+	}
+	return NewSequenceSet()
+}
+
+// IsNullable determines whether the production with the given name
+// can derive the empty string.
+func (a *Analysis) IsNullable(name string) bool {
+	return a.FirstOf(name).Contains(TerminalSequence{})
+}
+
+// FirstOfRule provides the FIRST_k set of the given sequence of right-hand side symbols.
+func (a *Analysis) FirstOfRule(rule []RHSRuleSymbol) *SequenceSet {
+	return a.firstOfSequence(flattenRule(rule), nil)
+}
+
+// FirstOfAlternative provides the FIRST_k set of the right-hand side
+// alternative at the given index of the named production.
+func (a *Analysis) FirstOfAlternative(name string, alternative int) *SequenceSet {
+	prod, exists := a.productions[name]
+	if !exists || alternative < 0 || alternative >= len(prod.RHS) {
+		return NewSequenceSet()
+	}
+	return a.FirstOfRule(prod.RHS[alternative])
+}
+
+// PredictOf provides the set of lookahead sequences which select the
+// right-hand side alternative at the given index of the named production,
+// that is FIRST_k of the alternative followed by FOLLOW_k of the production.
+func (a *Analysis) PredictOf(name string, alternative int) *SequenceSet {
+	prod, exists := a.productions[name]
+	if !exists || alternative < 0 || alternative >= len(prod.RHS) {
+		return NewSequenceSet()
+	}
+	return a.firstOfSequence(flattenRule(prod.RHS[alternative]), a.FollowOf(name))
+}
+
+// Computes the FIRST_k set of every production, the productions in
+// known already hold their complete set so their rules are skipped.
+func (a *Analysis) computeFirst(grammar *Grammar, known map[string]bool) {
+	changed := true
+	for changed {
+		changed = false
+		for _, prod := range grammar.Productions {
+			if known[prod.Name] {
+				continue
+			}
+			for _, rule := range prod.RHS {
+				if a.First[prod.Name].AddAll(a.firstOfSequence(flattenRule(rule), nil)) {
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// Computes the FOLLOW_k set of every production, the productions in known
+// already hold their complete set so nothing is added to them.
+func (a *Analysis) computeFollow(grammar *Grammar, known map[string]bool) {
+	for _, start := range a.Starts {
+		if set, exists := a.Follow[start]; exists {
+			set.Add(TerminalSequence{EndOfInput})
+		}
+	}
+	changed := true
+	for changed {
+		changed = false
+		for _, prod := range grammar.Productions {
+			for _, rule := range prod.RHS {
+				flattened := flattenRule(rule)
+				for i, symbol := range flattened {
+					if _, isNonTerminal := symbol.(*NonTerminalRHSRuleSymbol); isNonTerminal && !known[symbol.Name()] {
+						if follow, exists := a.Follow[symbol.Name()]; exists {
+							trailer := a.firstOfSequence(flattened[i+1:], a.Follow[prod.Name])
+							if follow.AddAll(trailer) {
+								changed = true
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// Computes FIRST_k of the given flattened sequence of symbols followed by
+// the provided tail set, where a nil tail represents the empty string.
+func (a *Analysis) firstOfSequence(symbols []RHSRuleSymbol, tail *SequenceSet) *SequenceSet {
+	result := NewSequenceSet(TerminalSequence{})
+	i := 0
+	for i < len(symbols) && !isComplete(result, a.K) {
+		symbol := symbols[i]
+		switch s := symbol.(type) {
+		case *TerminalRHSRuleSymbol:
+			if s.name != Epsilon {
+				result = concatK(result, NewSequenceSet(TerminalSequence{s.name}), a.K)
+			}
+		case *NonTerminalRHSRuleSymbol:
+			first, exists := a.First[s.name]
+			if !exists {
+				// Non-terminals without a production derive nothing,
+				// BuildParseTable reports them as undefined.
+				first = NewSequenceSet()
+			}
+			result = concatK(result, first, a.K)
+		case *LookaheadRHSRuleSymbol:
+			// A lookahead restricts the sequences that can follow it
+			// so everything after it is computed and filtered at once.
+			rest := a.firstOfSequence(symbols[i+1:], tail)
+			return concatK(result, excludeLookaheads(rest, s.params), a.K)
+		}
+		// Exclusions such as [no LineTerminator here] are zero-width
+		// as far as terminal sequences are concerned.
+		i++
+	}
+	if tail != nil {
+		result = concatK(result, tail, a.K)
+	}
+	return result
+}
+
+// Flattens conditional symbols into their parts so the resulting rule only holds
+// plain symbols. The conditions are not evaluated, so in a grammar whose parameters
+// have not been expanded each conditional part is treated as present and the sets
+// computed are a superset. Transform expands the parameters first, which leaves
+// no conditional symbols in the grammars parse tables are built from.
+func flattenRule(rule []RHSRuleSymbol) []RHSRuleSymbol {
+	flattened := []RHSRuleSymbol{}
+	for _, symbol := range rule {
+		if conditional, isCond := symbol.(*ConditionalRHSRuleSymbol); isCond {
+			flattened = append(flattened, flattenRule(conditional.Parts)...)
+		} else {
+			flattened = append(flattened, symbol)
+		}
+	}
+	return flattened
+}
+
+// Determines whether every sequence in the set
+// has already reached the length k.
+func isComplete(set *SequenceSet, k int) bool {
+	complete := true
+	i := 0
+	for complete && i < len(set.Sequences) {
+		if len(set.Sequences[i]) < k {
+			complete = false
+		}
+		i++
+	}
+	return complete
+}
+
+// Provides the k-length concatenation of the two sets of sequences.
+func concatK(left *SequenceSet, right *SequenceSet, k int) *SequenceSet {
+	result := NewSequenceSet()
+	for _, l := range left.Sequences {
+		if len(l) >= k {
+			result.Add(l)
+		} else {
+			for _, r := range right.Sequences {
+				seq := make(TerminalSequence, 0, k)
+				seq = append(seq, l...)
+				for j := 0; j < len(r) && len(seq) < k; j++ {
+					seq = append(seq, r[j])
+				}
+				result.Add(seq)
+			}
+		}
+	}
+	return result
+}
+
+// Removes the sequences which start with one of the excluded
+// sequences of a lookahead.
+// Exclusions longer than the sequences can not be decided here
+// so those sequences are retained. Neither can exclusions with a
+// [no LineTerminator here] restriction, such as async [no LineTerminator here]
+// function, as the terminal sequences do not record where line terminators are
+// and async followed by a line terminator and function is not excluded.
+// The sets are a superset for these, the predicates compiled into
+// the parse table decide them once the line terminators are known.
+func excludeLookaheads(set *SequenceSet, params *LaRHSParams) *SequenceSet {
+	if params == nil || len(params.Exclude) == 0 {
+		return set
+	}
+	exclusions := [][]string{}
+	for _, exclusion := range LookaheadTerminals(params) {
+		if !hasLineTerminatorRestriction(exclusion) {
+			exclusions = append(exclusions, terminalNames(exclusion))
+		}
+	}
+	filtered := NewSequenceSet()
+	for _, seq := range set.Sequences {
+		excluded := false
+		i := 0
+		for !excluded && i < len(exclusions) {
+			if len(exclusions[i]) > 0 && seq.HasPrefix(exclusions[i]) {
+				excluded = true
+			}
+			i++
+		}
+		if !excluded {
+			filtered.Add(seq)
+		}
+	}
+	return filtered
+}
+
+// LookaheadTerminals provides the terminals of each excluded sequence of a lookahead.
+// Placeholders such as [no LineTerminator here] do not consume a terminal, so they are
+// kept as the restriction of the terminal which follows them instead.
+func LookaheadTerminals(params *LaRHSParams) [][]*PredicateTerminal {
+	sequences := [][]*PredicateTerminal{}
+	if params == nil {
+		return sequences
+	}
+	for _, exclusion := range params.Exclude {
+		sequence := []*PredicateTerminal{}
+		noLineTerminator := false
+		for _, symbol := range exclusion {
+			if exclude, isExclude := symbol.(*ExcludeRHSRuleSymbol); isExclude {
+				noLineTerminator = exclude.name == lineTerminator
+			} else {
+				sequence = append(sequence, &PredicateTerminal{Name: symbol.Name(), NoLineTerminator: noLineTerminator})
+				noLineTerminator = false
+			}
+		}
+		sequences = append(sequences, sequence)
+	}
+	return sequences
+}
+
+// Determines whether a terminal of the excluded sequence
+// is only matched when no line terminator precedes it.
+func hasLineTerminatorRestriction(exclusion []*PredicateTerminal) bool {
+	restricted := false
+	i := 0
+	for !restricted && i < len(exclusion) {
+		restricted = exclusion[i].NoLineTerminator
+		i++
+	}
+	return restricted
+}
+
+// Provides the names of the terminals of the excluded sequence.
+func terminalNames(exclusion []*PredicateTerminal) []string {
+	names := []string{}
+	for _, terminal := range exclusion {
+		names = append(names, terminal.Name)
+	}
+	return names
+}
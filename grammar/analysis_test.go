@@ -0,0 +1,90 @@
+package grammar
+
+import (
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestAnalyseFirstAndFollow(t *testing.T) {
+	_, grammar := loadGrammarFixture("elr1")
+	analysis := Analyse(grammar, 1)
+	assertSequences(t, "FIRST(E)", analysis.FirstOf("E"), "(", "id")
+	assertSequences(t, "FIRST(E')", analysis.FirstOf("E'"), "+", "[empty]")
+	assertSequences(t, "FIRST(T')", analysis.FirstOf("T'"), "*", "[empty]")
+	assertSequences(t, "FOLLOW(E)", analysis.FollowOf("E"), ")", "[eoi]")
+	assertSequences(t, "FOLLOW(T)", analysis.FollowOf("T"), "+", ")", "[eoi]")
+	assertSequences(t, "FOLLOW(F)", analysis.FollowOf("F"), "*", "+", ")", "[eoi]")
+	if !analysis.IsNullable("E'") || analysis.IsNullable("E") {
+		t.Error("Expected E' to be nullable and E not to be nullable")
+	}
+	assertSequences(t, "PREDICT(E', 1)", analysis.PredictOf("E'", 1), ")", "[eoi]")
+}
+
+func TestAnalyseFirstK(t *testing.T) {
+	_, grammar := loadGrammarFixture("elr1")
+	analysis := Analyse(grammar, 2)
+	assertSequences(t, "FIRST2(E)", analysis.FirstOf("E"), "( (", "( id", "id +", "id *", "id")
+	assertSequences(t, "FOLLOW2(F)", analysis.FollowOf("F"),
+		"* (", "* id", "+ (", "+ id", ") *", ") +", ") )", ") [eoi]", "[eoi]")
+}
+
+func TestAnalyseLookaheadAndConditionals(t *testing.T) {
+	input := []byte(`
+<A>:
+  rhs:
+    -
+      - <*Lookahead*>:
+          params:
+            exclude:
+              - ['{']
+              - [let, '[']
+              - [async, <!LineTerminator!>, function]
+      - <B>
+    -
+      - <*Conditional*>:
+          params:
+            conditions: [+Default]
+          parts:
+            - function
+            - <C>
+<B>:
+  rhs:
+    - ['{']
+    - [let, x]
+    - [let, '[']
+    - [async, function]
+    - [async, x]
+<C>:
+  rhs:
+    - ['[empty]']
+    - ['(']
+`)
+	grammar := &Grammar{}
+	err := yaml.Unmarshal(input, grammar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis := Analyse(grammar, 2)
+	// The terminal sequences do not record line terminators, so async function is kept
+	// as async followed by a line terminator and function is not excluded.
+	assertSequences(t, "FIRST2(A)", analysis.FirstOf("A"),
+		"let x", "async function", "async x", "function", "function (")
+	assertSequences(t, "FOLLOW2(C)", analysis.FollowOf("C"), "[eoi]")
+}
+
+func assertSequences(t *testing.T, label string, set *SequenceSet, expected ...string) {
+	actual := set.Strings()
+	expectedSet := NewSequenceSet()
+	for _, seq := range expected {
+		if seq == Epsilon {
+			expectedSet.Add(TerminalSequence{})
+		} else {
+			expectedSet.Add(TerminalSequence(strings.Fields(seq)))
+		}
+	}
+	if expectedSet.Len() != set.Len() || expectedSet.Intersect(set).Len() != set.Len() {
+		t.Errorf("Expected %v to be %v but got %v", label, expected, actual)
+	}
+}
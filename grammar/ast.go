@@ -0,0 +1,494 @@
+package grammar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// DropField marks a symbol of an annotated alternative
+	// which is left out of the abstract syntax tree.
+	DropField = "_"
+	// FlattenField marks a symbol of an annotated alternative which builds no
+	// node of its own, the values built for the symbol become values of the alternative.
+	FlattenField = "..."
+	// ListFieldSuffix marks a field of a node which collects the values
+	// built for every symbol assigned to it rather than a single value.
+	ListFieldSuffix = "[]"
+)
+
+var (
+	// ErrMalformedAnnotation provides the error for the case when the
+	// AST annotations of a production are not of the expected form.
+	ErrMalformedAnnotation = errors.New("malformed AST annotation")
+	// ErrConflictingField provides the error for the case when a field of a node
+	// is a single value in one annotated alternative and a list in another.
+	ErrConflictingField = errors.New("conflicting AST node field")
+)
+
+var fieldPattern = regexp.MustCompile("^[A-Za-z]\\w*$")
+
+// ASTMapping provides the annotation of a right-hand side rule which describes
+// the abstract syntax tree node built when the rule is parsed.
+type ASTMapping struct {
+	// Node holds the type of node built, empty when the rule builds no node of its
+	// own and provides the values of the symbols it flattens instead.
+	Node string
+	// Fields holds an entry for each symbol of the rule, lookahead restrictions and
+	// exclusions are not counted while the parts of a conditional are counted individually.
+	// An entry is the name of the field of the node the symbol is kept in, suffixed with
+	// ListFieldSuffix for a list, DropField or FlattenField. Every symbol is dropped
+	// when no fields are provided.
+	Fields []string
+}
+
+// AnnotatedAlternative provides an annotated right-hand side rule of a production
+// of the source grammar along with the symbols its fields refer to.
+type AnnotatedAlternative struct {
+	Production  string
+	Alternative int
+	// Rule holds the rule in the notation of the ECMAScript specification.
+	Rule    string
+	Mapping *ASTMapping
+	// Symbols holds the symbols the fields of the mapping refer to in order.
+	Symbols []RHSRuleSymbol
+	// Optional determines for each symbol whether it can be
+	// left out of the rule once optional symbols have been expanded.
+	Optional []bool
+}
+
+// ASTTable provides what is needed to generate the code which builds the
+// abstract syntax tree from a parse tree of the LL(1) parse table, the helper
+// productions introduced by LLkify are skipped over so each node is built from
+// the alternative of the source grammar that was parsed.
+type ASTTable struct {
+	// Nodes holds each type of node in the order it is first annotated.
+	Nodes []*ASTNode
+	// Alternatives holds each annotated alternative a builder is generated for.
+	Alternatives []*AnnotatedAlternative
+	// Rules maps the index of each rule of the parse table which completes an
+	// annotated alternative to how the symbols parsed for it map to the alternative.
+	Rules map[int]*ASTRule
+	// Helpers maps each helper production to the transformation which introduced it.
+	Helpers map[string]string
+}
+
+// ASTNode provides a type of node of the abstract syntax tree.
+type ASTNode struct {
+	Name   string
+	Fields []*ASTField
+}
+
+// ASTField provides a field of a type of node.
+type ASTField struct {
+	Name string
+	List bool
+}
+
+// ASTRule provides how the symbols parsed for an annotated alternative map to
+// the symbols of the alternative, which can be spread across the rule and the rules
+// of the left-factoring helper productions it is reached through.
+type ASTRule struct {
+	// Builder holds the index of the annotated alternative in Alternatives.
+	Builder int
+	// Length holds the number of symbols of the annotated alternative.
+	Length int
+	// Positions holds the position in the annotated alternative of each symbol parsed,
+	// for an alternative which continues a left recursive production the production
+	// itself is at position 0 and is not parsed.
+	Positions []int
+}
+
+// CollectAnnotations provides the annotated alternatives of the grammar in the order
+// they appear, which have to be collected before the grammar is transformed.
+func CollectAnnotations(grammar *Grammar) []*AnnotatedAlternative {
+	annotations := []*AnnotatedAlternative{}
+	for _, prod := range grammar.Productions {
+		for i, mapping := range prod.AST {
+			if mapping != nil && i < len(prod.RHS) {
+				symbols, optional := fieldSymbols(prod.RHS[i])
+				annotations = append(annotations, &AnnotatedAlternative{
+					Production:  prod.Name,
+					Alternative: i,
+					Rule:        ruleText(prod.RHS[i]),
+					Mapping:     mapping,
+					Symbols:     symbols,
+					Optional:    optional,
+				})
+			}
+		}
+	}
+	return annotations
+}
+
+// BuildASTTable deals with mapping the rules of the parse table built for the
+// transformed grammar back to the annotated alternatives of the source grammar
+// they were derived from. Rules which can not be traced back to an annotated
+// alternative build the values of the non-terminals they contain.
+func BuildASTTable(annotations []*AnnotatedAlternative, grammar *Grammar, table *ParseTable) (*ASTTable, error) {
+	astTable := &ASTTable{
+		Alternatives: annotations,
+		Rules:        map[int]*ASTRule{},
+		Helpers:      map[string]string{},
+	}
+	if err := astTable.collectNodes(); err != nil {
+		return nil, err
+	}
+	origins := map[string]string{}
+	for _, prod := range grammar.Productions {
+		origins[prod.Name] = prod.OriginName()
+		if helper := prod.Helper(); helper != "" {
+			astTable.Helpers[prod.Name] = helper
+		}
+	}
+	rules := map[string][]int{}
+	for i, rule := range table.Rules {
+		rules[rule.Production] = append(rules[rule.Production], i)
+	}
+	visited := map[int]bool{}
+	var walk func(root *Production, prefix []string, ruleIndex int)
+	walk = func(root *Production, prefix []string, ruleIndex int) {
+		if visited[ruleIndex] {
+			return
+		}
+		visited[ruleIndex] = true
+		symbols := table.Rules[ruleIndex].Symbols
+		if n := len(symbols); n > 0 && astTable.Helpers[symbols[n-1]] == LeftFactorTransform {
+			// The rest of the alternative is parsed by the rules of the helper.
+			for _, next := range rules[symbols[n-1]] {
+				walk(root, append(append([]string{}, prefix...), symbols[:n-1]...), next)
+			}
+			return
+		}
+		sequence := append(append([]string{}, prefix...), symbols...)
+		if n := len(sequence); n > 0 && astTable.Helpers[sequence[n-1]] == EliminateLeftRecursionTransform {
+			sequence = sequence[:n-1]
+		}
+		start := 0
+		if root.Helper() == EliminateLeftRecursionTransform {
+			start = 1
+		}
+		if astRule := astTable.align(table.Rules[ruleIndex].Provenance, sequence, start, origins); astRule != nil {
+			astTable.Rules[ruleIndex] = astRule
+		}
+	}
+	for _, prod := range grammar.Productions {
+		if prod.Helper() != LeftFactorTransform {
+			for _, ruleIndex := range rules[prod.Name] {
+				walk(prod, []string{}, ruleIndex)
+			}
+		}
+	}
+	return astTable, nil
+}
+
+// Deals with collecting the fields of each type of node
+// from the annotated alternatives which build it.
+func (t *ASTTable) collectNodes() error {
+	nodes := map[string]*ASTNode{}
+	for _, alternative := range t.Alternatives {
+		if alternative.Mapping.Node == "" {
+			continue
+		}
+		node, exists := nodes[alternative.Mapping.Node]
+		if !exists {
+			node = &ASTNode{Name: alternative.Mapping.Node}
+			nodes[node.Name] = node
+			t.Nodes = append(t.Nodes, node)
+		}
+		for _, field := range alternative.Mapping.Fields {
+			if field == DropField || field == FlattenField {
+				continue
+			}
+			name := strings.TrimSuffix(field, ListFieldSuffix)
+			list := strings.HasSuffix(field, ListFieldSuffix)
+			found := false
+			i := 0
+			for !found && i < len(node.Fields) {
+				if node.Fields[i].Name == name {
+					found = true
+				} else {
+					i++
+				}
+			}
+			if !found {
+				node.Fields = append(node.Fields, &ASTField{Name: name, List: list})
+			} else if node.Fields[i].List != list {
+				return &BuildError{Production: alternative.Production, Err: fmt.Errorf(
+					"%w: %v.%v is a single value in one alternative and a list in another",
+					ErrConflictingField, node.Name, name,
+				)}
+			}
+		}
+	}
+	return nil
+}
+
+// Provides how the sequence of symbols parsed for the alternative the provenance refers to
+// maps to the symbols of the alternative, starting from the given position. Symbols are
+// matched by the production they were instantiated from and optional symbols of the
+// alternative which were not parsed are skipped. Nil when the alternative is not
+// annotated or the symbols do not match.
+func (t *ASTTable) align(provenance *Provenance, sequence []string, start int, origins map[string]string) *ASTRule {
+	if provenance == nil || provenance.Rule == "" {
+		return nil
+	}
+	found := false
+	index := 0
+	for !found && index < len(t.Alternatives) {
+		alternative := t.Alternatives[index]
+		if alternative.Production == provenance.Production && alternative.Alternative == provenance.Alternative {
+			found = true
+		} else {
+			index++
+		}
+	}
+	if !found {
+		return nil
+	}
+	alternative := t.Alternatives[index]
+	originOf := func(name string) string {
+		if origin, exists := origins[name]; exists {
+			return origin
+		}
+		return name
+	}
+	positions := []int{}
+	position := start
+	for _, name := range sequence {
+		for position < len(alternative.Symbols) && alternative.Optional[position] &&
+			alternative.Symbols[position].Name() != originOf(name) {
+			position++
+		}
+		if position == len(alternative.Symbols) || alternative.Symbols[position].Name() != originOf(name) {
+			return nil
+		}
+		positions = append(positions, position)
+		position++
+	}
+	for position < len(alternative.Symbols) {
+		if !alternative.Optional[position] {
+			return nil
+		}
+		position++
+	}
+	return &ASTRule{Builder: index, Length: len(alternative.Symbols), Positions: positions}
+}
+
+// Provides the symbols of a rule the fields of an annotation refer to along with
+// whether each of them is optional, lookahead restrictions and exclusions
+// are left out and the parts of conditionals are included individually.
+func fieldSymbols(rule []RHSRuleSymbol) ([]RHSRuleSymbol, []bool) {
+	symbols := []RHSRuleSymbol{}
+	optional := []bool{}
+	for _, symbol := range flattenRule(rule) {
+		switch s := symbol.(type) {
+		case *NonTerminalRHSRuleSymbol:
+			symbols = append(symbols, s)
+			optional = append(optional, s.params != nil && s.params.Optional != nil && *s.params.Optional)
+		case *TerminalRHSRuleSymbol:
+			if s.name != Epsilon {
+				symbols = append(symbols, s)
+				optional = append(optional, false)
+			}
+		}
+	}
+	return symbols, optional
+}
+
+// Deals with extracting the AST annotations of the production, one for each
+// right-hand side rule which is either null or a map of the node and fields.
+func (p *Production) extractASTMappings(astIface interface{}) error {
+	items, isList := astIface.([]interface{})
+	if !isList || len(items) != len(p.RHS) {
+		return fmt.Errorf(
+			"%w: ast must be a list with an annotation for each of the %v alternatives",
+			ErrMalformedAnnotation, len(p.RHS),
+		)
+	}
+	for i, item := range items {
+		if item == nil {
+			p.AST = append(p.AST, nil)
+			continue
+		}
+		mapping, err := extractASTMapping(item, p.RHS[i])
+		if err != nil {
+			return fmt.Errorf("alternative %v: %w", i, err)
+		}
+		p.AST = append(p.AST, mapping)
+	}
+	return nil
+}
+
+// Deals with extracting the annotation of a single right-hand side rule
+// and ensuring its fields can be mapped to the symbols of the rule.
+func extractASTMapping(item interface{}, rule []RHSRuleSymbol) (*ASTMapping, error) {
+	mapSlc, isMap := item.(yaml.MapSlice)
+	if !isMap {
+		return nil, fmt.Errorf("%w: expected null or a map of node and fields", ErrMalformedAnnotation)
+	}
+	err := checkKeys(mapSlc, ErrMalformedAnnotation, "the annotation", "node", "fields")
+	if err != nil {
+		return nil, err
+	}
+	mapping := &ASTMapping{}
+	if node := get(mapSlc, "node"); node != nil {
+		name, isStr := node.(string)
+		if !isStr || !fieldPattern.MatchString(name) {
+			return nil, fmt.Errorf("%w: the node %v is not a valid type name", ErrMalformedAnnotation, node)
+		}
+		mapping.Node = name
+	}
+	mapping.Fields, err = extractStringList(get(mapSlc, "fields"), ErrMalformedAnnotation, "fields")
+	if err != nil {
+		return nil, err
+	}
+	symbols, _ := fieldSymbols(rule)
+	if mapping.Fields != nil && len(mapping.Fields) != len(symbols) {
+		return nil, fmt.Errorf(
+			"%w: %v fields were given for %v symbols", ErrMalformedAnnotation, len(mapping.Fields), len(symbols),
+		)
+	}
+	assigned := map[string]bool{}
+	for _, field := range mapping.Fields {
+		switch {
+		case field == DropField:
+		case field == FlattenField:
+			if mapping.Node != "" {
+				return nil, fmt.Errorf("%w: %v can not be used along with a node", ErrMalformedAnnotation, FlattenField)
+			}
+		case !fieldPattern.MatchString(strings.TrimSuffix(field, ListFieldSuffix)):
+			return nil, fmt.Errorf("%w: %v is not a valid field name", ErrMalformedAnnotation, field)
+		case mapping.Node == "":
+			return nil, fmt.Errorf("%w: the field %v requires a node", ErrMalformedAnnotation, field)
+		case assigned[field] && !strings.HasSuffix(field, ListFieldSuffix):
+			return nil, fmt.Errorf("%w: the field %v is assigned more than once", ErrMalformedAnnotation, field)
+		}
+		assigned[field] = true
+	}
+	return mapping, nil
+}
+
+// Provides the YAML representation of the AST annotations of a production.
+func marshalASTMappings(mappings []*ASTMapping) []interface{} {
+	items := []interface{}{}
+	for _, mapping := range mappings {
+		if mapping == nil {
+			items = append(items, nil)
+			continue
+		}
+		item := yaml.MapSlice{}
+		if mapping.Node != "" {
+			item = append(item, yaml.MapItem{Key: "node", Value: mapping.Node})
+		}
+		if mapping.Fields != nil {
+			item = append(item, yaml.MapItem{Key: "fields", Value: mapping.Fields})
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// Deals with writing the fields of the generated parse tables which tell
+// the parser how to build the abstract syntax tree from a parse tree.
+func writeASTTables(output *bytes.Buffer, table *ParseTable, symbols map[string]string) {
+	output.WriteString("Helpers: map[Symbol]HelperKind{\n")
+	for _, name := range table.NonTerminals {
+		switch table.AST.Helpers[name] {
+		case LeftFactorTransform:
+			fmt.Fprintf(output, "%v: FactorHelper,\n", symbols[name])
+		case EliminateLeftRecursionTransform:
+			fmt.Fprintf(output, "%v: RecursionHelper,\n", symbols[name])
+		}
+	}
+	output.WriteString("},\nASTRules: map[int]*ASTRule{\n")
+	for i := range table.Rules {
+		if rule, exists := table.AST.Rules[i]; exists {
+			fmt.Fprintf(
+				output, "%v: {Builder: %v, Length: %v, Positions: []int{%v}},\n",
+				i, rule.Builder, rule.Length, joinInts(rule.Positions),
+			)
+		}
+	}
+	output.WriteString("},\nASTBuilders: []func([][]Node) []Node{\n")
+	for i := range table.AST.Alternatives {
+		fmt.Fprintf(output, "buildAST%v,\n", i)
+	}
+	output.WriteString("},\n")
+}
+
+// Deals with writing the types of the nodes of the abstract syntax tree along with
+// a function for each annotated alternative which builds its values from the values
+// built for each of its symbols.
+func writeASTSource(output *bytes.Buffer, astTable *ASTTable) {
+	for _, node := range astTable.Nodes {
+		fmt.Fprintf(output, "\n// %v provides a node of the abstract syntax tree.\ntype %v struct {\n", node.Name, node.Name)
+		for _, field := range node.Fields {
+			fieldType := "Node"
+			if field.List {
+				fieldType = "[]Node"
+			}
+			fmt.Fprintf(output, "%v %v\n", exportedName(field.Name), fieldType)
+		}
+		output.WriteString("}\n")
+	}
+	for i, alternative := range astTable.Alternatives {
+		fmt.Fprintf(
+			output, "\n// Builds <%v>: %v\nfunc buildAST%v(values [][]Node) []Node {\n",
+			alternative.Production, strings.ReplaceAll(alternative.Rule, "\n", " "), i,
+		)
+		mapping := alternative.Mapping
+		if mapping.Node == "" {
+			flattened := []string{}
+			for j, field := range mapping.Fields {
+				if field == FlattenField {
+					flattened = append(flattened, "values["+strconv.Itoa(j)+"]")
+				}
+			}
+			fmt.Fprintf(output, "return concatNodes(%v)\n}\n", strings.Join(flattened, ", "))
+			continue
+		}
+		// Holds the positions of the symbols assigned to each field in the order the fields are first assigned.
+		names := []string{}
+		assigned := map[string][]string{}
+		for j, field := range mapping.Fields {
+			if field != DropField {
+				name := strings.TrimSuffix(field, ListFieldSuffix)
+				if _, exists := assigned[name]; !exists {
+					names = append(names, name)
+				}
+				assigned[name] = append(assigned[name], "values["+strconv.Itoa(j)+"]")
+			}
+		}
+		values := []string{}
+		for _, name := range names {
+			if strings.HasSuffix(fieldOf(mapping, name), ListFieldSuffix) {
+				values = append(values, fmt.Sprintf("%v: concatNodes(%v)", exportedName(name), strings.Join(assigned[name], ", ")))
+			} else {
+				values = append(values, fmt.Sprintf("%v: firstNode(%v)", exportedName(name), assigned[name][0]))
+			}
+		}
+		fmt.Fprintf(output, "return []Node{&%v{%v}}\n}\n", mapping.Node, strings.Join(values, ", "))
+	}
+}
+
+// Provides the entry of the fields of the mapping which assigns the named field.
+func fieldOf(mapping *ASTMapping, name string) string {
+	for _, field := range mapping.Fields {
+		if strings.TrimSuffix(field, ListFieldSuffix) == name {
+			return field
+		}
+	}
+	return ""
+}
+
+// Provides the name of a field of a generated node which is exported.
+func exportedName(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
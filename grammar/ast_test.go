@@ -0,0 +1,141 @@
+package grammar
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const astTestGrammar = "<Script>:\n" +
+	"  rhs:\n" +
+	"    - [<Expr>]\n" +
+	"<Expr>:\n" +
+	"  rhs:\n" +
+	"    - [<Expr>, '+', <Term>]\n" +
+	"    - [<Term>]\n" +
+	"  ast:\n" +
+	"    - {node: Binary, fields: [left, op, right]}\n" +
+	"    - {fields: ['...']}\n" +
+	"<Term>:\n" +
+	"  rhs:\n" +
+	"    - [IdentifierName, '(', {<Args>: {params: {optional: true}}}, ')']\n" +
+	"    - [IdentifierName]\n" +
+	"  ast:\n" +
+	"    - {node: Call, fields: [callee, _, 'arguments[]', _]}\n" +
+	"    - {node: Identifier, fields: [name]}\n" +
+	"<Args>:\n" +
+	"  rhs:\n" +
+	"    - [<Args>, ',', IdentifierName]\n" +
+	"    - [IdentifierName]\n" +
+	"  ast:\n" +
+	"    - {fields: ['...', _, '...']}\n" +
+	"    - ~\n"
+
+func TestLoadASTAnnotations(t *testing.T) {
+	grammar := loadTestGrammar(t, astTestGrammar)
+	expected := []*ASTMapping{
+		{Node: "Call", Fields: []string{"callee", DropField, "arguments[]", DropField}},
+		{Node: "Identifier", Fields: []string{"name"}},
+	}
+	if !reflect.DeepEqual(grammar.Productions[2].AST, expected) {
+		t.Errorf("Expected the annotations %v but got %v", expected, grammar.Productions[2].AST)
+	}
+	if grammar.Productions[3].AST[1] != nil {
+		t.Errorf("Expected the second alternative of <Args> not to be annotated")
+	}
+	assertRoundTrip(t, grammar)
+}
+
+func TestLoadMalformedASTAnnotations(t *testing.T) {
+	for _, annotations := range []string{
+		"[{node: Pair}]",
+		"[{node: Pair, fields: [first]}, ~]",
+		"[{node: Pair, fields: [first, '...']}, ~]",
+		"[{fields: [first, second]}, ~]",
+		"[{node: Pair, fields: [first, first]}, ~]",
+		"[{node: 'not a type', fields: [first, second]}, ~]",
+		"[{node: Pair, fields: [first, '2nd']}, ~]",
+		"[{node: Pair, kind: pair}, ~]",
+	} {
+		input := "<Pair>:\n  rhs:\n    - [a, b]\n    - [c]\n  ast: " + annotations + "\n"
+		_, err := LoadBytes([]byte(input), "yaml", "test.yml")
+		if !errors.Is(err, ErrMalformedAnnotation) {
+			t.Errorf("Expected %v to be a malformed annotation but got %v", annotations, err)
+		}
+	}
+}
+
+func TestBuildASTTable(t *testing.T) {
+	artefacts, err := BuildBytes([]byte(astTestGrammar), &BuildOptions{Package: "parser", File: "ast.yml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	astTable := artefacts.Table.AST
+	nodes := []string{}
+	for _, node := range astTable.Nodes {
+		fields := []string{}
+		for _, field := range node.Fields {
+			if field.List {
+				fields = append(fields, field.Name+ListFieldSuffix)
+			} else {
+				fields = append(fields, field.Name)
+			}
+		}
+		nodes = append(nodes, node.Name+"{"+strings.Join(fields, " ")+"}")
+	}
+	expectedNodes := []string{"Binary{left op right}", "Call{callee arguments[]}", "Identifier{name}"}
+	if !reflect.DeepEqual(nodes, expectedNodes) {
+		t.Errorf("Expected the nodes %v but got %v", expectedNodes, nodes)
+	}
+	rules := map[string]*ASTRule{}
+	for i, rule := range artefacts.Table.Rules {
+		if astRule, exists := astTable.Rules[i]; exists {
+			rules[rule.Production+": "+strings.Join(rule.Symbols, " ")] = astRule
+		}
+	}
+	expectedRules := map[string]*ASTRule{
+		"Expr: Term Expr'":              {Builder: 1, Length: 1, Positions: []int{0}},
+		"Expr': + Term Expr'":           {Builder: 0, Length: 3, Positions: []int{1, 2}},
+		"TermA0: ":                      {Builder: 3, Length: 1, Positions: []int{0}},
+		"TermA0A0: Args )":              {Builder: 2, Length: 4, Positions: []int{0, 1, 2, 3}},
+		"TermA0A0: )":                   {Builder: 2, Length: 4, Positions: []int{0, 1, 3}},
+		"Args': , IdentifierName Args'": {Builder: 4, Length: 3, Positions: []int{1, 2}},
+	}
+	if !reflect.DeepEqual(rules, expectedRules) {
+		t.Errorf("Expected the AST rules %v but got %v", expectedRules, rules)
+	}
+	for _, expected := range []string{
+		"Helpers: map[Symbol]HelperKind{\n\t\t\tntSy2: RecursionHelper,",
+		"ASTBuilders: []func([][]Node) []Node{",
+		"type Call struct {\n\tCallee    Node\n\tArguments []Node\n}",
+		"return []Node{&Binary{Left: firstNode(values[0]), Op: firstNode(values[1]), Right: firstNode(values[2])}}",
+		"return []Node{&Call{Callee: firstNode(values[0]), Arguments: concatNodes(values[2])}}",
+		"// Builds <Args>: Args `,` IdentifierName\nfunc buildAST4(values [][]Node) []Node {\n\treturn concatNodes(values[0], values[2])",
+	} {
+		if !strings.Contains(string(artefacts.Source), expected) {
+			t.Errorf("Expected the generated output to contain %q but got\n%s", expected, artefacts.Source)
+		}
+	}
+}
+
+func TestBuildASTTableConflictingField(t *testing.T) {
+	input := "<A>:\n  rhs:\n    - [a, <B>]\n    - [b, <B>]\n  ast:\n" +
+		"    - {node: Pair, fields: [_, value]}\n    - {node: Pair, fields: [_, 'value[]']}\n" +
+		"<B>:\n  rhs:\n    - [c]\n"
+	_, err := BuildBytes([]byte(input), &BuildOptions{Package: "parser", File: "pair.yml"})
+	var buildErr *BuildError
+	if !errors.Is(err, ErrConflictingField) || !errors.As(err, &buildErr) || buildErr.File != "pair.yml" {
+		t.Errorf("Expected a conflicting field error in pair.yml but got %v", err)
+	}
+}
+
+func TestBuildWithoutASTAnnotations(t *testing.T) {
+	artefacts, err := BuildBytes([]byte("<E>:\n  rhs:\n    - [<E>, '+', id]\n    - [id]\n"), &BuildOptions{Package: "parser"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if artefacts.Table.AST != nil || strings.Contains(string(artefacts.Source), "ASTRules") {
+		t.Errorf("Expected no AST to be generated for a grammar without annotations")
+	}
+}
@@ -1,89 +1,481 @@
 package grammar
 
 import (
+	"bytes"
 	"fmt"
+	"go/format"
+	"io"
 	"io/ioutil"
-	"log"
 	"strconv"
-
-	yaml "gopkg.in/yaml.v2"
+	"strings"
 )
 
+// BuildOptions provides the options for building
+// the parse table source for a grammar.
+type BuildOptions struct {
+	// Format is the storage format of the grammar, one of yaml,
+	// json or ebnf which defaults to yaml.
+	Format string
+	// Package is the go package the generated source will belong to,
+	// which defaults to parser.
+	Package string
+	// File is the name of the grammar file used to describe
+	// where errors occurred.
+	File string
+	// Algorithm is the parse table construction algorithm,
+	// either ll or lalr which defaults to ll.
+	Algorithm string
+	// Supplemental is the file of the supplemental grammar which refines
+	// the cover productions of the grammar, stored in the same format.
+	Supplemental string
+	// CacheDir is the directory the artefacts of builds are cached in, when set
+	// a build with the same grammar and options as the last one reuses its source
+	// and otherwise only the analysis of the productions affected by an edit is computed.
+	CacheDir string
+	// Verify is true when the language of the grammar is checked against the language
+	// of the grammar it was transformed into before its parse table is built.
+	Verify bool
+	// VerifyLength provides the number of terminals of the longest sentence compared
+	// when verifying the transformed grammar. Defaults to DefaultEquivalenceLength.
+	VerifyLength int
+}
+
+// Provides the go package the generated source will belong to.
+func (o *BuildOptions) packageName() string {
+	if o.Package == "" {
+		return "parser"
+	}
+	return o.Package
+}
+
+// Artefacts holds everything produced from building a grammar.
+type Artefacts struct {
+	// Grammar holds the grammar once every transformation has been applied.
+	Grammar   *Grammar
+	Analysis  *Analysis
+	Conflicts []*Conflict
+	Table     *ParseTable
+	// LRTable and LRConflicts are produced in place of the LL(1)
+	// table and conflicts when the lalr algorithm is used.
+	LRTable     *LRTable
+	LRConflicts []*LRConflict
+	// Source holds the generated go source containing
+	// the symbols and the parse table.
+	Source []byte
+	// Cached is true when the source was taken from the build cache,
+	// in which case nothing else is produced.
+	Cached bool
+	// Recomputed holds the productions whose FIRST_k or FOLLOW_k sets
+	// were computed rather than taken from the build cache.
+	Recomputed []string
+	// Differences holds the sentences accepted by only one of the grammar and
+	// the transformed grammar, only checked when the build is verified.
+	Differences []*LanguageDifference
+}
+
 // Build deals with producing the symbols
 // and the parse table for the provided grammar
 // file in the specified file that will be a part of the package
 // specified.
-func Build(grammarFile string, format string, outputFile string, pkg string) {
-	switch format {
-	case "yaml":
-		buildGrammarYaml(grammarFile, outputFile, pkg)
+func Build(grammarFile string, format string, outputFile string, pkg string) error {
+	return BuildFile(grammarFile, outputFile, &BuildOptions{
+		Format:  format,
+		Package: pkg,
+	})
+}
+
+// BuildFile deals with producing the symbols and the parse table for
+// the provided grammar file in the given output file with the provided options.
+func BuildFile(grammarFile string, outputFile string, options *BuildOptions) error {
+	if options == nil {
+		options = &BuildOptions{}
+	}
+	data, err := ioutil.ReadFile(grammarFile)
+	if err != nil {
+		return &BuildError{File: grammarFile, Err: err}
+	}
+	fileOptions := *options
+	fileOptions.File = grammarFile
+	artefacts, err := BuildBytes(data, &fileOptions)
+	if err != nil {
+		return err
+	}
+	if existing, readErr := ioutil.ReadFile(outputFile); readErr == nil && bytes.Equal(existing, artefacts.Source) {
+		// Leave the output untouched so tools that watch it do not see a change.
+		return nil
 	}
+	err = ioutil.WriteFile(outputFile, artefacts.Source, 0644)
+	if err != nil {
+		return &BuildError{File: outputFile, Err: err}
+	}
+	return nil
+}
+
+// BuildFrom deals with building the parse table source
+// for the grammar read from the provided reader.
+func BuildFrom(r io.Reader, options *BuildOptions) (*Artefacts, error) {
+	if options == nil {
+		options = &BuildOptions{}
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, &BuildError{File: options.File, Err: err}
+	}
+	return BuildBytes(data, options)
 }
 
-func buildGrammarYaml(grammarFile string, outputFile string, pkg string) {
-	//output := "package " + pkg + "\n\n"
-	file, err := ioutil.ReadFile(grammarFile)
+// BuildBytes deals with building the parse table source for the grammar
+// in the given data, everything produced is returned in memory.
+// When the grammar has conflicts the artefacts produced up to
+// the point of detecting them are returned along with a *ConflictError.
+// Nil options are treated as the default options.
+func BuildBytes(data []byte, options *BuildOptions) (*Artefacts, error) {
+	if options == nil {
+		options = &BuildOptions{}
+	}
+	var supplemental []byte
+	var err error
+	if options.Supplemental != "" {
+		supplemental, err = ioutil.ReadFile(options.Supplemental)
+		if err != nil {
+			return nil, &BuildError{File: options.Supplemental, Err: err}
+		}
+	}
+	if options.CacheDir == "" {
+		return build(data, supplemental, options, nil)
+	}
+	cache, err := OpenBuildCache(options.CacheDir)
 	if err != nil {
-		errFmt := fmt.Errorf("failed to read the file %v containing"+
-			" the grammar in the YAML format\nerror: %v\n", grammarFile, err)
-		log.Fatal(errFmt)
+		return nil, &BuildError{File: options.CacheDir, Err: err}
 	}
-	grammar := &Grammar{}
-	err = yaml.Unmarshal(file, grammar)
+	key := BuildKey(data, supplemental, options)
+	if source, exists := cache.Source(key); exists {
+		return &Artefacts{Source: source, Cached: true}, nil
+	}
+	artefacts, err := build(data, supplemental, options, cache)
 	if err != nil {
-		errFmt := fmt.Errorf("failed to parse the yaml"+
-			" representation of the grammar\nerror: %v\n", err)
-		log.Fatal(errFmt)
+		return artefacts, err
+	}
+	if err = cache.StoreSource(key, artefacts.Source); err != nil {
+		return artefacts, &BuildError{File: options.CacheDir, Err: err}
 	}
+	return artefacts, nil
+}
+
+// Deals with building the parse table source for the grammar and supplemental
+// grammar in the given data, the analysis is taken from the cache when one is provided.
+func build(data []byte, supplemental []byte, options *BuildOptions, cache *BuildCache) (*Artefacts, error) {
+	format := options.Format
+	if format == "" {
+		format = "yaml"
+	}
+	grammar, err := LoadBytes(data, format, options.File)
+	if err != nil {
+		return nil, err
+	}
+	if options.Supplemental != "" {
+		var supplementalGrammar *Grammar
+		if supplementalGrammar, err = LoadBytes(supplemental, format, options.Supplemental); err != nil {
+			return nil, err
+		}
+		if err = Supplement(grammar, supplementalGrammar); err != nil {
+			return nil, err
+		}
+	}
+	switch options.Algorithm {
+	case "", "ll":
+	case "lalr":
+		return buildLALR(grammar, options)
+	default:
+		return nil, &BuildError{File: options.File, Err: fmt.Errorf("%w: %v", ErrUnknownAlgorithm, options.Algorithm)}
+	}
+	annotations := CollectAnnotations(grammar)
+	// Transformations replace the productions of the grammar rather
+	// than modifying them so the original productions are kept intact.
+	original := &Grammar{Productions: grammar.Productions}
+	Transform(grammar)
+	artefacts := &Artefacts{Grammar: grammar}
+	if options.Verify {
+		artefacts.Differences, err = CheckEquivalence(
+			original, grammar, StartSymbols(original), &EquivalenceOptions{MaxLength: options.VerifyLength},
+		)
+		if err != nil {
+			return artefacts, &BuildError{File: options.File, Err: err}
+		}
+		if len(artefacts.Differences) > 0 {
+			return artefacts, &EquivalenceError{Differences: artefacts.Differences}
+		}
+	}
+	if cache != nil {
+		artefacts.Analysis, artefacts.Recomputed, err = cache.Analyse(grammar, 1, GoalSymbols(grammar)...)
+		if err != nil {
+			return artefacts, &BuildError{File: cache.Dir, Err: err}
+		}
+	} else {
+		artefacts.Analysis = Analyse(grammar, 1, GoalSymbols(grammar)...)
+	}
+	table, err := BuildParseTable(grammar, artefacts.Analysis)
+	if buildErr, isBuildErr := err.(*BuildError); isBuildErr {
+		buildErr.File = options.File
+		return artefacts, buildErr
+	}
+	// The conflicts the lookahead restrictions compiled into the table resolve are allowed.
+	artefacts.Conflicts = detectConflicts(grammar, artefacts.Analysis, table)
+	if len(artefacts.Conflicts) > 0 {
+		return artefacts, &ConflictError{Conflicts: artefacts.Conflicts}
+	}
+	artefacts.Table = table
+	if len(annotations) > 0 {
+		artefacts.Table.AST, err = BuildASTTable(annotations, grammar, artefacts.Table)
+		if buildErr, isBuildErr := err.(*BuildError); isBuildErr {
+			buildErr.File = options.File
+			return artefacts, buildErr
+		}
+	}
+	artefacts.Source, err = generateGrammarOutput(artefacts.Table, options.packageName())
+	if err != nil {
+		return artefacts, &BuildError{File: options.File, Err: fmt.Errorf(
+			"failed to format the generated parse table source: %v", err,
+		)}
+	}
+	return artefacts, nil
+}
+
+// Deals with building the LALR(1) tables and their source for the loaded grammar,
+// only the parameters of the grammar are expanded as the LR construction
+// handles left recursion and optional symbols itself.
+func buildLALR(grammar *Grammar, options *BuildOptions) (*Artefacts, error) {
+	ExpandParameters(grammar)
+	artefacts := &Artefacts{Grammar: grammar}
+	artefacts.LRTable, artefacts.LRConflicts = BuildLALRTable(grammar, GoalSymbols(grammar)...)
+	if len(artefacts.LRConflicts) > 0 {
+		return artefacts, &ConflictError{LRConflicts: artefacts.LRConflicts}
+	}
+	var err error
+	artefacts.Source, err = generateLRTableOutput(artefacts.LRTable, options.packageName())
+	if err != nil {
+		return artefacts, &BuildError{File: options.File, Err: fmt.Errorf(
+			"failed to format the generated parse table source: %v", err,
+		)}
+	}
+	return artefacts, nil
+}
+
+// Transform deals with applying every transformation made to a grammar
+// before its parse table is built, parameters and optional symbols are expanded
+// and then left recursion is eliminated and common prefixes are factored out.
+func Transform(grammar *Grammar) {
+	ExpandParameters(grammar)
 	ExpandOptionals(grammar)
 	LLkify(grammar)
-	printGrammar(*grammar)
-	/*output += generateGrammarOutput(grammar)
-	// TODO: write to output file.
-	fmt.Println("Grammar output:")
-	fmt.Println()
-	fmt.Print(output)*/
 }
 
 // Deals with generating the parse symbol and table (LL(k)) output
-// as a string which can then be written to a file as source code.
-func generateGrammarOutput(grammar *Grammar) string {
-	output := "const (\n    _ Symbol = iota\n"
-	ntOutput := ""
-	tOutput := ""
-	ntSymbolNo := 0
-	tSymbolNo := 0
-	readTerminals := []string{}
-	for _, prod := range grammar.Productions {
-		ntOutput += "    ntSy" + strconv.Itoa(ntSymbolNo) + " // " + prod.Name + "\n"
-		ntSymbolNo++
-		if len(prod.Params) > 0 {
-			possibilities := []string{}
-			n := len(prod.Params)
-			// Holds temporary current combinations.
-			// (never should exceed 3 with ECMAScript grammar)
-			data := [3]string{}
-			combineParams(prod.Params, data, &possibilities, 0, n-1, 0, 2)
-			combineParams(prod.Params, data, &possibilities, 0, n-1, 0, 3)
-			possibilities = append(prod.Params, possibilities...)
-			for _, poss := range possibilities {
-				ntOutput += "    ntSy" + strconv.Itoa(ntSymbolNo) + " // " + prod.Name + "_" + poss + "\n"
-				ntSymbolNo++
+// as formatted source code which can then be written to a file.
+// The generated source populates the tables the parser
+// in the target package loads its parse table from.
+func generateGrammarOutput(table *ParseTable, pkg string) ([]byte, error) {
+	output := &bytes.Buffer{}
+	symbols, symbolNames := writeSymbols(output, pkg, table.NonTerminals, table.Terminals)
+	output.WriteString("func init() {\ngeneratedTables = &ParseTables{\n")
+	fmt.Fprintf(output, "SymbolNames: []string{%v},\n", symbolNames)
+	fmt.Fprintf(output, "NonTerminalCount: %v,\n", len(table.NonTerminals))
+	output.WriteString("Starts: map[string]Symbol{\n")
+	for _, start := range table.Starts {
+		fmt.Fprintf(output, "%q: %v,\n", start, symbols[start])
+	}
+	output.WriteString("},\n")
+	fmt.Fprintf(output, "EndOfInput: %v,\n", symbols[EndOfInput])
+	if kindTerminals := kindTerminalsSource(table.Terminals, symbols); kindTerminals != "" {
+		fmt.Fprintf(output, "KindTerminals: []Symbol{%v},\n", kindTerminals)
+	}
+	output.WriteString("Rules: []*ParseRule{\n")
+	for i, rule := range table.Rules {
+		ruleSymbols := []string{}
+		for _, name := range rule.Symbols {
+			ruleSymbols = append(ruleSymbols, symbols[name])
+		}
+		fmt.Fprintf(
+			output, "{Production: %v, Symbols: []Symbol{%v}%v}, // %v\n",
+			symbols[rule.Production], strings.Join(ruleSymbols, ", "),
+			predicatesSource(rule.Predicates, symbols), ruleComment(i, rule),
+		)
+	}
+	output.WriteString("},\nParseTable: map[Symbol]map[Symbol]int{\n")
+	for _, name := range table.NonTerminals {
+		entries := []string{}
+		for _, terminal := range table.Terminals {
+			if ruleIndex, exists := table.Entries[name][terminal]; exists {
+				entries = append(entries, symbols[terminal]+": "+strconv.Itoa(ruleIndex))
 			}
 		}
-		for _, ruleset := range prod.RHS {
-			for _, rule := range ruleset {
-				terminal, isTerminal := rule.(*TerminalRHSRuleSymbol)
-				if isTerminal && !contains(readTerminals, terminal.name) {
-					tOutput += "    tSy" + strconv.Itoa(tSymbolNo) + " // " + terminal.name + "\n"
-					readTerminals = append(readTerminals, terminal.name)
-					tSymbolNo++
+		fmt.Fprintf(output, "%v: {%v},\n", symbols[name], strings.Join(entries, ", "))
+	}
+	output.WriteString("},\n")
+	if len(table.Fallbacks) > 0 {
+		output.WriteString("Fallbacks: map[Symbol]map[Symbol][]int{\n")
+		for _, name := range table.NonTerminals {
+			if fallbacks, exists := table.Fallbacks[name]; exists {
+				entries := []string{}
+				for _, terminal := range table.Terminals {
+					if ruleIndices, exists := fallbacks[terminal]; exists {
+						entries = append(entries, fmt.Sprintf("%v: {%v}", symbols[terminal], joinInts(ruleIndices)))
+					}
 				}
+				fmt.Fprintf(output, "%v: {%v},\n", symbols[name], strings.Join(entries, ", "))
 			}
 		}
+		output.WriteString("},\n")
 	}
-	output += ntOutput + tOutput + ")\n"
-	return output
+	if len(table.Refinements) > 0 {
+		output.WriteString("Refinements: map[Symbol][]Symbol{\n")
+		for _, name := range table.NonTerminals {
+			if refining, exists := table.Refinements[name]; exists {
+				refiningSymbols := []string{}
+				for _, refiningName := range refining {
+					refiningSymbols = append(refiningSymbols, symbols[refiningName])
+				}
+				fmt.Fprintf(output, "%v: {%v},\n", symbols[name], strings.Join(refiningSymbols, ", "))
+			}
+		}
+		output.WriteString("},\n")
+	}
+	if table.AST != nil {
+		writeASTTables(output, table, symbols)
+	}
+	output.WriteString("}\n}\n")
+	if table.AST != nil {
+		writeASTSource(output, table.AST)
+	}
+	return format.Source(output.Bytes())
+}
+
+// Provides the source of the lookahead predicates of a rule as a field
+// of the generated rule, empty for rules without lookahead restrictions.
+func predicatesSource(predicates []*LookaheadPredicate, symbols map[string]string) string {
+	if len(predicates) == 0 {
+		return ""
+	}
+	predicateSources := []string{}
+	for _, predicate := range predicates {
+		exclusions := []string{}
+		for _, exclusion := range predicate.Exclude {
+			terminals := []string{}
+			for _, terminal := range exclusion {
+				if terminal.NoLineTerminator {
+					terminals = append(terminals, "{Symbol: "+symbols[terminal.Name]+", NoLineTerminator: true}")
+				} else {
+					terminals = append(terminals, "{Symbol: "+symbols[terminal.Name]+"}")
+				}
+			}
+			exclusions = append(exclusions, "{"+strings.Join(terminals, ", ")+"}")
+		}
+		predicateSources = append(predicateSources, fmt.Sprintf(
+			"{Position: %v, Exclude: [][]PredicateTerminal{%v}}", predicate.Position, strings.Join(exclusions, ", "),
+		))
+	}
+	return ", Predicates: []*LookaheadPredicate{" + strings.Join(predicateSources, ", ") + "}"
+}
+
+//go:generate go run gen_kinds.go
+
+// Provides the source of the terminal symbol of each kind of token produced by the
+// lexer of the parser package keyed by the constant of the kind, so tokens are matched
+// to terminals by their kind. Empty when none of the terminals are kinds of token.
+func kindTerminalsSource(terminals []string, symbols map[string]string) string {
+	entries := []string{}
+	for _, name := range terminals {
+		for _, kind := range terminalKinds[name] {
+			entries = append(entries, kind+": "+symbols[name])
+		}
+	}
+	return strings.Join(entries, ", ")
+}
+
+// Provides the given integers separated by commas.
+func joinInts(values []int) string {
+	texts := []string{}
+	for _, value := range values {
+		texts = append(texts, strconv.Itoa(value))
+	}
+	return strings.Join(texts, ", ")
+}
+
+// Deals with writing the header of the generated source along with a constant
+// for each symbol, providing the constant names of the symbols and
+// the source of the list of symbol names.
+func writeSymbols(output *bytes.Buffer, pkg string, nonTerminals []string, terminals []string) (map[string]string, string) {
+	symbols := map[string]string{}
+	symbolNames := &bytes.Buffer{}
+	fmt.Fprintf(output, "// Code generated by esegrammar. DO NOT EDIT.\n\npackage %v\n\n", pkg)
+	output.WriteString("const (\n_ Symbol = iota\n")
+	symbolNames.WriteString("\"\", ")
+	for i, name := range nonTerminals {
+		symbols[name] = "ntSy" + strconv.Itoa(i)
+		fmt.Fprintf(output, "%v // %v\n", symbols[name], name)
+		fmt.Fprintf(symbolNames, "%q, ", name)
+	}
+	for i, name := range terminals {
+		symbols[name] = "tSy" + strconv.Itoa(i)
+		fmt.Fprintf(output, "%v // %v\n", symbols[name], name)
+		fmt.Fprintf(symbolNames, "%q, ", name)
+	}
+	output.WriteString(")\n\n")
+	return symbols, symbolNames.String()
+}
+
+// Deals with generating the symbols along with the LALR(1) action and goto
+// tables as formatted source code which can then be written to a file.
+func generateLRTableOutput(table *LRTable, pkg string) ([]byte, error) {
+	output := &bytes.Buffer{}
+	symbols, symbolNames := writeSymbols(output, pkg, table.NonTerminals, table.Terminals)
+	output.WriteString("func init() {\ngeneratedLRTables = &LRTables{\n")
+	fmt.Fprintf(output, "SymbolNames: []string{%v},\n", symbolNames)
+	fmt.Fprintf(output, "NonTerminalCount: %v,\n", len(table.NonTerminals))
+	output.WriteString("Starts: map[string]int{\n")
+	for _, start := range table.Starts {
+		fmt.Fprintf(output, "%q: %v,\n", start, table.StartStates[start])
+	}
+	output.WriteString("},\n")
+	fmt.Fprintf(output, "EndOfInput: %v,\n", symbols[EndOfInput])
+	output.WriteString("Rules: []*LRRule{\n")
+	for i, rule := range table.Rules {
+		fmt.Fprintf(output, "{Production: %v, Length: %v}, // %v\n", symbols[rule.Production], len(rule.Symbols), ruleComment(i, rule))
+	}
+	output.WriteString("},\nAction: []map[Symbol]LRAction{\n")
+	for state, actions := range table.Action {
+		entries := []string{}
+		for _, terminal := range table.Terminals {
+			if action, exists := actions[terminal]; exists {
+				kind := map[LRActionKind]string{
+					ShiftAction: "ShiftAction", ReduceAction: "ReduceAction", AcceptAction: "AcceptAction",
+				}[action.Kind]
+				entries = append(entries, fmt.Sprintf("%v: {%v, %v}", symbols[terminal], kind, action.Target))
+			}
+		}
+		fmt.Fprintf(output, "{%v}, // %v\n", strings.Join(entries, ", "), state)
+	}
+	output.WriteString("},\nGoto: []map[Symbol]int{\n")
+	for state, gotos := range table.Goto {
+		entries := []string{}
+		for _, name := range table.NonTerminals {
+			if target, exists := gotos[name]; exists {
+				entries = append(entries, symbols[name]+": "+strconv.Itoa(target))
+			}
+		}
+		fmt.Fprintf(output, "{%v}, // %v\n", strings.Join(entries, ", "), state)
+	}
+	output.WriteString("},\n}\n}\n")
+	return format.Source(output.Bytes())
+}
+
+// Provides the comment which follows a rule in the generated source,
+// the index of the rule followed by the source rule it came from.
+func ruleComment(index int, rule *TableRule) string {
+	if rule.Provenance == nil {
+		return strconv.Itoa(index)
+	}
+	return strconv.Itoa(index) + " " + strings.ReplaceAll(rule.Provenance.String(), "\n", " ")
 }
 
 // Simple helper method to determine whether the provided
@@ -179,27 +571,6 @@ func containsRule(haystack [][]RHSRuleSymbol, needle []RHSRuleSymbol) bool {
 	return match
 }
 
-// Creates a new string with the suffix of a combination of each parameter
-// provided and appends each one to the combined slice of strings provided as an argument.
-func combineParams(params []string, data [3]string, combined *[]string, start int, end int, index int, max int) {
-	if index == max {
-		combination := ""
-		for j := 0; j < max; j++ {
-			if j == 0 {
-				combination += data[j]
-			} else {
-				combination += "_" + data[j]
-			}
-		}
-		*combined = append(*combined, combination)
-		return
-	}
-	for i := start; i <= end && end-i+1 >= max-index; i++ {
-		data[index] = params[i]
-		combineParams(params, data, combined, i+1, end, index+1, max)
-	}
-}
-
 // LLkify deals with left-factoring and applying
 // left recursion removal to make the grammar compatible with
 // the ll(k) parsing algorithm.
@@ -224,7 +595,9 @@ func EliminateLeftRecursion(grammar *Grammar) {
 		prod = removeOtherProductions(prod, prevProductions)
 		alphas := [][]RHSRuleSymbol{}
 		betas := [][]RHSRuleSymbol{}
-		for _, rule := range prod.RHS {
+		alphaProvenance := []*Provenance{}
+		betaProvenance := []*Provenance{}
+		for i, rule := range prod.RHS {
 			if len(rule) > 0 {
 				// If first symbol is a left recursion
 				// then extract the alpha part of the rule.
@@ -244,29 +617,30 @@ func EliminateLeftRecursion(grammar *Grammar) {
 						newAlpha = alpha
 					}
 					alphas = append(alphas, newAlpha)
+					alphaProvenance = append(alphaProvenance, prod.ProvenanceOf(i))
 				} else {
 					// This is a non left recursive symbol
 					// so make it a beta.
 					betas = append(betas, rule)
+					betaProvenance = append(betaProvenance, prod.ProvenanceOf(i))
 				}
 			}
 		}
 		// If there are alphas then we know we need to do some left
 		// recursion elimation.
 		if len(alphas) > 0 {
-			newProdA := &Production{}
-			newProdA.Name = prod.Name
-			newProdA.Params = prod.Params
+			newProdA := prod.withoutRules()
 			newProdAPrime := &Production{}
 			newProdAPrime.Name = prod.Name + "'"
 			newProdAPrime.Params = prod.Params
+			newProdAPrime.Provenance = deriveProductionProvenance(prod, EliminateLeftRecursionTransform)
 			aPrimeRule := &NonTerminalRHSRuleSymbol{
 				name: newProdAPrime.Name,
 				params: &NtRHSParams{
 					Passthrough: prefix(prod.Params, "?"),
 				},
 			}
-			for _, beta := range betas {
+			for j, beta := range betas {
 				// Remove epsilon from the beginning of beta as is equivalent
 				// to an empty string as εA' = A' and εBA' = BA'.
 				if len(beta) > 1 && beta[0].Name() == "[empty]" {
@@ -276,14 +650,21 @@ func EliminateLeftRecursion(grammar *Grammar) {
 				}
 				betaAPrime := append(beta, aPrimeRule)
 				newProdA.RHS = append(newProdA.RHS, betaAPrime)
+				newProdA.RuleProvenance = append(
+					newProdA.RuleProvenance, deriveProvenance(betaProvenance[j], EliminateLeftRecursionTransform),
+				)
 			}
-			for _, alpha := range alphas {
+			for j, alpha := range alphas {
 				alphaAPrime := append(alpha, aPrimeRule)
 				newProdAPrime.RHS = append(newProdAPrime.RHS, alphaAPrime)
+				newProdAPrime.RuleProvenance = append(
+					newProdAPrime.RuleProvenance, deriveProvenance(alphaProvenance[j], EliminateLeftRecursionTransform),
+				)
 			}
 			newProdAPrime.RHS = append(newProdAPrime.RHS, []RHSRuleSymbol{&TerminalRHSRuleSymbol{
 				name: "[empty]",
 			}})
+			newProdAPrime.RuleProvenance = append(newProdAPrime.RuleProvenance, newProdAPrime.Provenance)
 			newProductions = append(newProductions, newProdA, newProdAPrime)
 		} else {
 			// Ignore productions of the form A -> ε in the new set of productions we are forming.
@@ -318,11 +699,8 @@ func EliminateLeftRecursion(grammar *Grammar) {
 func removeOtherProductions(prod *Production, otherProds []*Production) *Production {
 	newProd := prod
 	if len(otherProds) > 0 {
-		newProd = &Production{
-			Name:   prod.Name,
-			Params: prod.Params,
-		}
-		for _, rule := range prod.RHS {
+		newProd = prod.withoutRules()
+		for i, rule := range prod.RHS {
 			ruleIsEpsilon := false
 			// List of rules which are a result of applying
 			// other productions.
@@ -370,25 +748,35 @@ func removeOtherProductions(prod *Production, otherProds []*Production) *Product
 			// production as is.
 			if len(otherAppliedRules) == 0 && !ruleIsEpsilon {
 				newProd.RHS = append(newProd.RHS, rule)
+				newProd.RuleProvenance = append(newProd.RuleProvenance, prod.ProvenanceOf(i))
 			} else {
 				newProd.RHS = append(newProd.RHS, otherAppliedRules...)
+				newProd.RuleProvenance = appendDerived(
+					newProd.RuleProvenance, prod.ProvenanceOf(i), EliminateLeftRecursionTransform, len(otherAppliedRules),
+				)
 			}
 		}
 	}
 	return newProd
 }
 
+// Deals with appending the provenance of the given number of rules
+// created from the same source by the provided transformation.
+func appendDerived(provenance []*Provenance, source *Provenance, transform string, count int) []*Provenance {
+	for i := 0; i < count; i++ {
+		provenance = append(provenance, deriveProvenance(source, transform))
+	}
+	return provenance
+}
+
 // Deals with removing/applying all instances of all the other productions
 // for our given production.
 func removeAnyOtherProduction(prod *Production, others []*Production) *Production {
 	newProd := prod
 	if len(others) > 0 {
-		newProd = &Production{
-			Name:   prod.Name,
-			Params: prod.Params,
-		}
+		newProd = prod.withoutRules()
 		epsilon := []RHSRuleSymbol{}
-		for _, rule := range prod.RHS {
+		for i, rule := range prod.RHS {
 			otherAppliedRules := [][]RHSRuleSymbol{}
 			// Flag used to determine whether the current rule has been reduced to A -> ε.
 			ruleIsEpsilon := false
@@ -423,14 +811,19 @@ func removeAnyOtherProduction(prod *Production, others []*Production) *Productio
 			// with the non-terminal reference.
 			if len(otherAppliedRules) == 0 && !ruleIsEpsilon {
 				newProd.RHS = append(newProd.RHS, rule)
+				newProd.RuleProvenance = append(newProd.RuleProvenance, prod.ProvenanceOf(i))
 			} else {
 				newProd.RHS = append(newProd.RHS, otherAppliedRules...)
+				newProd.RuleProvenance = appendDerived(
+					newProd.RuleProvenance, prod.ProvenanceOf(i), EliminateLeftRecursionTransform, len(otherAppliedRules),
+				)
 			}
 		}
 		// Finally add epsilon to the end if it is set and it is not already
 		// in our rule set.
 		if len(epsilon) > 0 && !ruleExistsInAny(epsilon, newProd.RHS) {
 			newProd.RHS = append(newProd.RHS, epsilon)
+			newProd.RuleProvenance = appendDerived(newProd.RuleProvenance, prod.Provenance, EliminateLeftRecursionTransform, 1)
 		}
 	}
 	return newProd
@@ -476,9 +869,10 @@ func ruleExistsInAny(rule []RHSRuleSymbol, ruleSets ...[][]RHSRuleSymbol) bool {
 func LeftFactor(grammar *Grammar) {
 	productions := []*Production{}
 	for _, prod := range grammar.Productions {
-		newProductions, newRules := leftFactorRules(prod.RHS, prod.Name, prod.Params)
+		newProductions, newRules, newProvenance := leftFactorRules(prod)
 		if len(newRules) > 0 {
 			prod.RHS = newRules
+			prod.RuleProvenance = newProvenance
 		}
 		productions = append(productions, prod)
 		if len(newProductions) > 0 {
@@ -488,18 +882,29 @@ func LeftFactor(grammar *Grammar) {
 	grammar.Productions = productions
 }
 
-// Deals with left-factoring a given set of rules and generating a new set of productions
-// in the case left-factoring is needed.
-func leftFactorRules(rules [][]RHSRuleSymbol, prodName string, prodParams []string) ([]*Production, [][]RHSRuleSymbol) {
+// Deals with left-factoring the rules of a given production and generating a new set
+// of productions in the case left-factoring is needed, along with the provenance of the new rules.
+func leftFactorRules(prod *Production) ([]*Production, [][]RHSRuleSymbol, []*Provenance) {
+	rules := prod.RHS
+	prodName := prod.Name
+	prodParams := prod.Params
 	// Holds rule symbols which are the start of more than one rule.
 	var alphas []RHSRuleSymbol
 	alphaBetaMap := make(map[string][][]RHSRuleSymbol)
 	alphaGammaMap := make(map[string][][]RHSRuleSymbol)
+	// Holds the provenance of the rules in the maps above along
+	// with the rule each alpha was first found in.
+	alphaBetaProvenance := make(map[string][]*Provenance)
+	alphaGammaProvenance := make(map[string][]*Provenance)
+	alphaProvenance := make(map[string]*Provenance)
 	var newRules [][]RHSRuleSymbol
+	var newProvenance []*Provenance
 	var newProductions []*Production
 	for i, rule := range rules {
 		var betas [][]RHSRuleSymbol
 		var gammas [][]RHSRuleSymbol
+		var betaProvenance []*Provenance
+		var gammaProvenance []*Provenance
 		j := 0
 		leftRepeat := false
 		for j < len(rules) {
@@ -526,9 +931,11 @@ func leftFactorRules(rules [][]RHSRuleSymbol, prodName string, prodParams []stri
 				// Prevent duplicates (Especially an issue if multiple epsilons occur).
 				if !containsRule(betas, beta) {
 					betas = append(betas, beta)
+					betaProvenance = append(betaProvenance, deriveProvenance(prod.ProvenanceOf(j), LeftFactorTransform))
 				}
 			} else if !isFirstSymbolSame(rule, rules[j]) {
 				gammas = append(gammas, rules[j])
+				gammaProvenance = append(gammaProvenance, prod.ProvenanceOf(j))
 			}
 			j++
 		}
@@ -540,12 +947,17 @@ func leftFactorRules(rules [][]RHSRuleSymbol, prodName string, prodParams []stri
 			if len(rule) > 1 {
 				primaryRuleBeta = rule[1:]
 			}
+			primaryProvenance := deriveProvenance(prod.ProvenanceOf(i), LeftFactorTransform)
 			if len(primaryRuleBeta) == 1 && primaryRuleBeta[0].Name() == "[empty]" {
 				alphaBetaMap[rule[0].Name()] = append(betas, primaryRuleBeta)
+				alphaBetaProvenance[rule[0].Name()] = append(betaProvenance, primaryProvenance)
 			} else {
 				alphaBetaMap[rule[0].Name()] = append([][]RHSRuleSymbol{primaryRuleBeta}, betas...)
+				alphaBetaProvenance[rule[0].Name()] = append([]*Provenance{primaryProvenance}, betaProvenance...)
 			}
 			alphaGammaMap[rule[0].Name()] = gammas
+			alphaGammaProvenance[rule[0].Name()] = gammaProvenance
+			alphaProvenance[rule[0].Name()] = primaryProvenance
 		}
 	}
 	// Now handle each alpha one at a time in producing our new rules and productions.
@@ -562,7 +974,8 @@ func leftFactorRules(rules [][]RHSRuleSymbol, prodName string, prodParams []stri
 				[][]RHSRuleSymbol{[]RHSRuleSymbol{alpha, prodPrimeRule}},
 				newRules...,
 			)
-			for _, gamma := range alphaGammaMap[alpha.Name()] {
+			newProvenance = append([]*Provenance{alphaProvenance[alpha.Name()]}, newProvenance...)
+			for j, gamma := range alphaGammaMap[alpha.Name()] {
 				// Only in the case our gamma's first rule
 				// is not an alpha and was not a part of alpha[i+1]
 				// we will add it to the new set of rules.
@@ -570,12 +983,15 @@ func leftFactorRules(rules [][]RHSRuleSymbol, prodName string, prodParams []stri
 					if (i+1 < len(alphas) && !containsRule(alphaGammaMap[alphas[i+1].Name()], gamma)) ||
 						i+1 >= len(alphas) {
 						newRules = append(newRules, gamma)
+						newProvenance = append(newProvenance, alphaGammaProvenance[alpha.Name()][j])
 					}
 				}
 			}
 			prodPrime := &Production{
-				Name:   prodName + "A" + strconv.Itoa(i),
-				Params: prodParams,
+				Name:           prodName + "A" + strconv.Itoa(i),
+				Params:         prodParams,
+				Provenance:     deriveProductionProvenance(prod, LeftFactorTransform),
+				RuleProvenance: alphaBetaProvenance[alpha.Name()],
 			}
 			for _, beta := range alphaBetaMap[alpha.Name()] {
 				prodPrime.RHS = append(prodPrime.RHS, beta)
@@ -583,14 +999,15 @@ func leftFactorRules(rules [][]RHSRuleSymbol, prodName string, prodParams []stri
 			newProductions = append([]*Production{prodPrime}, newProductions...)
 
 			// Now for A'.
-			furtherPrimeProductions, newProdPrimeRules := leftFactorRules(prodPrime.RHS, prodPrime.Name, prodPrime.Params)
+			furtherPrimeProductions, newProdPrimeRules, newProdPrimeProvenance := leftFactorRules(prodPrime)
 			if len(newProdPrimeRules) > 0 {
 				prodPrime.RHS = newProdPrimeRules
+				prodPrime.RuleProvenance = newProdPrimeProvenance
 			}
 			newProductions = append(newProductions, furtherPrimeProductions...)
 		}
 	}
-	return newProductions, newRules
+	return newProductions, newRules, newProvenance
 }
 
 // Adds the given prefix to each string in the provided list.
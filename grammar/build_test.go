@@ -1,7 +1,12 @@
 package grammar
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/freshwebio/esengine/parser"
 )
 
 func TestLeftFactor(t *testing.T) {
@@ -36,3 +41,169 @@ func TestExpandOptionals(t *testing.T) {
 	ExpandOptionals(input)
 	assertSame(t, *input, *expected)
 }
+
+func TestBuildParseTable(t *testing.T) {
+	_, grammar := loadGrammarFixture("elr1")
+	table, err := BuildParseTable(grammar, Analyse(grammar, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]map[string]int{
+		"E":  {"(": 0, "id": 0},
+		"E'": {"+": 1, ")": 2, "[eoi]": 2},
+		"T":  {"(": 3, "id": 3},
+		"T'": {"*": 4, "+": 5, ")": 5, "[eoi]": 5},
+		"F":  {"(": 6, "id": 7},
+	}
+	for nonTerminal, row := range expected {
+		if len(table.Entries[nonTerminal]) != len(row) {
+			t.Errorf("Expected %v entries for %v but got %v", len(row), nonTerminal, table.Entries[nonTerminal])
+		}
+		for terminal, rule := range row {
+			if actual, exists := table.Entries[nonTerminal][terminal]; !exists || actual != rule {
+				t.Errorf("Expected rule %v for (%v, %v) but got %v", rule, nonTerminal, terminal, actual)
+			}
+		}
+	}
+	if len(table.Terminals) != 6 || table.Terminals[len(table.Terminals)-1] != EndOfInput {
+		t.Errorf("Expected the terminals to end with the end of input but got %v", table.Terminals)
+	}
+}
+
+func TestBuildParseTableUndefinedNonTerminal(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n  rhs:\n    - [a, <Missing>]\n")
+	_, err := BuildParseTable(grammar, Analyse(grammar, 1))
+	if !errors.Is(err, ErrUndefinedNonTerminal) {
+		t.Fatalf("Expected an undefined non-terminal error but got %v", err)
+	}
+	if buildErr := err.(*BuildError); buildErr.Production != "S" {
+		t.Errorf("Expected the error to be reported for <S> but got %v", buildErr.Production)
+	}
+}
+
+func TestTerminalKindsMatchParser(t *testing.T) {
+	expected := map[string][]string{}
+	for kind := parser.TokenKind(1); kind.String() != ""; kind++ {
+		expected[kind.Terminal()] = append(expected[kind.Terminal()], kind.GoString())
+	}
+	if !reflect.DeepEqual(terminalKinds, expected) {
+		t.Errorf("Expected the terminal kinds to match the token kinds of the parser package, run go generate")
+	}
+}
+
+func TestGenerateGrammarOutput(t *testing.T) {
+	_, grammar := loadGrammarFixture("elr1")
+	table, err := BuildParseTable(grammar, Analyse(grammar, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := generateGrammarOutput(table, "parser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"package parser", "// E'\n", "tSy5         // [eoi]",
+		"{Production: ntSy1, Symbols: []Symbol{tSy0, ntSy2, ntSy1}}, // 1",
+		"ntSy4: {tSy2: 6, tSy4: 7},",
+		"KindTerminals: []Symbol{PlusToken: tSy0",
+	} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected the generated output to contain %q but got\n%s", expected, output)
+		}
+	}
+}
+
+func TestExpandParameters(t *testing.T) {
+	input, expected := loadGrammarFixture("ep1")
+	ExpandParameters(input)
+	assertSame(t, *input, *expected)
+}
+
+const predicateTestGrammar = "<Statement>:\n" +
+	"  rhs:\n" +
+	"    - [<ExpressionStatement>]\n" +
+	"    - [<LexicalDeclaration>]\n" +
+	"    - [<AsyncFunctionDeclaration>]\n" +
+	"<ExpressionStatement>:\n" +
+	"  rhs:\n" +
+	"    - [{<*Lookahead*>: {params: {exclude: [['{'], [let, '['], [async, <!LineTerminator!>, function]]}}}, <Expression>, ;]\n" +
+	"<Expression>:\n" +
+	"  rhs:\n" +
+	"    - [IdentifierName]\n" +
+	"    - [let]\n" +
+	"    - [async]\n" +
+	"    - ['{', '}']\n" +
+	"<LexicalDeclaration>:\n" +
+	"  rhs:\n" +
+	"    - [let, '[', IdentifierName, ']', ;]\n" +
+	"<AsyncFunctionDeclaration>:\n" +
+	"  rhs:\n" +
+	"    - [async, function, IdentifierName]\n"
+
+func TestBuildBytesPredicates(t *testing.T) {
+	artefacts, err := BuildBytes([]byte(predicateTestGrammar), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFallbacks := map[string]map[string][]int{
+		"Statement": {"let": {1}, "async": {2}},
+	}
+	if !reflect.DeepEqual(artefacts.Table.Fallbacks, expectedFallbacks) {
+		t.Errorf("Expected the fallbacks %v but got %v", expectedFallbacks, artefacts.Table.Fallbacks)
+	}
+	for _, expected := range []string{"Predicates: []*LookaheadPredicate{{Position: 0,", "Fallbacks: map[Symbol]map[Symbol][]int{"} {
+		if !strings.Contains(string(artefacts.Source), expected) {
+			t.Errorf("Expected the generated source to contain %q but got\n%s", expected, artefacts.Source)
+		}
+	}
+}
+
+func TestBuildParseTablePredicates(t *testing.T) {
+	grammar := loadTestGrammar(t, predicateTestGrammar)
+	table, err := BuildParseTable(grammar, Analyse(grammar, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	predicates := table.Rules[3].Predicates
+	if len(predicates) != 1 || predicates[0].Position != 0 {
+		t.Fatalf("Expected a single predicate at the start of the expression statement but got %v", predicates)
+	}
+	excluded := [][]PredicateTerminal{}
+	for _, exclusion := range predicates[0].Exclude {
+		terminals := []PredicateTerminal{}
+		for _, terminal := range exclusion {
+			terminals = append(terminals, *terminal)
+		}
+		excluded = append(excluded, terminals)
+	}
+	expectedExcluded := [][]PredicateTerminal{
+		{{Name: "{"}},
+		{{Name: "let"}, {Name: "["}},
+		{{Name: "async"}, {Name: "function", NoLineTerminator: true}},
+	}
+	if !reflect.DeepEqual(excluded, expectedExcluded) {
+		t.Errorf("Expected the excluded sequences %v but got %v", expectedExcluded, excluded)
+	}
+	if _, exists := table.Entries["Statement"]["{"]; exists {
+		t.Errorf("Expected a single terminal exclusion to remove { from the statement entries")
+	}
+	expectedFallbacks := map[string]map[string][]int{
+		"Statement": {"let": {1}, "async": {2}},
+	}
+	if !reflect.DeepEqual(table.Fallbacks, expectedFallbacks) {
+		t.Errorf("Expected the fallbacks %v but got %v", expectedFallbacks, table.Fallbacks)
+	}
+	output, err := generateGrammarOutput(table, "parser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"Predicates: []*LookaheadPredicate{{Position: 0, Exclude: [][]PredicateTerminal{" +
+			"{{Symbol: tSy0}}, {{Symbol: tSy1}, {Symbol: tSy2}}, {{Symbol: tSy3}, {Symbol: tSy4, NoLineTerminator: true}}}}}",
+		"Fallbacks: map[Symbol]map[Symbol][]int{", "ntSy0: {tSy1: {1}, tSy3: {2}},",
+	} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected the generated output to contain %q but got\n%s", expected, output)
+		}
+	}
+}
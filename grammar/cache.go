@@ -0,0 +1,345 @@
+package grammar
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Identifies the build of esegrammar the cached artefacts are produced by, which holds
+// the transformations and analysis, so artefacts of other builds are not picked up.
+var buildIdentity = readBuildIdentity()
+
+const (
+	// The prefix and extension of the files holding
+	// the generated source of a build.
+	sourceFilePrefix = "build-"
+	sourceFileExt    = ".go"
+	// The file holding the FIRST_k and FOLLOW_k sets
+	// of the productions of the last build.
+	analysisFile = "analysis.json"
+)
+
+// BuildCache provides the artefacts of earlier builds stored in a directory,
+// used to skip building a grammar when neither it nor the build options have
+// changed and to only compute the analysis of the productions affected by an edit.
+type BuildCache struct {
+	// Dir holds the directory the artefacts are stored in.
+	Dir string
+}
+
+// Holds the FIRST_k and FOLLOW_k sets stored in the cache keyed by the
+// hash of everything the set of a production depends on, the sets themselves
+// are only stored once as many productions share the same set.
+type analysisRecord struct {
+	Sets   [][][]string   `json:"sets"`
+	First  map[string]int `json:"first"`
+	Follow map[string]int `json:"follow"`
+}
+
+// OpenBuildCache deals with opening the build cache in the given
+// directory, the directory is created when it does not exist yet.
+func OpenBuildCache(dir string) (*BuildCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &BuildCache{Dir: dir}, nil
+}
+
+// BuildKey provides the key of a build which changes whenever the grammar data, the supplemental
+// grammar data, one of the options that affect the generated source or the build of esegrammar change.
+func BuildKey(data []byte, supplemental []byte, options *BuildOptions) string {
+	return hashStrings(
+		buildIdentity, options.Format, options.Package, options.Algorithm,
+		strconv.FormatBool(options.Verify), strconv.Itoa(options.VerifyLength),
+		string(data), string(supplemental),
+	)
+}
+
+// Source provides the generated source stored for the build with
+// the given key, the second value is false when there is none.
+func (c *BuildCache) Source(key string) ([]byte, bool) {
+	source, err := ioutil.ReadFile(c.sourcePath(key))
+	if err != nil {
+		return nil, false
+	}
+	return source, true
+}
+
+// StoreSource deals with storing the generated source of the build with the given
+// key, the source of every other build is removed as only the last build is kept.
+func (c *BuildCache) StoreSource(key string, source []byte) error {
+	previous, err := filepath.Glob(filepath.Join(c.Dir, sourceFilePrefix+"*"+sourceFileExt))
+	if err != nil {
+		return err
+	}
+	for _, file := range previous {
+		// Another build could have removed the file in the meantime.
+		if err = os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return ioutil.WriteFile(c.sourcePath(key), source, 0644)
+}
+
+func (c *BuildCache) sourcePath(key string) string {
+	return filepath.Join(c.Dir, sourceFilePrefix+key+sourceFileExt)
+}
+
+// Analyse deals with computing the FIRST_k and FOLLOW_k sets of the productions
+// of the given grammar the same way as Analyse, the sets of the productions whose
+// dependencies are unchanged since the last build are taken from the cache.
+// The names of the productions with a set that had to be computed are returned
+// along with the analysis, the sets of this build replace those in the cache.
+func (c *BuildCache) Analyse(grammar *Grammar, k int, starts ...string) (*Analysis, []string, error) {
+	a := newAnalysis(grammar, k, starts)
+	firstKeys, followKeys := analysisKeys(grammar, a.K, a.Starts)
+	record := c.loadAnalysis()
+	knownFirst := map[string]bool{}
+	knownFollow := map[string]bool{}
+	for _, prod := range grammar.Productions {
+		if set, exists := record.set(record.First, firstKeys[prod.Name]); exists {
+			a.First[prod.Name] = set
+			knownFirst[prod.Name] = true
+		}
+		if set, exists := record.set(record.Follow, followKeys[prod.Name]); exists {
+			a.Follow[prod.Name] = set
+			knownFollow[prod.Name] = true
+		}
+	}
+	a.computeFirst(grammar, knownFirst)
+	a.computeFollow(grammar, knownFollow)
+	recomputed := []string{}
+	for _, prod := range grammar.Productions {
+		if !knownFirst[prod.Name] || !knownFollow[prod.Name] {
+			recomputed = append(recomputed, prod.Name)
+		}
+	}
+	if err := c.storeAnalysis(grammar, a, firstKeys, followKeys); err != nil {
+		return a, recomputed, err
+	}
+	return a, recomputed, nil
+}
+
+// Loads the sets of the last build, a missing or unreadable
+// file leaves every set to be computed again.
+func (c *BuildCache) loadAnalysis() *analysisRecord {
+	record := &analysisRecord{}
+	data, err := ioutil.ReadFile(filepath.Join(c.Dir, analysisFile))
+	if err != nil || json.Unmarshal(data, record) != nil {
+		return &analysisRecord{}
+	}
+	return record
+}
+
+// Stores the sets of this build in place of those of the last build.
+func (c *BuildCache) storeAnalysis(grammar *Grammar, a *Analysis, firstKeys map[string]string, followKeys map[string]string) error {
+	record := &analysisRecord{First: map[string]int{}, Follow: map[string]int{}}
+	setIndices := map[string]int{}
+	addSet := func(set *SequenceSet) int {
+		setKey := strings.Join(set.Strings(), "\n")
+		index, exists := setIndices[setKey]
+		if !exists {
+			sequences := [][]string{}
+			for _, seq := range set.Sequences {
+				sequences = append(sequences, append([]string{}, seq...))
+			}
+			index = len(record.Sets)
+			record.Sets = append(record.Sets, sequences)
+			setIndices[setKey] = index
+		}
+		return index
+	}
+	for _, prod := range grammar.Productions {
+		record.First[firstKeys[prod.Name]] = addSet(a.First[prod.Name])
+		record.Follow[followKeys[prod.Name]] = addSet(a.Follow[prod.Name])
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(c.Dir, analysisFile), data, 0644)
+}
+
+// Provides a copy of the set stored for the given key, the sets in the
+// record are shared between productions so each production gets its own.
+func (r *analysisRecord) set(keys map[string]int, key string) (*SequenceSet, bool) {
+	index, exists := keys[key]
+	if !exists || index < 0 || index >= len(r.Sets) {
+		return nil, false
+	}
+	set := NewSequenceSet()
+	for _, seq := range r.Sets[index] {
+		set.Add(TerminalSequence(seq))
+	}
+	return set, true
+}
+
+// Provides the keys the FIRST_k and FOLLOW_k sets of each production are stored under.
+// The FIRST_k set of a production depends on its rules and the FIRST_k sets of the
+// productions they refer to, the FOLLOW_k set depends on the rules that refer to the
+// production, the FIRST_k sets of what follows it in those rules and the FOLLOW_k sets of
+// the productions the rules belong to. Each key is a hash of everything the set depends on,
+// productions that depend on each other through recursion are hashed together.
+func analysisKeys(grammar *Grammar, k int, starts []string) (map[string]string, map[string]string) {
+	names := []string{}
+	productions := map[string]bool{}
+	for _, prod := range grammar.Productions {
+		names = append(names, prod.Name)
+		productions[prod.Name] = true
+	}
+	ownFirst := map[string]string{}
+	mentions := map[string][]string{}
+	mentionedBy := map[string][]string{}
+	for _, prod := range grammar.Productions {
+		parts := []string{buildIdentity, strconv.Itoa(k), prod.Name}
+		for _, rule := range prod.RHS {
+			parts = append(parts, ruleKey(rule))
+			for _, symbol := range flattenRule(rule) {
+				if _, isNonTerminal := symbol.(*NonTerminalRHSRuleSymbol); isNonTerminal && productions[symbol.Name()] {
+					if !contains(mentions[prod.Name], symbol.Name()) {
+						mentions[prod.Name] = append(mentions[prod.Name], symbol.Name())
+						mentionedBy[symbol.Name()] = append(mentionedBy[symbol.Name()], prod.Name)
+					}
+				}
+			}
+		}
+		ownFirst[prod.Name] = hashStrings(parts...)
+	}
+	firstKeys := closureKeys(names, ownFirst, mentions)
+	trailers := map[string][]string{}
+	for _, prod := range grammar.Productions {
+		for _, rule := range prod.RHS {
+			flattened := flattenRule(rule)
+			for i, symbol := range flattened {
+				if _, isNonTerminal := symbol.(*NonTerminalRHSRuleSymbol); !isNonTerminal || !productions[symbol.Name()] {
+					continue
+				}
+				// The rules of the production are part of its own key which covers the
+				// terminals that follow the symbol, so only the productions are added.
+				parts := []string{ownFirst[prod.Name]}
+				for _, next := range flattened[i+1:] {
+					if _, isNonTerminal := next.(*NonTerminalRHSRuleSymbol); isNonTerminal {
+						parts = append(parts, firstKeys[next.Name()])
+					}
+				}
+				trailers[symbol.Name()] = append(trailers[symbol.Name()], hashStrings(parts...))
+			}
+		}
+	}
+	ownFollow := map[string]string{}
+	for _, name := range names {
+		sort.Strings(trailers[name])
+		parts := []string{buildIdentity, strconv.Itoa(k), name, strconv.FormatBool(contains(starts, name))}
+		ownFollow[name] = hashStrings(append(parts, trailers[name]...)...)
+	}
+	return firstKeys, closureKeys(names, ownFollow, mentionedBy)
+}
+
+// Provides a key for each of the named nodes of a graph which changes whenever the
+// own key of a node or of a node reachable from it changes. The strongly connected
+// components of the graph are found with Tarjan's algorithm which completes every
+// component reachable from a component before the component itself.
+func closureKeys(names []string, own map[string]string, edges map[string][]string) map[string]string {
+	keys := map[string]string{}
+	index := map[string]int{}
+	lowLink := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []string{}
+	var visit func(name string)
+	visit = func(name string) {
+		index[name] = len(index)
+		lowLink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+		for _, next := range edges[name] {
+			if _, visited := index[next]; !visited {
+				visit(next)
+				if lowLink[next] < lowLink[name] {
+					lowLink[name] = lowLink[next]
+				}
+			} else if onStack[next] && index[next] < lowLink[name] {
+				lowLink[name] = index[next]
+			}
+		}
+		if lowLink[name] != index[name] {
+			return
+		}
+		component := map[string]bool{}
+		parts := []string{}
+		top := ""
+		for top != name {
+			top = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component[top] = true
+			parts = append(parts, own[top])
+		}
+		for member := range component {
+			for _, next := range edges[member] {
+				if !component[next] {
+					parts = append(parts, "->"+keys[next])
+				}
+			}
+		}
+		sort.Strings(parts)
+		componentKey := hashStrings(parts...)
+		for member := range component {
+			keys[member] = hashStrings(componentKey, own[member])
+		}
+	}
+	for _, name := range names {
+		if _, visited := index[name]; !visited {
+			visit(name)
+		}
+	}
+	return keys
+}
+
+// Provides a right-hand side rule as text which tells apart
+// every kind of symbol, used to work out whether a rule changed.
+func ruleKey(rule []RHSRuleSymbol) string {
+	symbols := []string{}
+	for _, symbol := range rule {
+		symbols = append(symbols, fmt.Sprintf("%T(%v)", symbol, symbolText(symbol)))
+	}
+	return strings.Join(symbols, " ")
+}
+
+// Provides a hash of the go version, module versions and version control
+// revision the running binary was built with, empty when it was built
+// without module support.
+func readBuildIdentity() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	parts := []string{info.GoVersion, info.Main.Path, info.Main.Version, info.Main.Sum}
+	for _, dep := range info.Deps {
+		parts = append(parts, dep.Path, dep.Version, dep.Sum)
+	}
+	for _, setting := range info.Settings {
+		if strings.HasPrefix(setting.Key, "vcs.") {
+			parts = append(parts, setting.Key, setting.Value)
+		}
+	}
+	return hashStrings(parts...)
+}
+
+// Provides the hex encoded SHA-256 hash of the given strings,
+// each string is prefixed with its length so they can not run into each other.
+func hashStrings(values ...string) string {
+	hash := sha256.New()
+	for _, value := range values {
+		fmt.Fprintf(hash, "%d:%s", len(value), value)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,71 @@
+package grammar
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const cacheTestGrammar = "<S>:\n  rhs:\n    - [<A>]\n    - [<B>, x]\n" +
+	"<A>:\n  rhs:\n    - [a]\n" +
+	"<B>:\n  rhs:\n    - [b, <C>]\n" +
+	"<C>:\n  rhs:\n    - [c]\n"
+
+func TestBuildBytesCache(t *testing.T) {
+	options := &BuildOptions{Package: "parser", CacheDir: t.TempDir()}
+	uncached, err := BuildBytes([]byte(cacheTestGrammar), &BuildOptions{Package: "parser"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := BuildBytes([]byte(cacheTestGrammar), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Cached || len(first.Recomputed) != len(first.Grammar.Productions) || !bytes.Equal(first.Source, uncached.Source) {
+		t.Errorf("Expected the first build to compute every set but got %+v", first)
+	}
+	second, err := BuildBytes([]byte(cacheTestGrammar), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !second.Cached || !bytes.Equal(second.Source, uncached.Source) {
+		t.Errorf("Expected the second build to be taken from the cache but got %+v", second)
+	}
+	options.Algorithm = "lalr"
+	third, err := BuildBytes([]byte(cacheTestGrammar), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.Cached {
+		t.Errorf("Expected a change of options to build the grammar again")
+	}
+}
+
+func TestBuildCacheAnalyseIncremental(t *testing.T) {
+	cache, err := OpenBuildCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = cache.Analyse(loadTestGrammar(t, cacheTestGrammar), 1); err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(cacheTestGrammar, "    - [c]\n", "    - [c]\n    - [d]\n", 1)
+	grammar := loadTestGrammar(t, edited)
+	analysis, recomputed, err := cache.Analyse(grammar, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Neither the FIRST_k set of <A> nor what follows it
+	// in the rules of <S> depend on <C>.
+	if !reflect.DeepEqual(recomputed, []string{"S", "B", "C"}) {
+		t.Errorf("Expected only the sets of S, B and C to be computed but got %v", recomputed)
+	}
+	expected := Analyse(grammar, 1)
+	for _, prod := range grammar.Productions {
+		if !reflect.DeepEqual(analysis.FirstOf(prod.Name).Strings(), expected.FirstOf(prod.Name).Strings()) ||
+			!reflect.DeepEqual(analysis.FollowOf(prod.Name).Strings(), expected.FollowOf(prod.Name).Strings()) {
+			t.Errorf("Expected the sets of %v to match a full analysis", prod.Name)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package grammar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMalformedStartSymbol provides the error for the case when a start symbol
+// to check inputs against is not of the form Name or Name[Param, ...].
+var ErrMalformedStartSymbol = errors.New("malformed start symbol")
+
+// The name of the production added to the grammar being checked
+// which derives the start symbol with its parameters enabled.
+const checkStart = "[check]"
+
+// CheckOptions provides the options used to check
+// whether inputs are accepted by a grammar.
+type CheckOptions struct {
+	// Transformed is true when inputs are checked against the grammar once it has been
+	// transformed for building, otherwise only the parameters of the grammar are expanded.
+	Transformed bool
+}
+
+// CheckToken provides a token of an input checked against a grammar.
+type CheckToken struct {
+	// Terminals holds the names of the terminals of the grammar the token
+	// is an instance of, such as both the value and kind of a reserved word.
+	Terminals []string
+	// Text holds the source text of the token, used when reporting where
+	// an input was rejected. Defaults to the first of the terminals.
+	Text string
+	// LineTerminatorBefore is true when a line terminator precedes the token,
+	// in which case it does not satisfy a [no LineTerminator here] restriction.
+	LineTerminatorBefore bool
+}
+
+// CheckResult provides the outcome of checking an input against a grammar.
+type CheckResult struct {
+	Accepted bool
+	// Position holds the index of the token the input was rejected at, which
+	// is the number of tokens when the input ended before it could be accepted.
+	Position int
+	// Token holds the token the input was rejected at, nil when
+	// the input was accepted or ended before it could be accepted.
+	Token *CheckToken
+}
+
+func (r *CheckResult) String() string {
+	if r.Accepted {
+		return "accepted"
+	}
+	if r.Token == nil {
+		return "rejected at the end of input"
+	}
+	return fmt.Sprintf("rejected at token %v %q", r.Position, r.Token.text())
+}
+
+// Checker provides a recognizer of the sentences of a grammar which interprets the
+// rules of the grammar directly instead of building a parse table, so it accepts exactly
+// the language of the grammar whether or not the grammar is LL(1) and can be used to
+// show that a transformation of the grammar does not change its language.
+type Checker struct {
+	// Holds the names of the productions in the order of the expanded grammar.
+	names       []string
+	productions map[string]*Production
+	// Holds the flattened right-hand side rules of each production.
+	rules map[string][][]RHSRuleSymbol
+}
+
+// An item of the recognizer, a rule of a production with the number of
+// symbols of the rule recognized so far from the given origin.
+type checkItem struct {
+	production string
+	rule       int
+	dot        int
+	origin     int
+}
+
+// NewChecker deals with creating a checker for sentences derived from the given start
+// symbol, which is the name of a production optionally followed by the parameters
+// enabled for it such as Statement[Yield, Return]. The grammar is not modified as its
+// parameters are expanded on a copy of its productions.
+func NewChecker(grammar *Grammar, start string, options *CheckOptions) (*Checker, error) {
+	name, params, err := parseStartSymbol(start)
+	if err != nil {
+		return nil, err
+	}
+	var startProd *Production
+	for _, prod := range grammar.Productions {
+		if prod.Name == name {
+			startProd = prod
+		}
+	}
+	if startProd == nil {
+		return nil, fmt.Errorf("%w: <%v>", ErrUnknownStartSymbol, name)
+	}
+	passthrough := []string{}
+	for _, param := range params {
+		if !contains(startProd.Params, param) {
+			return nil, fmt.Errorf("%w: <%v> does not declare %v", ErrUnknownParameter, name, param)
+		}
+		passthrough = append(passthrough, "+"+param)
+	}
+	goal := &Production{
+		Name: checkStart,
+		RHS: [][]RHSRuleSymbol{{
+			&NonTerminalRHSRuleSymbol{name: name, params: &NtRHSParams{Passthrough: passthrough}},
+		}},
+	}
+	checked := &Grammar{Productions: append([]*Production{goal}, grammar.Productions...)}
+	ExpandParameters(checked, checkStart)
+	if options != nil && options.Transformed {
+		ExpandOptionals(checked)
+		LLkify(checked)
+	}
+	checker := &Checker{productions: map[string]*Production{}, rules: map[string][][]RHSRuleSymbol{}}
+	for _, prod := range checked.Productions {
+		checker.names = append(checker.names, prod.Name)
+		checker.productions[prod.Name] = prod
+		for _, rule := range prod.RHS {
+			checker.rules[prod.Name] = append(checker.rules[prod.Name], flattenRule(rule))
+		}
+	}
+	return checker, nil
+}
+
+// Check deals with determining whether the given input is a sentence of the grammar.
+// The items of an Earley recognizer are computed for each position of the input,
+// a lookahead restriction is checked against the tokens that follow its position
+// and a [no LineTerminator here] restriction against the token that follows it.
+func (c *Checker) Check(input []CheckToken) *CheckResult {
+	sets := make([][]*checkItem, len(input)+1)
+	seen := make([]map[checkItem]bool, len(input)+1)
+	for i := range seen {
+		seen[i] = map[checkItem]bool{}
+	}
+	add := func(position int, item checkItem) {
+		if !seen[position][item] {
+			seen[position][item] = true
+			sets[position] = append(sets[position], &item)
+		}
+	}
+	for i := range c.rules[checkStart] {
+		add(0, checkItem{production: checkStart, rule: i})
+	}
+	reached := 0
+	for position := 0; position <= len(input); position++ {
+		if len(sets[position]) > 0 {
+			reached = position
+		}
+		// Holds the productions which derive the empty string from this position,
+		// items predicting them later on are advanced straight away.
+		nullable := map[string]bool{}
+		for i := 0; i < len(sets[position]); i++ {
+			item := *sets[position][i]
+			rule := c.rules[item.production][item.rule]
+			if item.dot == len(rule) {
+				if item.origin == position {
+					nullable[item.production] = true
+				}
+				for _, waiting := range sets[item.origin] {
+					if c.expects(waiting, item.production) {
+						add(position, checkItem{waiting.production, waiting.rule, waiting.dot + 1, waiting.origin})
+					}
+				}
+				continue
+			}
+			next := checkItem{item.production, item.rule, item.dot + 1, item.origin}
+			switch symbol := rule[item.dot].(type) {
+			case *NonTerminalRHSRuleSymbol:
+				if isOptional(symbol) {
+					add(position, next)
+				}
+				if _, exists := c.productions[symbol.name]; exists {
+					for j := range c.rules[symbol.name] {
+						add(position, checkItem{production: symbol.name, rule: j, origin: position})
+					}
+					if nullable[symbol.name] {
+						add(position, next)
+					}
+				} else if position < len(input) && input[position].matches(symbol.name) {
+					// Non-terminals without a production are terminals
+					// of the syntactic grammar such as AssignmentOperator.
+					add(position+1, next)
+				}
+			case *TerminalRHSRuleSymbol:
+				if symbol.name == Epsilon {
+					add(position, next)
+				} else if position < len(input) && input[position].matches(symbol.name) {
+					add(position+1, next)
+				}
+			case *ExcludeRHSRuleSymbol:
+				if symbol.name != lineTerminator || position == len(input) || !input[position].LineTerminatorBefore {
+					add(position, next)
+				}
+			case *LookaheadRHSRuleSymbol:
+				if !isExcludedInput(input[position:], LookaheadTerminals(symbol.params)) {
+					add(position, next)
+				}
+			}
+		}
+	}
+	for _, item := range sets[len(input)] {
+		if item.production == checkStart && item.origin == 0 && item.dot == len(c.rules[checkStart][item.rule]) {
+			return &CheckResult{Accepted: true}
+		}
+	}
+	result := &CheckResult{Position: reached}
+	if reached < len(input) {
+		result.Token = &input[reached]
+	}
+	return result
+}
+
+// Determines whether the given item expects the named
+// production as the next symbol of its rule.
+func (c *Checker) expects(item *checkItem, production string) bool {
+	rule := c.rules[item.production][item.rule]
+	return item.dot < len(rule) && rule[item.dot].Name() == production &&
+		isNonTerminalSymbol(rule[item.dot])
+}
+
+func isNonTerminalSymbol(symbol RHSRuleSymbol) bool {
+	_, isNonTerminal := symbol.(*NonTerminalRHSRuleSymbol)
+	return isNonTerminal
+}
+
+// Determines whether the given tokens start with one of the excluded sequences,
+// sequences longer than the tokens do not exclude them.
+// A terminal restricted by [no LineTerminator here] is not matched by a token
+// which a line terminator precedes.
+func isExcludedInput(tokens []CheckToken, exclusions [][]*PredicateTerminal) bool {
+	excluded := false
+	i := 0
+	for !excluded && i < len(exclusions) {
+		exclusion := exclusions[i]
+		if len(exclusion) > 0 && len(exclusion) <= len(tokens) {
+			excluded = true
+			for j, terminal := range exclusion {
+				excluded = excluded && tokens[j].matches(terminal.Name) &&
+					!(terminal.NoLineTerminator && tokens[j].LineTerminatorBefore)
+			}
+		}
+		i++
+	}
+	return excluded
+}
+
+func (t *CheckToken) matches(terminal string) bool {
+	return contains(t.Terminals, terminal)
+}
+
+func (t *CheckToken) text() string {
+	if t.Text == "" && len(t.Terminals) > 0 {
+		return t.Terminals[0]
+	}
+	return t.Text
+}
+
+// TerminalTokens provides the tokens of an input written as the names of
+// its terminals separated by white space, such as IdentifierName = NumericLiteral ;
+// where each name is a token which is only an instance of the named terminal.
+func TerminalTokens(input string) []CheckToken {
+	tokens := []CheckToken{}
+	for _, name := range strings.Fields(input) {
+		tokens = append(tokens, CheckToken{Terminals: []string{name}, Text: name})
+	}
+	return tokens
+}
+
+// Provides the name of the production and the enabled parameters
+// of a start symbol of the form Name or Name[Param, ...].
+func parseStartSymbol(start string) (string, []string, error) {
+	open := strings.Index(start, "[")
+	if open == -1 {
+		return strings.TrimSpace(start), nil, nil
+	}
+	if !strings.HasSuffix(start, "]") {
+		return "", nil, fmt.Errorf("%w: %v", ErrMalformedStartSymbol, start)
+	}
+	params := []string{}
+	for _, param := range strings.Split(start[open+1:len(start)-1], ",") {
+		if param = strings.TrimSpace(param); param != "" {
+			params = append(params, param)
+		}
+	}
+	return strings.TrimSpace(start[:open]), params, nil
+}
@@ -0,0 +1,135 @@
+package grammar
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckParameters(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n"+
+		"  params: [In]\n"+
+		"  rhs:\n"+
+		"    -\n"+
+		"      - <A>:\n"+
+		"          params:\n"+
+		"            passthrough: ['?In']\n"+
+		"<A>:\n"+
+		"  params: [In]\n"+
+		"  rhs:\n"+
+		"    -\n"+
+		"      - in:\n"+
+		"          params:\n"+
+		"            conditions: [+In]\n"+
+		"    - [out, <A>]\n"+
+		"    - [out]\n")
+	// The parameter is not passed on to the recursive <A> so only the first terminal can be in.
+	assertLanguage(t, grammar, "S[In]", nil, []string{"in", "out out"}, []string{"", "out in", "in in"})
+	assertLanguage(t, grammar, "S", nil, []string{"out", "out out"}, []string{"in", "out in"})
+}
+
+func TestCheckLookaheadsAndLineTerminators(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n"+
+		"  rhs:\n"+
+		"    -\n"+
+		"      - <*Lookahead*>:\n"+
+		"          params:\n"+
+		"            exclude:\n"+
+		"              - [let, '[']\n"+
+		"              - [async, <!LineTerminator!>, function]\n"+
+		"      - <A>\n"+
+		"      - ;\n"+
+		"    - [return, <!LineTerminator!>, x, ;]\n"+
+		"<A>:\n"+
+		"  rhs:\n"+
+		"    - [let, '[', ']']\n"+
+		"    - [let, x]\n"+
+		"    - [async, function]\n")
+	assertLanguage(t, grammar, "S", nil, []string{"let x ;", "return x ;"}, []string{"let [ ] ;", "let x", "async function ;"})
+	checker, err := NewChecker(grammar, "S", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := TerminalTokens("return x ;")
+	input[1].LineTerminatorBefore = true
+	if result := checker.Check(input); result.Accepted || result.Position != 1 || result.Token != &input[1] {
+		t.Errorf("Expected the input to be rejected at the token after the line terminator but got %v", result)
+	}
+	// The lookahead restriction only excludes async function without a line terminator between them.
+	input = TerminalTokens("async function ;")
+	input[1].LineTerminatorBefore = true
+	if result := checker.Check(input); !result.Accepted {
+		t.Errorf("Expected async followed by a line terminator and function to be accepted but got %v", result)
+	}
+}
+
+func TestCheckRejectedPosition(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n  rhs:\n    - [a, b, c]\n")
+	checker, err := NewChecker(grammar, "S", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := checker.Check(TerminalTokens("a c")); result.Position != 1 || result.String() != `rejected at token 1 "c"` {
+		t.Errorf("Expected the input to be rejected at c but got %v", result)
+	}
+	if result := checker.Check(TerminalTokens("a b")); result.Position != 2 || result.Token != nil {
+		t.Errorf("Expected the input to be rejected at the end of input but got %v", result)
+	}
+}
+
+func TestNewCheckerStartSymbols(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n  params: [In]\n  rhs:\n    - [x]\n")
+	for start, expected := range map[string]error{
+		"T":        ErrUnknownStartSymbol,
+		"S[Yield]": ErrUnknownParameter,
+		"S[In":     ErrMalformedStartSymbol,
+	} {
+		if _, err := NewChecker(grammar, start, nil); !errors.Is(err, expected) {
+			t.Errorf("Expected %v for %v but got %v", expected, start, err)
+		}
+	}
+}
+
+func TestTransformationsPreserveLanguage(t *testing.T) {
+	for _, name := range []string{"lf1", "lf2", "lf3", "lf4", "lf5"} {
+		original := loadFixtureInput(t, name)
+		factored := loadFixtureInput(t, name)
+		LeftFactor(factored)
+		assertSameLanguage(t, original, factored, original.Productions[0].Name, 4)
+	}
+	for _, name := range []string{"elr1", "elr2"} {
+		original := loadFixtureInput(t, name)
+		eliminated := loadFixtureInput(t, name)
+		EliminateLeftRecursion(eliminated)
+		assertSameLanguage(t, original, eliminated, original.Productions[0].Name, 4)
+	}
+}
+
+func TestCheckTransformed(t *testing.T) {
+	grammar := loadTestGrammar(t, expressionTestGrammar)
+	accepted := []string{"x ;", "x * x - x - x ;", "( x - x ) * x ;"}
+	rejected := []string{"x", "x - ;", "( x ;", "x * * x ;"}
+	assertLanguage(t, grammar, "S", nil, accepted, rejected)
+	assertLanguage(t, grammar, "S", &CheckOptions{Transformed: true}, accepted, rejected)
+}
+
+func TestCheckAcceptsSamples(t *testing.T) {
+	grammar, err := LoadFile("../parser/grammar.yml", "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker, err := NewChecker(grammar, "Script", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without any source text the terminals of the samples are written as their names.
+	sampler := NewSampler(grammar, &SampleOptions{Seed: 1, Terminals: map[string][]string{}}, "Script")
+	for i := 0; i < 20; i++ {
+		sample, err := sampler.Sample("Script")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := checker.Check(TerminalTokens(sample)); !result.Accepted {
+			t.Errorf("Expected the sample to be accepted but it was %v\n%v", result, sample)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package grammar
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ConflictKind provides a type alias to distinguish
+// between the kinds of LL conflicts.
+type ConflictKind int
+
+const (
+	_ ConflictKind = iota
+	// FirstFirstConflict is where two alternatives of a production
+	// can start with the same lookahead.
+	FirstFirstConflict
+	// FirstFollowConflict is where an alternative that can derive the
+	// empty string shares a lookahead with what may follow the production.
+	FirstFollowConflict
+)
+
+// String provides the name of the conflict kind.
+func (k ConflictKind) String() string {
+	switch k {
+	case FirstFirstConflict:
+		return "FIRST/FIRST"
+	case FirstFollowConflict:
+		return "FIRST/FOLLOW"
+	default:
+		return ""
+	}
+}
+
+// Conflict provides the details of a pair of alternatives of a production
+// which can not be chosen between with the lookahead available.
+type Conflict struct {
+	Kind ConflictKind
+	// Production holds the name of the production the alternatives belong to.
+	Production string
+	// Origin holds the name of the production in the source grammar
+	// the conflicting production was derived from.
+	Origin string
+	// Alternatives holds the indices of the two conflicting right-hand side rules.
+	Alternatives [2]int
+	Rules        [2][]RHSRuleSymbol
+	// Provenance holds where each of the conflicting alternatives
+	// came from in the source grammar.
+	Provenance [2]*Provenance
+	// Lookahead holds the lookahead sequences shared by both alternatives.
+	Lookahead *SequenceSet
+}
+
+// String provides a human-readable report of the conflict.
+func (c *Conflict) String() string {
+	report := c.Kind.String() + " conflict in " + c.Production
+	if c.Origin != c.Production {
+		report += " (from <" + c.Origin + ">)"
+	}
+	report += ":\n"
+	for i, alternative := range c.Alternatives {
+		report += "    alternative " + strconv.Itoa(alternative) + ": " +
+			strings.TrimSpace(sprintRule(c.Rules[i])) + "\n"
+		if c.Provenance[i] != nil {
+			report += "        from: " + c.Provenance[i].String() + "\n"
+		}
+	}
+	report += "    shared lookahead: " + strings.Join(c.Lookahead.Strings(), ", ") + "\n"
+	return report
+}
+
+// DetectConflicts deals with finding every pair of alternatives in the
+// grammar which are predicted by the same lookahead for the given analysis.
+// Lookaheads the parse table resolves with a lookahead restriction are not
+// conflicts, which is where the first of the pair can be rejected by a restriction
+// for the lookahead terminal so the second is tried as a fallback when it is.
+func DetectConflicts(grammar *Grammar, analysis *Analysis) []*Conflict {
+	// A grammar with undefined non-terminals has no parse table, so none
+	// of its conflicts can be resolved.
+	table, _ := BuildParseTable(grammar, analysis)
+	return detectConflicts(grammar, analysis, table)
+}
+
+// Provides the conflicts of the grammar for the given analysis
+// which are not resolved by the given parse table, if any.
+func detectConflicts(grammar *Grammar, analysis *Analysis, table *ParseTable) []*Conflict {
+	conflicts := []*Conflict{}
+	// Holds the index of the first rule of the production in the parse table,
+	// which holds the rules of each production in the same order.
+	ruleIndex := 0
+	for _, prod := range grammar.Productions {
+		predicts := []*SequenceSet{}
+		firsts := []*SequenceSet{}
+		for i, rule := range prod.RHS {
+			predicts = append(predicts, analysis.PredictOf(prod.Name, i))
+			firsts = append(firsts, analysis.FirstOfRule(rule))
+		}
+		for i := 0; i < len(prod.RHS); i++ {
+			for j := i + 1; j < len(prod.RHS); j++ {
+				shared := predicts[i].Intersect(predicts[j])
+				if table != nil {
+					shared = unresolved(table, ruleIndex+i, shared)
+				}
+				if shared.Len() > 0 {
+					kind := FirstFollowConflict
+					if hasNonEmpty(firsts[i].Intersect(firsts[j])) {
+						kind = FirstFirstConflict
+					}
+					conflicts = append(conflicts, &Conflict{
+						Kind:         kind,
+						Production:   prod.Name,
+						Origin:       prod.OriginName(),
+						Alternatives: [2]int{i, j},
+						Rules:        [2][]RHSRuleSymbol{prod.RHS[i], prod.RHS[j]},
+						Provenance:   [2]*Provenance{prod.ProvenanceOf(i), prod.ProvenanceOf(j)},
+						Lookahead:    shared,
+					})
+				}
+			}
+		}
+		ruleIndex += len(prod.RHS)
+	}
+	return conflicts
+}
+
+// Provides the lookaheads shared with a later rule which are not resolved by the rule
+// at the given index of the parse table being rejected by a lookahead restriction.
+func unresolved(table *ParseTable, ruleIndex int, shared *SequenceSet) *SequenceSet {
+	remaining := NewSequenceSet()
+	for _, seq := range shared.Sequences {
+		if len(seq) == 0 || !canReject(table, ruleIndex, seq[0], map[string]bool{}) {
+			remaining.Add(seq)
+		}
+	}
+	return remaining
+}
+
+// Determines whether the set holds a sequence
+// other than the empty string.
+func hasNonEmpty(set *SequenceSet) bool {
+	found := false
+	i := 0
+	for !found && i < len(set.Sequences) {
+		if len(set.Sequences[i]) > 0 {
+			found = true
+		}
+		i++
+	}
+	return found
+}
@@ -0,0 +1,146 @@
+package grammar
+
+import (
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestDetectFirstFirstConflicts(t *testing.T) {
+	input, expected := loadGrammarFixture("lf1")
+	// The fixture leaves <B> undefined so it is given a production of its own.
+	b := &Grammar{}
+	if err := yaml.Unmarshal([]byte("<B>:\n  rhs:\n    - [b]\n"), b); err != nil {
+		t.Fatal(err)
+	}
+	input.Productions = append(input.Productions, b.Productions...)
+	expected.Productions = append(expected.Productions, b.Productions...)
+	conflicts := DetectConflicts(input, Analyse(input, 1))
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict but got %v", len(conflicts))
+	}
+	conflict := conflicts[0]
+	if conflict.Kind != FirstFirstConflict || conflict.Production != "A" ||
+		conflict.Alternatives != [2]int{0, 1} {
+		t.Errorf("Expected a FIRST/FIRST conflict between alternatives 0 and 1 of A but got %v", conflict)
+	}
+	assertSequences(t, "shared lookahead", conflict.Lookahead, "b")
+	// Left factoring should resolve the conflict.
+	conflicts = DetectConflicts(expected, Analyse(expected, 1))
+	if len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts after left factoring but got %v", conflicts)
+	}
+}
+
+func TestConflictReport(t *testing.T) {
+	input := []byte(`
+<S>:
+  rhs:
+    - [<A>, a]
+<A>:
+  rhs:
+    - [a, <A>]
+    - [<B>, c]
+<B>:
+  rhs:
+    - [a]
+    - ['[empty]']
+`)
+	grammar := &Grammar{}
+	err := yaml.Unmarshal(input, grammar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	LeftFactor(grammar)
+	conflicts := DetectConflicts(grammar, Analyse(grammar, 1))
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict but got %v", conflicts)
+	}
+	conflict := conflicts[0]
+	if conflict.Kind != FirstFirstConflict || conflict.Production != "A" {
+		t.Errorf("Expected a FIRST/FIRST conflict in A but got %v", conflict)
+	}
+	report := conflict.String()
+	for _, expected := range []string{
+		"FIRST/FIRST conflict in A:", "alternative 0: a A", "alternative 1: B c", "shared lookahead: a",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected the report to contain %q but got\n%v", expected, report)
+		}
+	}
+}
+
+func TestDetectFirstFollowConflicts(t *testing.T) {
+	input := []byte(`
+<S>:
+  rhs:
+    - [<A>, b]
+<A>:
+  rhs:
+    - [b, <C>]
+    - ['[empty]']
+<C>:
+  rhs:
+    - [c]
+`)
+	grammar := &Grammar{}
+	err := yaml.Unmarshal(input, grammar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflicts := DetectConflicts(grammar, Analyse(grammar, 1))
+	if len(conflicts) != 1 || conflicts[0].Kind != FirstFollowConflict {
+		t.Fatalf("Expected a FIRST/FOLLOW conflict but got %v", conflicts)
+	}
+	assertSequences(t, "shared lookahead", conflicts[0].Lookahead, "b")
+}
+
+func TestDetectConflictsReportsOrigin(t *testing.T) {
+	input := []byte(`
+<A>:
+  rhs:
+    - [a, <B>]
+    - [a, <C>]
+<B>:
+  rhs:
+    - [b]
+<C>:
+  rhs:
+    - [b, c]
+`)
+	grammar := &Grammar{}
+	err := yaml.Unmarshal(input, grammar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	LLkify(grammar)
+	conflicts := DetectConflicts(grammar, Analyse(grammar, 1))
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict but got %v", conflicts)
+	}
+	if conflicts[0].Production != "AA0" || conflicts[0].Origin != "A" {
+		t.Errorf("Expected the conflict in AA0 to originate from A but got %v", conflicts[0])
+	}
+	if !strings.Contains(conflicts[0].String(), "FIRST/FIRST conflict in AA0 (from <A>):") {
+		t.Errorf("Expected the report to cite the source production but got\n%v", conflicts[0])
+	}
+}
+
+func TestDetectConflictsResolvedByLookahead(t *testing.T) {
+	grammar := loadTestGrammar(t, predicateTestGrammar)
+	if conflicts := DetectConflicts(grammar, Analyse(grammar, 1)); len(conflicts) != 0 {
+		t.Errorf("Expected the lookahead restrictions to resolve every conflict but got %v", conflicts)
+	}
+	// Without the exclusion starting with let the expression statement
+	// is always chosen for let, so the lexical declaration can't be.
+	unresolved := loadTestGrammar(t, strings.Replace(predicateTestGrammar, "[let, '['], ", "", 1))
+	conflicts := DetectConflicts(unresolved, Analyse(unresolved, 1))
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict but got %v", conflicts)
+	}
+	if conflicts[0].Production != "Statement" || conflicts[0].Alternatives != [2]int{0, 1} {
+		t.Errorf("Expected a conflict between alternatives 0 and 1 of Statement but got %v", conflicts[0])
+	}
+	assertSequences(t, "shared lookahead", conflicts[0].Lookahead, "let")
+}
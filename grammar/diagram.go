@@ -0,0 +1,388 @@
+package grammar
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	railCharWidth  = 8
+	railBoxHeight  = 22
+	railGap        = 10
+	railRadius     = 10
+	railLabelSpace = 18
+)
+
+var railroadStyle = `svg.railroad path { stroke-width: 2; stroke: #333; fill: none; }
+svg.railroad rect { stroke-width: 2; stroke: #333; fill: #fff; }
+svg.railroad rect.terminal { fill: #eef6ff; }
+svg.railroad rect.nonterminal { fill: #fff8e1; }
+svg.railroad rect.special { stroke-dasharray: 4 3; fill: #f4f4f4; }
+svg.railroad rect.group { stroke-dasharray: 6 4; stroke: #999; fill: none; }
+svg.railroad text { font: 13px monospace; text-anchor: middle; dominant-baseline: central; }
+svg.railroad text.label { font-size: 11px; fill: #666; text-anchor: start; }
+`
+
+// WriteDOT deals with writing the dependency graph between the non-terminals
+// of the grammar in the Graphviz DOT language.
+// Productions introduced by transformations are drawn with dashed borders
+// and grouped with the other productions derived from the same source production.
+func WriteDOT(w io.Writer, grammar *Grammar) error {
+	output := &bytes.Buffer{}
+	output.WriteString("digraph grammar {\n  rankdir=LR;\n  node [shape=box, fontname=monospace];\n")
+	origins := []string{}
+	derived := map[string][]*Production{}
+	productions := map[string]bool{}
+	for _, prod := range grammar.Productions {
+		productions[prod.Name] = true
+		if _, exists := derived[prod.OriginName()]; !exists {
+			origins = append(origins, prod.OriginName())
+		}
+		derived[prod.OriginName()] = append(derived[prod.OriginName()], prod)
+	}
+	for _, origin := range origins {
+		indent := "  "
+		if len(derived[origin]) > 1 {
+			fmt.Fprintf(output, "  subgraph %q {\n    label=%q;\n    style=dashed;\n", "cluster_"+origin, origin)
+			indent = "    "
+		}
+		for _, prod := range derived[origin] {
+			if prod.Name != prod.OriginName() {
+				fmt.Fprintf(output, "%v%q [style=dashed];\n", indent, prod.Name)
+			} else {
+				fmt.Fprintf(output, "%v%q;\n", indent, prod.Name)
+			}
+		}
+		if len(derived[origin]) > 1 {
+			output.WriteString("  }\n")
+		}
+	}
+	for _, prod := range grammar.Productions {
+		referenced := map[string]bool{}
+		for _, rule := range prod.RHS {
+			for _, symbol := range symbolsOf(rule) {
+				_, isNonTerminal := symbol.(*NonTerminalRHSRuleSymbol)
+				if isNonTerminal && productions[symbol.Name()] && !referenced[symbol.Name()] {
+					referenced[symbol.Name()] = true
+					fmt.Fprintf(output, "  %q -> %q;\n", prod.Name, symbol.Name())
+				}
+			}
+		}
+	}
+	output.WriteString("}\n")
+	_, err := w.Write(output.Bytes())
+	return err
+}
+
+// WriteRailroadHTML deals with writing a standalone HTML page holding
+// the railroad diagram of every production in the grammar,
+// non-terminals in the diagrams link to the diagram of their production.
+func WriteRailroadHTML(w io.Writer, grammar *Grammar) error {
+	output := &bytes.Buffer{}
+	output.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Grammar</title>\n<style>\n")
+	output.WriteString("body { font-family: sans-serif; }\nh2 { font-family: monospace; }\n")
+	output.WriteString(railroadStyle)
+	output.WriteString("</style>\n</head>\n<body>\n")
+	for _, prod := range grammar.Productions {
+		fmt.Fprintf(output, "<h2 id=\"%v\">%v</h2>\n", html.EscapeString(prod.Name), html.EscapeString(productionTitle(prod)))
+		writeRailroadSVG(output, prod, func(name string) string { return "#" + name }, false)
+	}
+	output.WriteString("</body>\n</html>\n")
+	_, err := w.Write(output.Bytes())
+	return err
+}
+
+// WriteRailroadSVG deals with writing a standalone SVG document holding the
+// railroad diagram of the given production, non-terminals link to
+// SVG documents named after their production in the same directory.
+func WriteRailroadSVG(w io.Writer, prod *Production) error {
+	output := &bytes.Buffer{}
+	output.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	writeRailroadSVG(output, prod, func(name string) string { return name + ".svg" }, true)
+	_, err := w.Write(output.Bytes())
+	return err
+}
+
+// Provides the name of the production along with its parameters and
+// the production it was derived from when it was introduced by a transformation.
+func productionTitle(prod *Production) string {
+	title := prod.Name
+	if len(prod.Params) > 0 {
+		title += "[" + strings.Join(prod.Params, ", ") + "]"
+	}
+	if prod.Name != prod.OriginName() {
+		title += " (from " + prod.OriginName() + ")"
+	}
+	return title
+}
+
+// Deals with writing the SVG element holding the railroad diagram of the production,
+// standalone documents hold their own title and styles.
+func writeRailroadSVG(output *bytes.Buffer, prod *Production, link func(string) string, standalone bool) {
+	alternatives := []railroadNode{}
+	for _, rule := range prod.RHS {
+		alternatives = append(alternatives, railroadRule(rule, link))
+	}
+	var diagram railroadNode = &railSequence{}
+	if len(alternatives) == 1 {
+		diagram = alternatives[0]
+	} else if len(alternatives) > 1 {
+		diagram = &railChoice{items: alternatives}
+	}
+	width, up, down := diagram.size()
+	top := railGap
+	if standalone {
+		top += railLabelSpace
+	}
+	totalWidth := width + 4*railGap
+	totalHeight := top + up + down + railGap
+	fmt.Fprintf(
+		output, "<svg class=\"railroad\" xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" viewBox=\"0 0 %v %v\">\n",
+		totalWidth, totalHeight, totalWidth, totalHeight,
+	)
+	if standalone {
+		fmt.Fprintf(output, "<style>\n%v</style>\n", railroadStyle)
+		fmt.Fprintf(output, "<text class=\"label\" x=\"%v\" y=\"%v\">%v</text>\n", railGap, railGap+railLabelSpace/2, html.EscapeString(productionTitle(prod)))
+	}
+	y := top + up
+	// The start and end of the diagram are marked by vertical bars.
+	fmt.Fprintf(output, "<path d=\"M%v %vv%v M%v %vh%v\"/>\n", railGap, y-railGap, 2*railGap, railGap, y, railGap)
+	diagram.render(output, 2*railGap, y)
+	end := 2*railGap + width
+	fmt.Fprintf(output, "<path d=\"M%v %vh%v M%v %vv%v\"/>\n", end, y, railGap, end+railGap, y-railGap, 2*railGap)
+	output.WriteString("</svg>\n")
+}
+
+// Provides the railroad diagram node for a sequence of right-hand side symbols.
+func railroadRule(rule []RHSRuleSymbol, link func(string) string) railroadNode {
+	sequence := &railSequence{}
+	for _, symbol := range rule {
+		sequence.items = append(sequence.items, railroadSymbol(symbol, link))
+	}
+	return sequence
+}
+
+// Provides the railroad diagram node for a single right-hand side symbol.
+func railroadSymbol(symbol RHSRuleSymbol, link func(string) string) railroadNode {
+	switch s := symbol.(type) {
+	case *NonTerminalRHSRuleSymbol:
+		var node railroadNode = &railText{text: s.name, class: "nonterminal", href: link(s.name)}
+		if s.params == nil {
+			return node
+		}
+		if len(s.params.Passthrough) > 0 {
+			node.(*railText).text += "[" + strings.Join(s.params.Passthrough, ", ") + "]"
+		}
+		if s.params.Optional != nil && *s.params.Optional {
+			node = &railChoice{items: []railroadNode{&railSequence{}, node}}
+		}
+		if len(s.params.Conditions) > 0 {
+			node = &railGroup{label: "[" + strings.Join(s.params.Conditions, ", ") + "]", item: node}
+		}
+		return node
+	case *TerminalRHSRuleSymbol:
+		if s.name == Epsilon {
+			return &railSequence{}
+		}
+		var node railroadNode = &railText{text: s.name, class: "terminal"}
+		if s.params != nil && len(s.params.Conditions) > 0 {
+			node = &railGroup{label: "[" + strings.Join(s.params.Conditions, ", ") + "]", item: node}
+		}
+		return node
+	case *ExcludeRHSRuleSymbol:
+		return &railText{text: "no " + s.name + " here", class: "special"}
+	case *LookaheadRHSRuleSymbol:
+		exclusions := []string{}
+		if s.params != nil {
+			for _, exclusion := range s.params.Exclude {
+				exclusions = append(exclusions, strings.TrimSpace(sprintRule(exclusion)))
+			}
+		}
+		return &railText{text: "lookahead ∉ { " + strings.Join(exclusions, ", ") + " }", class: "special"}
+	case *ConditionalRHSRuleSymbol:
+		label := ""
+		if s.params != nil {
+			label = "[" + strings.Join(s.params.Conditions, ", ") + "]"
+		}
+		return &railGroup{label: label, item: railroadRule(s.Parts, link)}
+	default:
+		return &railText{text: symbol.Name(), class: "special"}
+	}
+}
+
+// Provides the layout and rendering of a part of a railroad diagram,
+// the track enters a node at its left on the baseline and leaves on
+// the right at the same height.
+type railroadNode interface {
+	// Provides the width of the node along with its
+	// height above and below the baseline.
+	size() (int, int, int)
+	// Deals with rendering the node with its left edge
+	// at x and its baseline at y.
+	render(output *bytes.Buffer, x int, y int)
+}
+
+// A box holding a terminal, non-terminal or a special symbol.
+type railText struct {
+	text  string
+	class string
+	href  string
+}
+
+func (n *railText) size() (int, int, int) {
+	return utf8.RuneCountInString(n.text)*railCharWidth + 2*railGap, railBoxHeight / 2, railBoxHeight / 2
+}
+
+func (n *railText) render(output *bytes.Buffer, x int, y int) {
+	width, _, _ := n.size()
+	rounded := 0
+	if n.class == "terminal" {
+		rounded = railBoxHeight / 2
+	}
+	if n.href != "" {
+		fmt.Fprintf(output, "<a href=\"%v\">", html.EscapeString(n.href))
+	}
+	fmt.Fprintf(
+		output, "<rect class=\"%v\" x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" rx=\"%v\"/>"+
+			"<text x=\"%v\" y=\"%v\">%v</text>",
+		n.class, x, y-railBoxHeight/2, width, railBoxHeight, rounded, x+width/2, y, html.EscapeString(n.text),
+	)
+	if n.href != "" {
+		output.WriteString("</a>")
+	}
+	output.WriteString("\n")
+}
+
+// A sequence of nodes joined by the track, an empty
+// sequence is a track that skips over a choice.
+type railSequence struct {
+	items []railroadNode
+}
+
+func (n *railSequence) size() (int, int, int) {
+	width, up, down := 0, 0, 0
+	for i, item := range n.items {
+		itemWidth, itemUp, itemDown := item.size()
+		if i > 0 {
+			width += railGap
+		}
+		width += itemWidth
+		up = maxInt(up, itemUp)
+		down = maxInt(down, itemDown)
+	}
+	return width, up, down
+}
+
+func (n *railSequence) render(output *bytes.Buffer, x int, y int) {
+	for i, item := range n.items {
+		if i > 0 {
+			fmt.Fprintf(output, "<path d=\"M%v %vh%v\"/>\n", x, y, railGap)
+			x += railGap
+		}
+		item.render(output, x, y)
+		width, _, _ := item.size()
+		x += width
+	}
+}
+
+// A choice between nodes, the first node is on the baseline
+// and the remaining nodes branch off below it.
+type railChoice struct {
+	items []railroadNode
+}
+
+// Provides the offset of the baseline of each item
+// from the baseline of the choice.
+func (n *railChoice) offsets() []int {
+	offsets := []int{}
+	offset := 0
+	for i, item := range n.items {
+		_, up, _ := item.size()
+		if i > 0 {
+			// Leave room for the curves of the track to reach the item.
+			_, _, prevDown := n.items[i-1].size()
+			offset += maxInt(prevDown+railGap+up, 2*railRadius)
+		}
+		offsets = append(offsets, offset)
+	}
+	return offsets
+}
+
+func (n *railChoice) size() (int, int, int) {
+	width, up, down := 0, 0, 0
+	offsets := n.offsets()
+	for i, item := range n.items {
+		itemWidth, itemUp, itemDown := item.size()
+		width = maxInt(width, itemWidth)
+		if i == 0 {
+			up = itemUp
+		}
+		down = maxInt(down, offsets[i]+itemDown)
+	}
+	return width + 4*railRadius, up, down
+}
+
+func (n *railChoice) render(output *bytes.Buffer, x int, y int) {
+	width, _, _ := n.size()
+	inner := width - 4*railRadius
+	for i, item := range n.items {
+		itemWidth, _, _ := item.size()
+		itemY := y + n.offsets()[i]
+		if i == 0 {
+			fmt.Fprintf(output, "<path d=\"M%v %vh%v\"/>\n", x, y, 2*railRadius)
+		} else {
+			fmt.Fprintf(
+				output, "<path d=\"M%v %va%v %v 0 0 1 %v %vV%va%v %v 0 0 0 %v %v\"/>\n",
+				x, y, railRadius, railRadius, railRadius, railRadius,
+				itemY-railRadius, railRadius, railRadius, railRadius, railRadius,
+			)
+		}
+		item.render(output, x+2*railRadius, itemY)
+		right := x + 2*railRadius + itemWidth
+		if i == 0 {
+			fmt.Fprintf(output, "<path d=\"M%v %vH%v\"/>\n", right, y, x+width)
+		} else {
+			fmt.Fprintf(
+				output, "<path d=\"M%v %vH%va%v %v 0 0 0 %v %vV%va%v %v 0 0 1 %v %v\"/>\n",
+				right, itemY, x+2*railRadius+inner, railRadius, railRadius, railRadius, -railRadius,
+				y+railRadius, railRadius, railRadius, railRadius, -railRadius,
+			)
+		}
+	}
+}
+
+// A labelled box around a node used for the conditions a node depends on.
+type railGroup struct {
+	label string
+	item  railroadNode
+}
+
+func (n *railGroup) size() (int, int, int) {
+	width, up, down := n.item.size()
+	labelWidth := utf8.RuneCountInString(n.label) * railCharWidth
+	return maxInt(width, labelWidth) + 2*railGap, up + railLabelSpace, down + railGap
+}
+
+func (n *railGroup) render(output *bytes.Buffer, x int, y int) {
+	width, up, down := n.size()
+	itemWidth, _, _ := n.item.size()
+	fmt.Fprintf(
+		output, "<rect class=\"group\" x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\"/>"+
+			"<text class=\"label\" x=\"%v\" y=\"%v\">%v</text>\n",
+		x, y-up, width, up+down, x+4, y-up+railLabelSpace/2, html.EscapeString(n.label),
+	)
+	fmt.Fprintf(output, "<path d=\"M%v %vh%v\"/>\n", x, y, railGap)
+	n.item.render(output, x+railGap, y)
+	fmt.Fprintf(output, "<path d=\"M%v %vH%v\"/>\n", x+railGap+itemWidth, y, x+width)
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
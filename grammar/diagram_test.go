@@ -0,0 +1,119 @@
+package grammar
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Asserts the data is well formed XML.
+func assertWellFormed(t *testing.T, data []byte) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("Expected well formed XML but got %v in \n%s", err, data)
+		}
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	input, _ := loadGrammarFixture("elr1")
+	LLkify(input)
+	output := &bytes.Buffer{}
+	err := WriteDOT(output, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dot := output.String()
+	expected := []string{
+		"subgraph \"cluster_E\" {\n    label=\"E\";\n    style=dashed;\n    \"E\";\n    \"E'\" [style=dashed];\n  }\n",
+		"  \"E\" -> \"T\";\n  \"E\" -> \"E'\";\n",
+		"  \"F\" -> \"E\";\n",
+	}
+	for _, part := range expected {
+		if !strings.Contains(dot, part) {
+			t.Errorf("Expected the graph to contain %q but got \n%v", part, dot)
+		}
+	}
+	if strings.Contains(dot, "-> \"id\"") {
+		t.Errorf("Expected terminals to be left out of the graph but got \n%v", dot)
+	}
+}
+
+func TestWriteRailroadSVG(t *testing.T) {
+	input := "<S>:\n" +
+		"  params: [In]\n" +
+		"  rhs:\n" +
+		"    -\n" +
+		"      - <*Lookahead*>:\n" +
+		"          params:\n" +
+		"            exclude: [['{'], [async, <!LineTerminator!>, function]]\n" +
+		"      - <T>:\n" +
+		"          params:\n" +
+		"            passthrough: ['?In']\n" +
+		"            optional: true\n" +
+		"      - '<'\n" +
+		"    -\n" +
+		"      - <*Conditional*>:\n" +
+		"          params:\n" +
+		"            conditions: [+In]\n" +
+		"          parts: [in, <!LineTerminator!>, <T>]\n" +
+		"    - ['[empty]']\n" +
+		"<T>:\n" +
+		"  params: [In]\n" +
+		"  rhs: [[t]]\n"
+	grammar, err := LoadBytes([]byte(input), "yaml", "s.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := &bytes.Buffer{}
+	err = WriteRailroadSVG(output, grammar.Productions[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertWellFormed(t, output.Bytes())
+	expected := []string{
+		">S[In]</text>",
+		">lookahead ∉ { {, async [no LineTerminator here] function }</text>",
+		"<a href=\"T.svg\"><rect class=\"nonterminal\"",
+		">T[?In]</text>",
+		">&lt;</text>",
+		">[+In]</text>",
+		">no LineTerminator here</text>",
+	}
+	for _, part := range expected {
+		if !strings.Contains(output.String(), part) {
+			t.Errorf("Expected the diagram to contain %q but got \n%v", part, output)
+		}
+	}
+}
+
+func TestWriteRailroadHTML(t *testing.T) {
+	input, _ := loadGrammarFixture("elr1")
+	LLkify(input)
+	output := &bytes.Buffer{}
+	err := WriteRailroadHTML(output, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := output.String()
+	expected := []string{
+		"<h2 id=\"E&#39;\">E&#39; (from E)</h2>",
+		"<a href=\"#E&#39;\"><rect class=\"nonterminal\"",
+		"<rect class=\"terminal\"",
+	}
+	for _, part := range expected {
+		if !strings.Contains(page, part) {
+			t.Errorf("Expected the page to contain %q but got \n%v", part, page)
+		}
+	}
+	body := page[strings.Index(page, "<body>"):strings.Index(page, "</html>")]
+	assertWellFormed(t, []byte(body))
+}
@@ -0,0 +1,292 @@
+package grammar
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ChangeKind provides a type alias to distinguish
+// between the kinds of changes made to a grammar.
+type ChangeKind int
+
+const (
+	_ ChangeKind = iota
+	// ProductionAdded is where a production only exists in the new grammar.
+	ProductionAdded
+	// ProductionRemoved is where a production only exists in the old grammar.
+	ProductionRemoved
+	// ParamsChanged is where the parameters a production declares have changed.
+	ParamsChanged
+	// AlternativeAdded is where a right-hand side rule only exists in the new grammar.
+	AlternativeAdded
+	// AlternativeRemoved is where a right-hand side rule only exists in the old grammar.
+	AlternativeRemoved
+	// AlternativeChanged is where the symbols of a right-hand side rule are the same
+	// but the arguments, conditions, optional markers or lookaheads have changed.
+	AlternativeChanged
+)
+
+// String provides the name of the change kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case ProductionAdded:
+		return "added production"
+	case ProductionRemoved:
+		return "removed production"
+	case ParamsChanged:
+		return "changed params"
+	case AlternativeAdded:
+		return "added alternative"
+	case AlternativeRemoved:
+		return "removed alternative"
+	case AlternativeChanged:
+		return "changed alternative"
+	default:
+		return ""
+	}
+}
+
+// Change provides the details of a semantic change
+// to a single production of a grammar.
+type Change struct {
+	Kind ChangeKind
+	// Production holds the name of the production which changed.
+	Production string
+	// Old holds the parameters or right-hand side rule before the change
+	// in the notation of the ECMAScript specification, empty for additions.
+	Old string
+	// New holds the parameters or right-hand side rule after the change
+	// in the notation of the ECMAScript specification, empty for removals.
+	New string
+}
+
+// String provides a human-readable report of the change.
+func (c *Change) String() string {
+	report := "<" + c.Production + ">: " + c.Kind.String()
+	switch {
+	case c.Old != "" && c.New != "":
+		report += " " + c.Old + " → " + c.New
+	case c.Old != "":
+		report += " " + c.Old
+	case c.New != "":
+		report += " " + c.New
+	}
+	return report
+}
+
+// Diff deals with finding the semantic changes between two grammars, changes
+// are reported production by production in the order of the old grammar
+// followed by the productions which have been added to the new grammar.
+// The order of the alternatives of a production is not taken into account.
+func Diff(before *Grammar, after *Grammar) []*Change {
+	oldProductions := map[string]*Production{}
+	for _, prod := range before.Productions {
+		oldProductions[prod.Name] = prod
+	}
+	newProductions := map[string]*Production{}
+	for _, prod := range after.Productions {
+		newProductions[prod.Name] = prod
+	}
+	changes := []*Change{}
+	for _, oldProd := range before.Productions {
+		newProd, exists := newProductions[oldProd.Name]
+		if !exists {
+			changes = append(changes, &Change{Kind: ProductionRemoved, Production: oldProd.Name, Old: paramsText(oldProd.Params)})
+			for _, rule := range oldProd.RHS {
+				changes = append(changes, &Change{Kind: AlternativeRemoved, Production: oldProd.Name, Old: ruleText(rule)})
+			}
+		} else {
+			changes = append(changes, diffProduction(oldProd, newProd)...)
+		}
+	}
+	for _, newProd := range after.Productions {
+		if _, exists := oldProductions[newProd.Name]; !exists {
+			changes = append(changes, &Change{Kind: ProductionAdded, Production: newProd.Name, New: paramsText(newProd.Params)})
+			for _, rule := range newProd.RHS {
+				changes = append(changes, &Change{Kind: AlternativeAdded, Production: newProd.Name, New: ruleText(rule)})
+			}
+		}
+	}
+	return changes
+}
+
+// Provides the changes between two versions of the same production, a removed
+// and an added alternative made up of the same symbols are reported as a change.
+func diffProduction(oldProd *Production, newProd *Production) []*Change {
+	changes := []*Change{}
+	if paramsText(oldProd.Params) != paramsText(newProd.Params) {
+		changes = append(changes, &Change{
+			Kind:       ParamsChanged,
+			Production: newProd.Name,
+			Old:        paramsText(oldProd.Params),
+			New:        paramsText(newProd.Params),
+		})
+	}
+	removed := unmatchedRules(oldProd.RHS, newProd.RHS)
+	added := unmatchedRules(newProd.RHS, oldProd.RHS)
+	for _, oldRule := range removed {
+		found := false
+		i := 0
+		for !found && i < len(added) {
+			if added[i] != nil && ruleShape(added[i]) == ruleShape(oldRule) {
+				found = true
+				changes = append(changes, &Change{
+					Kind:       AlternativeChanged,
+					Production: newProd.Name,
+					Old:        ruleText(oldRule),
+					New:        ruleText(added[i]),
+				})
+				added[i] = nil
+			}
+			i++
+		}
+		if !found {
+			changes = append(changes, &Change{Kind: AlternativeRemoved, Production: newProd.Name, Old: ruleText(oldRule)})
+		}
+	}
+	for _, newRule := range added {
+		if newRule != nil {
+			changes = append(changes, &Change{Kind: AlternativeAdded, Production: newProd.Name, New: ruleText(newRule)})
+		}
+	}
+	return changes
+}
+
+// Provides the rules which have no identical counterpart in the other set of rules,
+// each rule of the other set can only be the counterpart of a single rule.
+func unmatchedRules(rules [][]RHSRuleSymbol, others [][]RHSRuleSymbol) [][]RHSRuleSymbol {
+	remaining := map[string]int{}
+	for _, rule := range others {
+		remaining[ruleText(rule)]++
+	}
+	unmatched := [][]RHSRuleSymbol{}
+	for _, rule := range rules {
+		text := ruleText(rule)
+		if remaining[text] > 0 {
+			remaining[text]--
+		} else {
+			unmatched = append(unmatched, rule)
+		}
+	}
+	return unmatched
+}
+
+// Provides the names of the symbols that make up a rule without their
+// arguments or conditions, used to pair up alternatives which have changed.
+func ruleShape(rule []RHSRuleSymbol) string {
+	names := []string{}
+	for _, symbol := range flattenRule(rule) {
+		if symbol.Name() != "" {
+			names = append(names, symbol.Name())
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+// Provides the declared parameters of a production in
+// the notation of the ECMAScript specification.
+func paramsText(params []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+// Provides a right-hand side rule in the notation of the ECMAScript
+// specification, including the arguments and conditions of its symbols.
+func ruleText(rule []RHSRuleSymbol) string {
+	symbols := []string{}
+	for _, symbol := range rule {
+		symbols = append(symbols, symbolText(symbol))
+	}
+	return strings.Join(symbols, " ")
+}
+
+// Provides a single right-hand side symbol in the
+// notation of the ECMAScript specification.
+func symbolText(symbol RHSRuleSymbol) string {
+	switch s := symbol.(type) {
+	case *NonTerminalRHSRuleSymbol:
+		text := s.name
+		if s.params == nil {
+			return text
+		}
+		text += paramsText(s.params.Passthrough)
+		if s.params.Optional != nil && *s.params.Optional {
+			text += "opt"
+		}
+		if len(s.params.Conditions) > 0 {
+			text = paramsText(s.params.Conditions) + " " + text
+		}
+		return text
+	case *TerminalRHSRuleSymbol:
+		text := s.name
+		if r, _ := utf8.DecodeRuneInString(s.name); s.name != Epsilon && !unicode.IsUpper(r) {
+			// Lexical grammar non-terminals such as IdentifierName are not quoted.
+			text = "`" + s.name + "`"
+		}
+		if s.params != nil && len(s.params.Conditions) > 0 {
+			text = paramsText(s.params.Conditions) + " " + text
+		}
+		return text
+	case *ExcludeRHSRuleSymbol:
+		return "[no " + s.name + " here]"
+	case *LookaheadRHSRuleSymbol:
+		exclusions := []string{}
+		if s.params != nil {
+			for _, exclusion := range s.params.Exclude {
+				exclusions = append(exclusions, ruleText(exclusion))
+			}
+		}
+		return "[lookahead ∉ { " + strings.Join(exclusions, ", ") + " }]"
+	case *ConditionalRHSRuleSymbol:
+		text := "{ " + ruleText(s.Parts) + " }"
+		if s.params != nil {
+			text = paramsText(s.params.Conditions) + " " + text
+		}
+		return text
+	default:
+		return symbol.Name()
+	}
+}
+
+// DiffConflicts deals with finding the conflicts which have been introduced
+// and those which have been resolved between two sets of conflicts.
+// Conflicts are compared by their kind, production, rules and shared lookahead
+// so conflicts between alternatives which have only been reordered are not reported.
+func DiffConflicts(before []*Conflict, after []*Conflict) ([]*Conflict, []*Conflict) {
+	oldKeys := map[string]bool{}
+	for _, conflict := range before {
+		oldKeys[conflictKey(conflict)] = true
+	}
+	newKeys := map[string]bool{}
+	introduced := []*Conflict{}
+	for _, conflict := range after {
+		newKeys[conflictKey(conflict)] = true
+		if !oldKeys[conflictKey(conflict)] {
+			introduced = append(introduced, conflict)
+		}
+	}
+	resolved := []*Conflict{}
+	for _, conflict := range before {
+		if !newKeys[conflictKey(conflict)] {
+			resolved = append(resolved, conflict)
+		}
+	}
+	return introduced, resolved
+}
+
+// Provides the key a conflict is identified by between versions of a grammar,
+// the rules of the conflict are ordered so swapped alternatives match.
+func conflictKey(conflict *Conflict) string {
+	rules := []string{ruleText(conflict.Rules[0]), ruleText(conflict.Rules[1])}
+	if rules[0] > rules[1] {
+		rules[0], rules[1] = rules[1], rules[0]
+	}
+	lookahead := conflict.Lookahead.Strings()
+	sort.Strings(lookahead)
+	return conflict.Kind.String() + "|" + conflict.Production + "|" +
+		strings.Join(rules, "|") + "|" + strings.Join(lookahead, ",")
+}
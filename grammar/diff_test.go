@@ -0,0 +1,93 @@
+package grammar
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := loadTestGrammar(t, "<Statement>:\n"+
+		"  params: [Yield]\n"+
+		"  rhs:\n"+
+		"    - [<Block>]\n"+
+		"    -\n"+
+		"      - <Expression>:\n"+
+		"          params:\n"+
+		"            passthrough: [+In]\n"+
+		"      - ;\n"+
+		"    - [debugger, ;]\n"+
+		"<Block>:\n"+
+		"  rhs:\n"+
+		"    - ['{', '}']\n"+
+		"<Expression>:\n"+
+		"  params: [In]\n"+
+		"  rhs:\n"+
+		"    - [IdentifierName]\n"+
+		"<With>:\n"+
+		"  rhs:\n"+
+		"    - [with]\n")
+	after := loadTestGrammar(t, "<Statement>:\n"+
+		"  params: [Yield, Return]\n"+
+		"  rhs:\n"+
+		"    - [debugger, ;]\n"+
+		"    - [<Block>]\n"+
+		"    -\n"+
+		"      - <Expression>:\n"+
+		"          params:\n"+
+		"            passthrough: ['?In']\n"+
+		"      - ;\n"+
+		"    -\n"+
+		"      - <Return>:\n"+
+		"          params:\n"+
+		"            conditions: [+Return]\n"+
+		"<Block>:\n"+
+		"  rhs:\n"+
+		"    - ['{', '}']\n"+
+		"<Expression>:\n"+
+		"  params: [In]\n"+
+		"  rhs:\n"+
+		"    - [IdentifierName]\n"+
+		"<Return>:\n"+
+		"  rhs:\n"+
+		"    - [return, ;]\n")
+	expected := []string{
+		"<Statement>: changed params [Yield] → [Yield, Return]",
+		"<Statement>: changed alternative Expression[+In] `;` → Expression[?In] `;`",
+		"<Statement>: added alternative [+Return] Return",
+		"<With>: removed production",
+		"<With>: removed alternative `with`",
+		"<Return>: added production",
+		"<Return>: added alternative `return` `;`",
+	}
+	changes := Diff(before, after)
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %v changes but got %v", len(expected), changes)
+	}
+	for i, change := range changes {
+		if change.String() != expected[i] {
+			t.Errorf("Expected change %q but got %q", expected[i], change)
+		}
+	}
+	if len(Diff(after, after)) != 0 {
+		t.Errorf("Expected no changes between a grammar and itself")
+	}
+}
+
+func TestDiffConflicts(t *testing.T) {
+	conflictsOf := func(input string) []*Conflict {
+		grammar := loadTestGrammar(t, input)
+		return DetectConflicts(grammar, Analyse(grammar, 1))
+	}
+	before := conflictsOf("<S>:\n  rhs:\n    - [a, b]\n    - [a, c]\n    - [d]\n")
+	after := conflictsOf("<S>:\n  rhs:\n    - [d]\n    - [a, c]\n    - [a, b]\n    - [d, e]\n")
+	introduced, resolved := DiffConflicts(before, after)
+	if len(introduced) != 1 || introduced[0].Lookahead.Strings()[0] != "d" {
+		t.Errorf("Expected only the conflict on d to be introduced but got %v", introduced)
+	}
+	if len(resolved) != 0 {
+		t.Errorf("Expected the reordered conflict not to be resolved but got %v", resolved)
+	}
+	introduced, resolved = DiffConflicts(after, before)
+	if len(introduced) != 0 || len(resolved) != 1 {
+		t.Errorf("Expected the conflict on d to be resolved but got %v and %v", introduced, resolved)
+	}
+}
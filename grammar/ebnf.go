@@ -0,0 +1,421 @@
+package grammar
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidNotation provides the error for the case when a grammar
+	// written in the specification notation can not be parsed.
+	ErrInvalidNotation = errors.New("invalid grammar notation")
+
+	ebnfHeaderPattern    = regexp.MustCompile(`^([A-Za-z_]\w*)(?:\[([^\]]*)\])?\s*:+\s*(.*)$`)
+	ebnfLookaheadPattern = regexp.MustCompile(`^lookahead\s*(∉|not in|≠|!=)\s*(.*)$`)
+	ebnfNoHerePattern    = regexp.MustCompile(`^no\s+(\w+)\s+here$`)
+)
+
+// Holds the state of parsing a single right-hand side
+// rule written in the specification notation.
+type ebnfRule struct {
+	text    string
+	pos     int
+	line    int
+	indent  int
+	defined map[string]bool
+}
+
+// Deals with parsing a grammar written in the notation used by the
+// ECMAScript specification, where each production starts with an
+// unindented header such as `Statement[Yield, Await, Return] :`
+// followed by one indented line for each right-hand side alternative.
+//
+// Within an alternative, terminals are either quoted in backticks or are names
+// without a production in the grammar (lexical grammar non-terminals),
+// non-terminals take arguments as in `BindingIdentifier[?Yield, +In]` and are
+// made optional with an `opt` or `?` suffix. A leading condition such as `[+Return]`
+// applies to the whole alternative and `[lookahead ∉ { `let`, `[` }]`,
+// `[no LineTerminator here]` and `[empty]` are supported along with
+// `one of` headers and `//` comments.
+//
+// Every malformed production is reported rather than stopping at the first.
+func parseEBNF(data []byte) (*Grammar, []*BuildError) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	defined := map[string]bool{}
+	for _, line := range lines {
+		if match := ebnfHeaderPattern.FindStringSubmatch(line); match != nil {
+			defined[match[1]] = true
+		}
+	}
+	g := &Grammar{}
+	errs := []*BuildError{}
+	var prod *Production
+	oneOf := false
+	failed := false
+	for i, line := range lines {
+		text := line
+		if index := strings.Index(text, "//"); index >= 0 && !insideBackticks(text, index) {
+			text = text[:index]
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+		indent := len(text) - len(trimmed)
+		if indent == 0 {
+			match := ebnfHeaderPattern.FindStringSubmatch(strings.TrimRightFunc(text, unicode.IsSpace))
+			if match == nil {
+				prod = nil
+				errs = append(errs, &BuildError{
+					Line: i + 1, Column: 1,
+					Err: fmt.Errorf("%w: expected a production of the form Name[Params] :", ErrInvalidNotation),
+				})
+				continue
+			}
+			prod = &Production{Name: match[1], Params: splitList(match[2])}
+			g.Productions = append(g.Productions, prod)
+			failed = false
+			oneOf = match[3] == "one of"
+			if match[3] != "" && !oneOf {
+				trimmed = match[3]
+				indent = strings.LastIndex(text, match[3])
+			} else {
+				continue
+			}
+		}
+		if prod == nil || failed {
+			continue
+		}
+		if oneOf {
+			for _, name := range strings.Fields(trimmed) {
+				rule := []RHSRuleSymbol{&TerminalRHSRuleSymbol{name: strings.Trim(name, "`")}}
+				prod.RuleProvenance = append(prod.RuleProvenance, &Provenance{
+					Line: i + 1, Production: prod.Name, Rule: ruleText(rule), Alternative: len(prod.RHS),
+				})
+				prod.RHS = append(prod.RHS, rule)
+			}
+			continue
+		}
+		rule := &ebnfRule{text: strings.TrimRightFunc(trimmed, unicode.IsSpace), line: i + 1, indent: indent, defined: defined}
+		symbols, err := rule.parse()
+		if err != nil {
+			// The production is left out entirely so it is not
+			// mistaken for a production with fewer alternatives.
+			g.Productions = g.Productions[:len(g.Productions)-1]
+			failed = true
+			err.Production = prod.Name
+			errs = append(errs, err)
+		} else {
+			prod.RuleProvenance = append(prod.RuleProvenance, &Provenance{
+				Line: i + 1, Production: prod.Name, Rule: ruleText(symbols), Alternative: len(prod.RHS),
+			})
+			prod.RHS = append(prod.RHS, symbols)
+		}
+	}
+	return g, errs
+}
+
+// Deals with parsing the alternative, applying a leading condition to the
+// single symbol that follows it or to a conditional holding the remaining symbols.
+func (r *ebnfRule) parse() ([]RHSRuleSymbol, *BuildError) {
+	var conditions []string
+	if strings.HasPrefix(r.text, "[+") || strings.HasPrefix(r.text, "[~") {
+		content, err := r.bracket()
+		if err != nil {
+			return nil, err
+		}
+		conditions = splitList(content)
+		if checkErr := checkPrefixes(conditions, ErrInvalidNotation, "the conditions", "+~"); checkErr != nil {
+			return nil, r.errorAt(checkErr)
+		}
+	}
+	symbols := []RHSRuleSymbol{}
+	r.skipSpace()
+	for r.pos < len(r.text) {
+		start := r.pos
+		symbol, err := r.symbol()
+		if err != nil {
+			return nil, err
+		}
+		if symbol != nil {
+			symbols = append(symbols, symbol)
+		} else if len(symbols) == 0 || !setOptional(symbols[len(symbols)-1]) {
+			// An opt suffix must be separated from the non-terminal it applies to.
+			r.pos = start
+			return nil, r.errorf("%w: only non-terminals can be optional", ErrInvalidNotation)
+		}
+		r.skipSpace()
+	}
+	if len(symbols) == 0 {
+		return nil, r.errorf("%w: expected at least one symbol", ErrInvalidNotation)
+	}
+	if conditions == nil {
+		return symbols, nil
+	}
+	if len(symbols) == 1 {
+		switch symbol := symbols[0].(type) {
+		case *NonTerminalRHSRuleSymbol:
+			if symbol.params == nil {
+				symbol.params = &NtRHSParams{}
+			}
+			symbol.params.Conditions = conditions
+			return symbols, nil
+		case *TerminalRHSRuleSymbol:
+			symbol.params = &TRHSParams{Conditions: conditions}
+			return symbols, nil
+		}
+	}
+	for _, symbol := range symbols {
+		if _, isLookahead := symbol.(*LookaheadRHSRuleSymbol); isLookahead {
+			return nil, r.errorf("%w: a lookahead can not be used in a conditional alternative", ErrInvalidNotation)
+		}
+	}
+	return []RHSRuleSymbol{&ConditionalRHSRuleSymbol{
+		params: &CRHSParams{Conditions: conditions},
+		Parts:  symbols,
+	}}, nil
+}
+
+// Deals with parsing the symbol at the current position, a nil symbol
+// is returned for a standalone opt suffix.
+func (r *ebnfRule) symbol() (RHSRuleSymbol, *BuildError) {
+	c, _ := utf8.DecodeRuneInString(r.text[r.pos:])
+	switch {
+	case c == '`':
+		name, err := r.terminal()
+		if err != nil {
+			return nil, err
+		}
+		return &TerminalRHSRuleSymbol{name: name}, nil
+	case c == '[':
+		return r.operation()
+	case c == '_' || unicode.IsLetter(c):
+		return r.name()
+	default:
+		// Punctuators are accepted without backticks
+		// as long as they are separated by spaces.
+		end := strings.IndexFunc(r.text[r.pos:], unicode.IsSpace)
+		if end < 0 {
+			end = len(r.text) - r.pos
+		}
+		name := r.text[r.pos : r.pos+end]
+		r.pos += end
+		return &TerminalRHSRuleSymbol{name: name}, nil
+	}
+}
+
+// Deals with parsing a terminal quoted in backticks.
+func (r *ebnfRule) terminal() (string, *BuildError) {
+	end := strings.IndexRune(r.text[r.pos+1:], '`')
+	if end < 0 {
+		return "", r.errorf("%w: unterminated terminal", ErrInvalidNotation)
+	}
+	name := r.text[r.pos+1 : r.pos+1+end]
+	if name == "" {
+		return "", r.errorf("%w: empty terminal", ErrInvalidNotation)
+	}
+	r.pos += end + 2
+	return name, nil
+}
+
+// Deals with parsing a name along with its arguments and opt suffix,
+// names without a production in the grammar are terminals unless
+// they are given arguments.
+func (r *ebnfRule) name() (RHSRuleSymbol, *BuildError) {
+	start := r.pos
+	for r.pos < len(r.text) {
+		c, size := utf8.DecodeRuneInString(r.text[r.pos:])
+		if c != '_' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			break
+		}
+		r.pos += size
+	}
+	name := r.text[start:r.pos]
+	if name == "opt" {
+		return nil, nil
+	}
+	var args []string
+	if strings.HasPrefix(r.text[r.pos:], "[") {
+		content, err := r.bracket()
+		if err != nil {
+			return nil, err
+		}
+		args = splitList(content)
+		if checkErr := checkPrefixes(args, ErrInvalidNotation, name+" arguments", "?+~"); checkErr != nil {
+			r.pos = start
+			return nil, r.errorAt(checkErr)
+		}
+	}
+	optional := false
+	if strings.HasPrefix(r.text[r.pos:], "?") {
+		optional = true
+		r.pos++
+	} else if strings.HasPrefix(r.text[r.pos:], "opt") && args != nil {
+		optional = true
+		r.pos += len("opt")
+	} else if strings.HasSuffix(name, "opt") && r.defined[strings.TrimSuffix(name, "opt")] {
+		name = strings.TrimSuffix(name, "opt")
+		optional = true
+	}
+	if !r.defined[name] && args == nil && !optional {
+		return &TerminalRHSRuleSymbol{name: name}, nil
+	}
+	symbol := &NonTerminalRHSRuleSymbol{name: name}
+	if args != nil || optional {
+		symbol.params = &NtRHSParams{Passthrough: args}
+		if optional {
+			symbol.params.Optional = &optional
+		}
+	}
+	return symbol, nil
+}
+
+// Deals with parsing a bracketed operation such as a lookahead,
+// an exclusion or the empty string.
+func (r *ebnfRule) operation() (RHSRuleSymbol, *BuildError) {
+	start := r.pos
+	content, err := r.bracket()
+	if err != nil {
+		return nil, err
+	}
+	content = strings.TrimSpace(content)
+	if content == "empty" {
+		return &TerminalRHSRuleSymbol{name: Epsilon}, nil
+	}
+	if match := ebnfNoHerePattern.FindStringSubmatch(content); match != nil {
+		return &ExcludeRHSRuleSymbol{name: match[1]}, nil
+	}
+	match := ebnfLookaheadPattern.FindStringSubmatch(content)
+	if match == nil {
+		r.pos = start
+		return nil, r.errorf("%w: unknown operation [%v]", ErrInvalidNotation, content)
+	}
+	set := strings.TrimSpace(match[2])
+	if match[1] == "∉" || match[1] == "not in" {
+		if !strings.HasPrefix(set, "{") || !strings.HasSuffix(set, "}") {
+			r.pos = start
+			return nil, r.errorf("%w: expected a set of excluded sequences in {}", ErrInvalidNotation)
+		}
+		set = set[1 : len(set)-1]
+	}
+	params := &LaRHSParams{}
+	for _, item := range splitOutsideBackticks(set, ',') {
+		inner := &ebnfRule{text: strings.TrimSpace(item), line: r.line, indent: r.indent + start, defined: r.defined}
+		sequence := []RHSRuleSymbol{}
+		for inner.pos < len(inner.text) {
+			symbol, err := inner.symbol()
+			if err != nil {
+				return nil, err
+			}
+			if symbol != nil {
+				sequence = append(sequence, symbol)
+			}
+			inner.skipSpace()
+		}
+		if len(sequence) == 0 {
+			r.pos = start
+			return nil, r.errorf("%w: empty lookahead sequence", ErrInvalidNotation)
+		}
+		params.Exclude = append(params.Exclude, sequence)
+	}
+	return &LookaheadRHSRuleSymbol{params: params}, nil
+}
+
+// Provides the content of the bracket at the current position, brackets
+// quoted as terminals within the content do not end the bracket.
+func (r *ebnfRule) bracket() (string, *BuildError) {
+	depth := 0
+	quoted := false
+	for i := r.pos; i < len(r.text); i++ {
+		switch r.text[i] {
+		case '`':
+			quoted = !quoted
+		case '[':
+			if !quoted {
+				depth++
+			}
+		case ']':
+			if !quoted {
+				depth--
+				if depth == 0 {
+					content := r.text[r.pos+1 : i]
+					r.pos = i + 1
+					return content, nil
+				}
+			}
+		}
+	}
+	return "", r.errorf("%w: unterminated [", ErrInvalidNotation)
+}
+
+func (r *ebnfRule) skipSpace() {
+	for r.pos < len(r.text) && (r.text[r.pos] == ' ' || r.text[r.pos] == '\t') {
+		r.pos++
+	}
+}
+
+// Creates an error located at the current position of the rule.
+func (r *ebnfRule) errorf(format string, args ...interface{}) *BuildError {
+	return r.errorAt(fmt.Errorf(format, args...))
+}
+
+// Wraps the given error in a build error located at the current position of the rule.
+func (r *ebnfRule) errorAt(err error) *BuildError {
+	return &BuildError{
+		Line:   r.line,
+		Column: r.indent + utf8.RuneCountInString(r.text[:r.pos]) + 1,
+		Err:    err,
+	}
+}
+
+// Marks the given symbol as optional, returning false
+// for symbols that can not be optional.
+func setOptional(symbol RHSRuleSymbol) bool {
+	nonTerminal, isNonTerminal := symbol.(*NonTerminalRHSRuleSymbol)
+	if !isNonTerminal {
+		return false
+	}
+	optional := true
+	if nonTerminal.params == nil {
+		nonTerminal.params = &NtRHSParams{}
+	}
+	nonTerminal.params.Optional = &optional
+	return true
+}
+
+// Splits a comma separated list, an empty string provides a nil list.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Splits the text on the separator where it does not appear in backticks.
+func splitOutsideBackticks(text string, separator byte) []string {
+	items := []string{}
+	quoted := false
+	start := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] == '`' {
+			quoted = !quoted
+		} else if text[i] == separator && !quoted {
+			items = append(items, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, text[start:])
+}
+
+// Determines whether the given index of the line
+// is within a terminal quoted in backticks.
+func insideBackticks(line string, index int) bool {
+	return strings.Count(line[:index], "`")%2 == 1
+}
@@ -0,0 +1,129 @@
+package grammar
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadEBNF(t *testing.T) {
+	input := "// Statements from the specification.\n" +
+		"Statement[Yield, Return] :\n" +
+		"  BlockStatement[?Yield, ?Return]\n" +
+		"  [+Return] ReturnStatement[?Yield]\n" +
+		"  [lookahead ∉ { `{`, `async` [no LineTerminator here] `function` }] Expression[+In, ?Yield] `;`\n" +
+		"  [~Yield] `yield` IdentifierName\n" +
+		"\n" +
+		"BlockStatement[Yield, Return] : `{` StatementList[?Yield, ?Return]opt `}`\n" +
+		"\n" +
+		"ReturnStatement[Yield] :\n" +
+		"  `return` Expression[+In, ?Yield]? `;`\n" +
+		"\n" +
+		"Expression[In, Yield] :\n" +
+		"  [empty]\n" +
+		"\n" +
+		"StatementList[Yield, Return] :\n" +
+		"  Statement[?Yield, ?Return]\n"
+	expected := "<Statement>:\n" +
+		"  params: [Yield, Return]\n" +
+		"  rhs:\n" +
+		"    - [{<BlockStatement>: {params: {passthrough: ['?Yield', '?Return']}}}]\n" +
+		"    - [{<ReturnStatement>: {params: {passthrough: ['?Yield'], conditions: [+Return]}}}]\n" +
+		"    -\n" +
+		"      - <*Lookahead*>: {params: {exclude: [['{'], [async, <!LineTerminator!>, function]]}}\n" +
+		"      - <Expression>: {params: {passthrough: [+In, '?Yield']}}\n" +
+		"      - ;\n" +
+		"    - [{<*Conditional*>: {params: {conditions: [~Yield]}, parts: [yield, IdentifierName]}}]\n" +
+		"<BlockStatement>:\n" +
+		"  params: [Yield, Return]\n" +
+		"  rhs:\n" +
+		"    - ['{', {<StatementList>: {params: {passthrough: ['?Yield', '?Return'], optional: true}}}, '}']\n" +
+		"<ReturnStatement>:\n" +
+		"  params: [Yield]\n" +
+		"  rhs:\n" +
+		"    - [return, {<Expression>: {params: {passthrough: [+In, '?Yield'], optional: true}}}, ;]\n" +
+		"<Expression>:\n" +
+		"  params: [In, Yield]\n" +
+		"  rhs:\n" +
+		"    - ['[empty]']\n" +
+		"<StatementList>:\n" +
+		"  params: [Yield, Return]\n" +
+		"  rhs:\n" +
+		"    - [{<Statement>: {params: {passthrough: ['?Yield', '?Return']}}}]\n"
+	loaded, err := LoadBytes([]byte(input), "ebnf", "statement.grammar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedGrammar, err := LoadBytes([]byte(expected), "yaml", "statement.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSame(t, *loaded, *expectedGrammar)
+}
+
+func TestLoadEBNFOneOf(t *testing.T) {
+	loaded, err := LoadBytes([]byte("Sign : one of\n  `+` `-`\n"), "ebnf", "sign.grammar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := LoadBytes([]byte("<Sign>:\n  rhs: [['+'], ['-']]\n"), "yaml", "sign.yml")
+	assertSame(t, *loaded, *expected)
+}
+
+func TestLoadEBNFReportsPosition(t *testing.T) {
+	input := "A :\n  B\nB :\n  `b` [lookahead maybe `c`]\n"
+	_, err := LoadBytes([]byte(input), "ebnf", "broken.grammar")
+	var buildErr *BuildError
+	if !errors.As(err, &buildErr) || !errors.Is(err, ErrInvalidNotation) {
+		t.Fatalf("Expected an invalid notation error but got %v", err)
+	}
+	if buildErr.Production != "B" || buildErr.Line != 4 || buildErr.Column != 7 {
+		t.Errorf("Expected the error to be located at 4:7 in <B> but got %v", buildErr)
+	}
+}
+
+func TestValidateEBNF(t *testing.T) {
+	input := "A :\n  B C[+In]\nB :\n  `b`\n"
+	problems := ValidateBytes([]byte(input), "ebnf", "undefined.grammar")
+	assertProblems(t, problems, []error{ErrUndefinedNonTerminal}, []int{2})
+	if problems[0].Column != 5 {
+		t.Errorf("Expected the undefined non-terminal to be located at column 5 but got %v", problems[0].Column)
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	input := `{
+	"<A>": {
+		"params": ["In"],
+		"rhs": [
+			["a", {"<B>": {"params": {"passthrough": ["?In"], "optional": true}}}],
+			[{"<*Lookahead*>": {"params": {"exclude": [["b"]]}}}, "<B>"]
+		]
+	},
+	"<B>": {"params": ["In"], "rhs": [["b"]]}
+}`
+	expected := "<A>:\n" +
+		"  params: [In]\n" +
+		"  rhs:\n" +
+		"    - [a, {<B>: {params: {passthrough: ['?In'], optional: true}}}]\n" +
+		"    - [{<*Lookahead*>: {params: {exclude: [[b]]}}}, <B>]\n" +
+		"<B>:\n" +
+		"  params: [In]\n" +
+		"  rhs: [[b]]\n"
+	loaded, err := LoadBytes([]byte(input), "json", "a.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedGrammar, _ := LoadBytes([]byte(expected), "yaml", "a.yml")
+	assertSame(t, *loaded, *expectedGrammar)
+}
+
+func TestLoadJSONReportsPosition(t *testing.T) {
+	_, err := LoadBytes([]byte("{\n  \"<A>\": {\"rhs\": [[a]]}\n}"), "json", "broken.json")
+	var buildErr *BuildError
+	if !errors.As(err, &buildErr) {
+		t.Fatalf("Expected a build error but got %v", err)
+	}
+	if buildErr.Line != 2 || buildErr.Column != 20 {
+		t.Errorf("Expected the error to be located at 2:20 but got %v", buildErr)
+	}
+}
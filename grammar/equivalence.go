@@ -0,0 +1,200 @@
+package grammar
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultEquivalenceLength provides the default number of terminals
+	// of the longest sentence compared between two grammars.
+	DefaultEquivalenceLength = 3
+	// DefaultEquivalenceSentences provides the default number of sentences
+	// enumerated for each production of the grammars being compared.
+	DefaultEquivalenceSentences = 500
+)
+
+// EquivalenceOptions provides the bounds of the sentences
+// compared when checking two grammars accept the same language.
+type EquivalenceOptions struct {
+	// MaxLength provides the number of terminals of the longest sentence
+	// enumerated from each grammar. Defaults to DefaultEquivalenceLength.
+	MaxLength int
+	// MaxSentences provides the number of sentences enumerated for each production,
+	// which keeps the comparison of large grammars such as the ECMAScript syntactic
+	// grammar tractable at the cost of only comparing part of their languages.
+	// Defaults to DefaultEquivalenceSentences.
+	MaxSentences int
+}
+
+// LanguageDifference provides a sentence derived from a start symbol
+// which is accepted by one of two grammars but not the other.
+type LanguageDifference struct {
+	Start    string
+	Sentence TerminalSequence
+	// InOriginal is true when the sentence is only accepted by the original
+	// grammar and false when it is only accepted by the transformed grammar.
+	InOriginal bool
+	// Rejection holds where the grammar which does not accept the sentence rejected it.
+	Rejection *CheckResult
+}
+
+func (d *LanguageDifference) String() string {
+	accepted, rejected := "transformed", "original"
+	if d.InOriginal {
+		accepted, rejected = rejected, accepted
+	}
+	return fmt.Sprintf(
+		"<%v>: %q is accepted by the %v grammar but the %v grammar %v",
+		d.Start, d.Sentence.String(), accepted, rejected, d.Rejection,
+	)
+}
+
+// EquivalenceError provides the error for the case when transforming
+// a grammar for building changed the language it accepts.
+type EquivalenceError struct {
+	Differences []*LanguageDifference
+}
+
+func (e *EquivalenceError) Error() string {
+	return fmt.Sprintf(
+		"the transformed grammar does not accept the same language, %v differences were found",
+		len(e.Differences),
+	)
+}
+
+// CheckEquivalence deals with comparing the languages of an original grammar and the grammar
+// it was transformed into, such as by LLkify. The sentences of at most the maximum length derived
+// from each of the start symbols are enumerated from both grammars and each sentence accepted by one
+// grammar is checked against the other, every sentence which is only accepted by one of them is reported.
+// Neither grammar is modified as their parameters are expanded on a copy of their productions.
+func CheckEquivalence(original *Grammar, transformed *Grammar, starts []string, options *EquivalenceOptions) ([]*LanguageDifference, error) {
+	maxLength, maxSentences := DefaultEquivalenceLength, DefaultEquivalenceSentences
+	if options != nil && options.MaxLength > 0 {
+		maxLength = options.MaxLength
+	}
+	if options != nil && options.MaxSentences > 0 {
+		maxSentences = options.MaxSentences
+	}
+	differences := []*LanguageDifference{}
+	for _, start := range starts {
+		originalChecker, err := NewChecker(original, start, nil)
+		if err != nil {
+			return nil, err
+		}
+		transformedChecker, err := NewChecker(transformed, start, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, inOriginal := range []bool{true, false} {
+			from, against := transformedChecker, originalChecker
+			if inOriginal {
+				from, against = originalChecker, transformedChecker
+			}
+			for _, sentence := range from.Sentences(maxLength, maxSentences).Sequences {
+				input := TerminalTokens(strings.Join(sentence, " "))
+				// Sentences are enumerated without regard to lookahead restrictions
+				// so those the grammar does not accept itself are skipped.
+				if !from.Check(input).Accepted {
+					continue
+				}
+				if result := against.Check(input); !result.Accepted {
+					differences = append(differences, &LanguageDifference{
+						Start: start, Sentence: sentence, InOriginal: inOriginal, Rejection: result,
+					})
+				}
+			}
+		}
+	}
+	return differences, nil
+}
+
+// Sentences provides the sentences of at most the given number of terminals derived from the
+// start symbol of the checker, at most maxSentences are enumerated for each production. The sentences
+// each production derives are computed until none change, so left recursion and productions
+// which derive the empty string are handled. Lookahead restrictions are not taken into account.
+func (c *Checker) Sentences(maxLength int, maxSentences int) *SequenceSet {
+	sentences := map[string]*SequenceSet{}
+	for _, name := range c.names {
+		sentences[name] = NewSequenceSet()
+	}
+	// Only the rules which refer to a production whose sentences grew
+	// in the last pass can derive new sentences in the next one.
+	var grown map[string]bool
+	for grown == nil || len(grown) > 0 {
+		growing := map[string]bool{}
+		for _, name := range c.names {
+			for _, rule := range c.rules[name] {
+				if sentences[name].Len() >= maxSentences || (grown != nil && !refersToAny(rule, grown)) {
+					continue
+				}
+				for _, sentence := range c.ruleSentences(rule, sentences, maxLength, maxSentences).Sequences {
+					if sentences[name].Len() < maxSentences && sentences[name].Add(sentence) {
+						growing[name] = true
+					}
+				}
+			}
+		}
+		grown = growing
+	}
+	return sentences[checkStart]
+}
+
+// Determines whether the rule refers to one of the given productions.
+func refersToAny(rule []RHSRuleSymbol, productions map[string]bool) bool {
+	refers := false
+	i := 0
+	for !refers && i < len(rule) {
+		refers = isNonTerminalSymbol(rule[i]) && productions[rule[i].Name()]
+		i++
+	}
+	return refers
+}
+
+// Provides the sentences of at most the given number of terminals derived
+// from a rule, using the sentences derived from each production so far.
+func (c *Checker) ruleSentences(rule []RHSRuleSymbol, sentences map[string]*SequenceSet, maxLength int, maxSentences int) *SequenceSet {
+	result := NewSequenceSet(TerminalSequence{})
+	for _, symbol := range rule {
+		var derived *SequenceSet
+		switch s := symbol.(type) {
+		case *NonTerminalRHSRuleSymbol:
+			if set, exists := sentences[s.name]; exists {
+				derived = set
+			} else {
+				derived = NewSequenceSet(TerminalSequence{s.name})
+			}
+			if isOptional(s) {
+				optional := NewSequenceSet(TerminalSequence{})
+				optional.AddAll(derived)
+				derived = optional
+			}
+		case *TerminalRHSRuleSymbol:
+			if s.name != Epsilon {
+				derived = NewSequenceSet(TerminalSequence{s.name})
+			}
+		}
+		if derived != nil {
+			result = concatSentences(result, derived, maxLength, maxSentences)
+		}
+	}
+	return result
+}
+
+// Provides the concatenation of each sequence of the left set with each sequence of
+// the right set which is at most the given length, up to the given number of sequences.
+func concatSentences(left *SequenceSet, right *SequenceSet, maxLength int, maxSentences int) *SequenceSet {
+	result := NewSequenceSet()
+	for _, l := range left.Sequences {
+		for _, r := range right.Sequences {
+			if result.Len() >= maxSentences {
+				return result
+			}
+			if len(l)+len(r) <= maxLength {
+				seq := make(TerminalSequence, 0, len(l)+len(r))
+				result.Add(append(append(seq, l...), r...))
+			}
+		}
+	}
+	return result
+}
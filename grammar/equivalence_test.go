@@ -0,0 +1,99 @@
+package grammar
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckerSentences(t *testing.T) {
+	grammar := loadTestGrammar(t, expressionTestGrammar)
+	checker, err := NewChecker(grammar, "E", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"x", "x * x", "x - x", "( x )"}
+	if actual := checker.Sentences(3, 100).Strings(); !sameStrings(actual, expected) {
+		t.Errorf("Expected the sentences %v but got %v", expected, actual)
+	}
+	if actual := checker.Sentences(7, 5); actual.Len() != 5 {
+		t.Errorf("Expected the sentences to be limited to 5 but got %v", actual.Strings())
+	}
+}
+
+func TestCheckerSentencesWithOptionals(t *testing.T) {
+	grammar := loadTestGrammar(t, optionalsTestGrammar)
+	checker, err := NewChecker(grammar, "S", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"var x ;", "let x ;", "let * x ;", "let x * ;"}
+	if actual := checker.Sentences(4, 100).Strings(); !sameStrings(actual, expected) {
+		t.Errorf("Expected the sentences %v but got %v", expected, actual)
+	}
+}
+
+func TestCheckEquivalenceOfTransform(t *testing.T) {
+	for _, source := range []string{expressionTestGrammar, optionalsTestGrammar} {
+		original := loadTestGrammar(t, source)
+		transformed := &Grammar{Productions: original.Productions}
+		Transform(transformed)
+		differences, err := CheckEquivalence(original, transformed, []string{"S"}, &EquivalenceOptions{MaxLength: 7})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(differences) > 0 {
+			t.Errorf("Expected no differences but got %v", differences)
+		}
+	}
+}
+
+func TestCheckEquivalenceReportsDifferences(t *testing.T) {
+	original := loadTestGrammar(t, "<S>:\n  rhs:\n    - [a]\n    - [b, <S>]\n")
+	transformed := loadTestGrammar(t, "<S>:\n  rhs:\n    - [a]\n    - [c, <S>]\n")
+	differences, err := CheckEquivalence(original, transformed, []string{"S"}, &EquivalenceOptions{MaxLength: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := []string{}
+	for _, difference := range differences {
+		actual = append(actual, difference.String())
+	}
+	expected := []string{
+		`<S>: "b a" is accepted by the original grammar but the transformed grammar rejected at token 0 "b"`,
+		`<S>: "c a" is accepted by the transformed grammar but the original grammar rejected at token 0 "c"`,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected the differences %v but got %v", expected, actual)
+	}
+	_, err = CheckEquivalence(original, transformed, []string{"T"}, nil)
+	if !errors.Is(err, ErrUnknownStartSymbol) {
+		t.Errorf("Expected an unknown start symbol error but got %v", err)
+	}
+}
+
+func TestBuildBytesVerify(t *testing.T) {
+	for _, source := range []string{expressionTestGrammar, optionalsTestGrammar} {
+		artefacts, err := BuildBytes([]byte(source), &BuildOptions{Package: "parser", Verify: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(artefacts.Differences) > 0 || artefacts.Source == nil {
+			t.Errorf("Expected the verified grammar to be built but got %v", artefacts.Differences)
+		}
+	}
+}
+
+// Determines whether the two lists hold the same strings in any order.
+func sameStrings(actual []string, expected []string) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+	same := true
+	i := 0
+	for same && i < len(expected) {
+		same = contains(actual, expected[i])
+		i++
+	}
+	return same
+}
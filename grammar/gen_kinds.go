@@ -0,0 +1,53 @@
+//go:build ignore
+
+// Generates kinds.go, which holds the constants of the kinds of token of the parser
+// package that are an instance of each terminal of the syntactic grammar, so the
+// generated parse tables can map token kinds to terminals without the grammar
+// package depending on the parser package.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+
+	"github.com/freshwebio/esengine/parser"
+)
+
+func main() {
+	terminals := []string{}
+	constants := map[string][]string{}
+	for kind := parser.TokenKind(1); kind.String() != ""; kind++ {
+		terminal := kind.Terminal()
+		if _, exists := constants[terminal]; !exists {
+			terminals = append(terminals, terminal)
+		}
+		constants[terminal] = append(constants[terminal], kind.GoString())
+	}
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// Code generated by gen_kinds.go from the token kinds of the parser package. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&source, "package grammar\n\n")
+	fmt.Fprintf(&source, "// Holds the constants of the kinds of token produced by the lexer of the\n")
+	fmt.Fprintf(&source, "// parser package which are an instance of each terminal of the syntactic grammar.\n")
+	fmt.Fprintf(&source, "var terminalKinds = map[string][]string{\n")
+	for _, terminal := range terminals {
+		fmt.Fprintf(&source, "\t%q: {", terminal)
+		for i, constant := range constants[terminal] {
+			if i > 0 {
+				fmt.Fprintf(&source, ", ")
+			}
+			fmt.Fprintf(&source, "%q", constant)
+		}
+		fmt.Fprintf(&source, "},\n")
+	}
+	fmt.Fprintf(&source, "}\n")
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("kinds.go", formatted, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,7 +1,7 @@
 package grammar
 
 import (
-	"regexp"
+	"fmt"
 	"strings"
 
 	yaml "gopkg.in/yaml.v2"
@@ -17,39 +17,74 @@ type Grammar struct {
 // into the data structures used in this application in order to
 // generate our parser components.
 func (g *Grammar) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	target := &yaml.MapSlice{}
-	err := unmarshal(target)
+	target := yaml.MapSlice{}
+	err := unmarshal(&target)
 	if err != nil {
 		return err
 	}
-	for _, p := range *target {
-		matched, err := regexp.MatchString("^<(\\w|')+>$", p.Key.(string))
+	decoded, errs := decodeGrammar(target)
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	g.Productions = decoded.Productions
+	return nil
+}
+
+// Deals with decoding every production of a grammar from the generic
+// YAML representation, every malformed production is reported
+// rather than stopping at the first.
+func decodeGrammar(target yaml.MapSlice) (*Grammar, []*BuildError) {
+	g := &Grammar{}
+	errs := []*BuildError{}
+	for _, p := range target {
+		prod, err := decodeProduction(p)
 		if err != nil {
-			return err
-		}
-		if matched {
-			pMap := p.Value.(yaml.MapSlice)
-			params := get(pMap, "params")
-			rhs := get(pMap, "rhs")
-			prod := &Production{}
-			prod.Name = strings.TrimSuffix(strings.TrimPrefix(p.Key.(string), "<"), ">")
-			if params != nil {
-				paramsIface := params.([]interface{})
-				for _, param := range paramsIface {
-					prod.Params = append(prod.Params, param.(string))
-				}
-			}
-			if rhs != nil {
-				err = prod.extractRHSRuleSymbols(rhs)
-				if err != nil {
-					return err
-				}
-			}
+			errs = append(errs, err)
+		} else {
 			g.Productions = append(g.Productions, prod)
-			//fmt.Printf("%+s\n\n", prod)
 		}
 	}
-	return nil
+	return g, errs
+}
+
+// Deals with decoding a single production from its key and value in the grammar.
+func decodeProduction(p yaml.MapItem) (*Production, *BuildError) {
+	key, keyIsStr := p.Key.(string)
+	if !keyIsStr || !nonTerminalPattern.MatchString(key) {
+		return nil, &BuildError{
+			Err: fmt.Errorf("%w: the key %v is not a non-terminal of the form <Name>", ErrMalformedProduction, p.Key),
+		}
+	}
+	prod := &Production{}
+	prod.Name = strings.TrimSuffix(strings.TrimPrefix(key, "<"), ">")
+	pMap, isMap := p.Value.(yaml.MapSlice)
+	if !isMap {
+		return nil, &BuildError{
+			Production: prod.Name,
+			Err:        fmt.Errorf("%w: expected a map of params and rhs", ErrMalformedProduction),
+		}
+	}
+	err := checkKeys(pMap, ErrMalformedProduction, "the production", "params", "refines", "rhs", "ast")
+	if err == nil {
+		prod.Params, err = extractStringList(get(pMap, "params"), ErrMalformedProduction, "params")
+	}
+	if err == nil {
+		prod.Refines, err = extractNonTerminalList(get(pMap, "refines"), ErrMalformedProduction, "refines")
+	}
+	if err == nil {
+		if rhs := get(pMap, "rhs"); rhs != nil {
+			err = prod.extractRHSRuleSymbols(rhs)
+		}
+	}
+	if err == nil {
+		if ast := get(pMap, "ast"); ast != nil {
+			err = prod.extractASTMappings(ast)
+		}
+	}
+	if err != nil {
+		return nil, &BuildError{Production: prod.Name, Err: err}
+	}
+	return prod, nil
 }
 
 // Retrieves the item with the provided key in
@@ -8,6 +8,21 @@ import (
 	yaml "gopkg.in/yaml.v2"
 )
 
+// Holds a left recursive grammar of expressions shared by the tests
+// of checking sentences and the equivalence of transformed grammars.
+const expressionTestGrammar = "<S>:\n  rhs:\n    - [<E>, ;]\n" +
+	"<E>:\n  rhs:\n    - [<T>]\n    - [<E>, '-', <T>]\n" +
+	"<T>:\n  rhs:\n    - [<F>]\n    - [<T>, '*', <F>]\n" +
+	"<F>:\n  rhs:\n    - [x]\n    - ['(', <E>, ')']\n"
+
+// A grammar with an optional symbol which ends a rule
+// and a rule with two optional symbols.
+const optionalsTestGrammar = "<S>:\n  rhs:\n    - [var, <B>, ;]\n" +
+	"    - [let, {<M>: {params: {optional: true}}}, <B>, {<M>: {params: {optional: true}}}, ;]\n" +
+	"<B>:\n  rhs:\n    - [x, {<I>: {params: {optional: true}}}]\n" +
+	"<I>:\n  rhs:\n    - ['=', x]\n" +
+	"<M>:\n  rhs:\n    - ['*']\n"
+
 // Loads a grammar fixture by name,
 // returns input grammar followed by expected result grammar
 func loadGrammarFixture(name string) (*Grammar, *Grammar) {
@@ -17,9 +32,29 @@ func loadGrammarFixture(name string) (*Grammar, *Grammar) {
 	}
 	grammars := []*Grammar{}
 	err = yaml.Unmarshal(inputBytes, &grammars)
+	if err != nil {
+		panic(err)
+	}
 	return grammars[0], grammars[1]
 }
 
+// Loads the input grammar of a grammar fixture by name,
+// failing the test when the fixture can not be read or decoded.
+func loadFixtureInput(t *testing.T, name string) *Grammar {
+	inputBytes, err := ioutil.ReadFile("test/fixtures/" + name + ".yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grammars := []*Grammar{}
+	if err = yaml.Unmarshal(inputBytes, &grammars); err != nil {
+		t.Fatal(err)
+	}
+	if len(grammars) == 0 || grammars[0] == nil || len(grammars[0].Productions) == 0 {
+		t.Fatalf("Expected the fixture %v to hold an input grammar", name)
+	}
+	return grammars[0]
+}
+
 // Determines if the two given grammars are equivalent.
 func isSameGrammar(g1 Grammar, g2 Grammar) bool {
 	if len(g1.Productions) != len(g2.Productions) {
@@ -218,3 +253,34 @@ func assertSame(t *testing.T, input Grammar, expected Grammar) {
 		t.Errorf("Expected grammar \n%v but got grammar \n%v", sprintGrammar(expected), sprintGrammar(input))
 	}
 }
+
+// Asserts that each of the accepted inputs, written as the names of their terminals,
+// is a sentence of the grammar derived from the start symbol and that each of the rejected inputs is not.
+func assertLanguage(t *testing.T, grammar *Grammar, start string, options *CheckOptions, accepted []string, rejected []string) {
+	checker, err := NewChecker(grammar, start, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range accepted {
+		if result := checker.Check(TerminalTokens(input)); !result.Accepted {
+			t.Errorf("Expected %q to be accepted by <%v> but it was %v", input, start, result)
+		}
+	}
+	for _, input := range rejected {
+		if result := checker.Check(TerminalTokens(input)); result.Accepted {
+			t.Errorf("Expected %q to be rejected by <%v> but it was accepted", input, start)
+		}
+	}
+}
+
+// Asserts that the two grammars accept the same sentences of at most
+// maxLength terminals derived from the start symbol.
+func assertSameLanguage(t *testing.T, g1 *Grammar, g2 *Grammar, start string, maxLength int) {
+	differences, err := CheckEquivalence(g1, g2, []string{start}, &EquivalenceOptions{MaxLength: maxLength})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, difference := range differences {
+		t.Errorf("Expected both grammars to accept the same sentences but %v", difference)
+	}
+}
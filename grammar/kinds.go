@@ -0,0 +1,106 @@
+// Code generated by gen_kinds.go from the token kinds of the parser package. DO NOT EDIT.
+
+package grammar
+
+// Holds the constants of the kinds of token produced by the lexer of the
+// parser package which are an instance of each terminal of the syntactic grammar.
+var terminalKinds = map[string][]string{
+	"LineTerminator":           {"LineTerminatorToken"},
+	"IdentifierName":           {"IdentifierNameToken"},
+	"NullLiteral":              {"NullLiteralToken"},
+	"BooleanLiteral":           {"BooleanLiteralToken"},
+	"NumericLiteral":           {"DecimalLiteralToken", "BinaryIntegerLiteralToken", "OctalIntegerLiteralToken", "HexIntegerLiteralToken"},
+	"StringLiteral":            {"StringLiteralToken"},
+	"RegularExpressionLiteral": {"RegularExpressionLiteralToken"},
+	"NoSubstitutionTemplate":   {"NoSubstitutionTemplateToken"},
+	"TemplateHead":             {"TemplateHeadToken"},
+	"TemplateMiddle":           {"TemplateMiddleToken"},
+	"TemplateTail":             {"TemplateTailToken"},
+	"{":                        {"LeftBraceToken"},
+	"(":                        {"LeftParenToken"},
+	")":                        {"RightParenToken"},
+	"[":                        {"LeftBracketToken"},
+	"]":                        {"RightBracketToken"},
+	".":                        {"DotToken"},
+	"...":                      {"EllipsisToken"},
+	";":                        {"SemicolonToken"},
+	",":                        {"CommaToken"},
+	"<":                        {"LessThanToken"},
+	">":                        {"GreaterThanToken"},
+	"<=":                       {"LessThanEqualsToken"},
+	">=":                       {"GreaterThanEqualsToken"},
+	"==":                       {"EqualsEqualsToken"},
+	"!=":                       {"ExclamationEqualsToken"},
+	"===":                      {"EqualsEqualsEqualsToken"},
+	"!==":                      {"ExclamationEqualsEqualsToken"},
+	"+":                        {"PlusToken"},
+	"-":                        {"MinusToken"},
+	"*":                        {"AsteriskToken"},
+	"%":                        {"PercentToken"},
+	"**":                       {"AsteriskAsteriskToken"},
+	"++":                       {"PlusPlusToken"},
+	"--":                       {"MinusMinusToken"},
+	"<<":                       {"LessThanLessThanToken"},
+	">>":                       {"GreaterThanGreaterThanToken"},
+	">>>":                      {"GreaterThanGreaterThanGreaterThanToken"},
+	"&":                        {"AmpersandToken"},
+	"|":                        {"BarToken"},
+	"^":                        {"CaretToken"},
+	"!":                        {"ExclamationToken"},
+	"~":                        {"TildeToken"},
+	"&&":                       {"AmpersandAmpersandToken"},
+	"||":                       {"BarBarToken"},
+	"?":                        {"QuestionToken"},
+	":":                        {"ColonToken"},
+	"=":                        {"EqualsToken"},
+	"+=":                       {"PlusEqualsToken"},
+	"-=":                       {"MinusEqualsToken"},
+	"*=":                       {"AsteriskEqualsToken"},
+	"%=":                       {"PercentEqualsToken"},
+	"**=":                      {"AsteriskAsteriskEqualsToken"},
+	"<<=":                      {"LessThanLessThanEqualsToken"},
+	">>=":                      {"GreaterThanGreaterThanEqualsToken"},
+	">>>=":                     {"GreaterThanGreaterThanGreaterThanEqualsToken"},
+	"&=":                       {"AmpersandEqualsToken"},
+	"|=":                       {"BarEqualsToken"},
+	"^=":                       {"CaretEqualsToken"},
+	"=>":                       {"EqualsGreaterThanToken"},
+	"}":                        {"RightBraceToken"},
+	"/":                        {"SlashToken"},
+	"/=":                       {"SlashEqualsToken"},
+	"await":                    {"AwaitToken"},
+	"break":                    {"BreakToken"},
+	"case":                     {"CaseToken"},
+	"catch":                    {"CatchToken"},
+	"class":                    {"ClassToken"},
+	"const":                    {"ConstToken"},
+	"continue":                 {"ContinueToken"},
+	"debugger":                 {"DebuggerToken"},
+	"default":                  {"DefaultToken"},
+	"delete":                   {"DeleteToken"},
+	"do":                       {"DoToken"},
+	"else":                     {"ElseToken"},
+	"export":                   {"ExportToken"},
+	"extends":                  {"ExtendsToken"},
+	"finally":                  {"FinallyToken"},
+	"for":                      {"ForToken"},
+	"function":                 {"FunctionToken"},
+	"if":                       {"IfToken"},
+	"import":                   {"ImportToken"},
+	"in":                       {"InToken"},
+	"instanceof":               {"InstanceofToken"},
+	"new":                      {"NewToken"},
+	"return":                   {"ReturnToken"},
+	"super":                    {"SuperToken"},
+	"switch":                   {"SwitchToken"},
+	"this":                     {"ThisToken"},
+	"throw":                    {"ThrowToken"},
+	"try":                      {"TryToken"},
+	"typeof":                   {"TypeofToken"},
+	"var":                      {"VarToken"},
+	"void":                     {"VoidToken"},
+	"while":                    {"WhileToken"},
+	"with":                     {"WithToken"},
+	"yield":                    {"YieldToken"},
+	"enum":                     {"EnumToken"},
+}
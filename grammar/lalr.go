@@ -0,0 +1,643 @@
+package grammar
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LRActionKind provides a type alias to distinguish
+// between the actions of an LR parse table.
+type LRActionKind int
+
+const (
+	_ LRActionKind = iota
+	// ShiftAction consumes the lookahead terminal and moves to the target state.
+	ShiftAction
+	// ReduceAction replaces the symbols of the target rule on the stack
+	// with the production of the rule.
+	ReduceAction
+	// AcceptAction completes the parse of a goal symbol.
+	AcceptAction
+)
+
+// String provides the name of the action kind.
+func (k LRActionKind) String() string {
+	switch k {
+	case ShiftAction:
+		return "shift"
+	case ReduceAction:
+		return "reduce"
+	case AcceptAction:
+		return "accept"
+	default:
+		return ""
+	}
+}
+
+// LRAction provides an entry in the action table, the target is
+// the state to shift to or the index of the rule to reduce by.
+type LRAction struct {
+	Kind   LRActionKind
+	Target int
+}
+
+// LRItem provides a rule of the grammar with a position in its symbols,
+// the symbols before the position have been recognised.
+type LRItem struct {
+	Rule int
+	Dot  int
+}
+
+// LRState provides a state of the LR(0) automaton the LALR(1) table is built from.
+type LRState struct {
+	// Kernel holds the items which distinguish the state from other states.
+	Kernel []LRItem
+	// Lookaheads holds the LALR(1) lookahead terminals of each kernel item.
+	Lookaheads [][]string
+}
+
+// LRTable provides the symbols, rules and LALR(1) action
+// and goto tables generated from a grammar.
+type LRTable struct {
+	// NonTerminals holds the names of the non-terminal symbols
+	// in the order their productions appear in the grammar.
+	NonTerminals []string
+	// Terminals holds the names of the terminal symbols in the order
+	// they are first referenced, the end of input is always the last terminal.
+	Terminals []string
+	// Starts holds the names of the start symbols of the grammar.
+	Starts []string
+	// StartStates maps each start symbol to the state parsing it begins in.
+	StartStates map[string]int
+	// Rules holds every right-hand side rule of the grammar once optional symbols
+	// have been expanded, reductions refer to rules by their index.
+	Rules  []*TableRule
+	States []*LRState
+	// Action maps a state and a lookahead terminal to the action to take.
+	Action []map[string]LRAction
+	// Goto maps a state and the non-terminal that has just been
+	// reduced to the state to move to.
+	Goto []map[string]int
+}
+
+// ItemString provides the human-readable form of an item, e.g. `E → E + · T`.
+func (t *LRTable) ItemString(item LRItem) string {
+	var rule *TableRule
+	if item.Rule < len(t.Rules) {
+		rule = t.Rules[item.Rule]
+	} else {
+		// The item belongs to the rule which accepts a start symbol.
+		goal := t.Starts[item.Rule-len(t.Rules)]
+		rule = &TableRule{Production: goalName(goal), Symbols: []string{goal}}
+	}
+	symbols := append([]string{}, rule.Symbols[:item.Dot]...)
+	symbols = append(symbols, "·")
+	symbols = append(symbols, rule.Symbols[item.Dot:]...)
+	return rule.Production + " → " + strings.Join(symbols, " ")
+}
+
+// Provides the name of the production which accepts the given start symbol.
+func goalName(start string) string {
+	return "[goal " + start + "]"
+}
+
+// LRConflictKind provides a type alias to distinguish
+// between the kinds of LR conflicts.
+type LRConflictKind int
+
+const (
+	_ LRConflictKind = iota
+	// ShiftReduceConflict is where a state can both shift the
+	// lookahead terminal and reduce by a rule.
+	ShiftReduceConflict
+	// ReduceReduceConflict is where a state can reduce
+	// by more than one rule for the lookahead terminal.
+	ReduceReduceConflict
+)
+
+// String provides the name of the conflict kind.
+func (k LRConflictKind) String() string {
+	switch k {
+	case ShiftReduceConflict:
+		return "shift/reduce"
+	case ReduceReduceConflict:
+		return "reduce/reduce"
+	default:
+		return ""
+	}
+}
+
+// LRConflict provides the details of a state of the LALR(1) automaton
+// where more than one action applies for the same lookahead terminal.
+// Shifts take precedence over reductions and reductions by rules which
+// appear first take precedence over later rules.
+type LRConflict struct {
+	Kind     LRConflictKind
+	State    int
+	Terminal string
+	// Actions holds the action that was chosen followed by the action that was discarded.
+	Actions [2]LRAction
+	// Items holds the items of the state which lead to the conflicting actions.
+	Items []string
+	// Provenance holds where the rules reduced by the conflicting
+	// actions came from in the source grammar.
+	Provenance []*Provenance
+}
+
+// String provides a human-readable report of the conflict.
+func (c *LRConflict) String() string {
+	report := c.Kind.String() + " conflict in state " + strconv.Itoa(c.State) + " on " + c.Terminal + ":\n"
+	for _, action := range c.Actions {
+		report += "    " + action.Kind.String() + " " + strconv.Itoa(action.Target) + "\n"
+	}
+	for _, item := range c.Items {
+		report += "    item: " + item + "\n"
+	}
+	for _, provenance := range c.Provenance {
+		report += "    from: " + provenance.String() + "\n"
+	}
+	return report
+}
+
+// BuildLALRTable deals with building the LALR(1) action and goto tables for
+// the given grammar, which should have its parameters expanded beforehand.
+// Optional symbols are expanded into every combination, conditionals are
+// flattened into their parts and lookahead restrictions and exclusions are not
+// taken into account so the conflicts they would resolve are reported.
+// When no start symbols are provided the default start symbols of the grammar are used.
+func BuildLALRTable(grammar *Grammar, starts ...string) (*LRTable, []*LRConflict) {
+	if len(starts) == 0 {
+		starts = StartSymbols(grammar)
+	}
+	builder := newLRBuilder(grammar, starts)
+	builder.buildAutomaton()
+	builder.computeLookaheads()
+	return builder.buildTables()
+}
+
+// Holds the state of building an LALR(1) table, terminals are numbered first
+// followed by non-terminals and then the goal symbols of each start symbol.
+type lrBuilder struct {
+	table       *LRTable
+	symbols     []string
+	symbolIndex map[string]int
+	terminals   int
+	rules       []lrRule
+	rulesOf     map[int][]int
+	itemBase    []int
+	nullable    []bool
+	first       []lrSet
+	states      [][]int
+	stateIndex  map[string]int
+	transitions []map[int]int
+	lookaheads  [][]lrSet
+}
+
+type lrRule struct {
+	production int
+	symbols    []int
+}
+
+func newLRBuilder(grammar *Grammar, starts []string) *lrBuilder {
+	b := &lrBuilder{
+		table: &LRTable{
+			Starts:      starts,
+			StartStates: map[string]int{},
+		},
+		symbolIndex: map[string]int{},
+		rulesOf:     map[int][]int{},
+	}
+	nonTerminals := map[string]bool{}
+	for _, prod := range grammar.Productions {
+		if !nonTerminals[prod.Name] {
+			nonTerminals[prod.Name] = true
+			b.table.NonTerminals = append(b.table.NonTerminals, prod.Name)
+		}
+	}
+	rules := [][]string{}
+	for _, prod := range grammar.Productions {
+		for i, rule := range prod.RHS {
+			for _, expanded := range expandOptionalSymbols(flattenRule(rule)) {
+				symbols := []string{}
+				for _, symbol := range expanded {
+					switch symbol.(type) {
+					case *NonTerminalRHSRuleSymbol, *TerminalRHSRuleSymbol:
+						if symbol.Name() != Epsilon {
+							symbols = append(symbols, symbol.Name())
+							if !nonTerminals[symbol.Name()] && !contains(b.table.Terminals, symbol.Name()) {
+								b.table.Terminals = append(b.table.Terminals, symbol.Name())
+							}
+						}
+					}
+				}
+				b.table.Rules = append(b.table.Rules, &TableRule{
+					Production:  prod.Name,
+					Alternative: i,
+					Symbols:     symbols,
+					Provenance:  prod.ProvenanceOf(i),
+				})
+				rules = append(rules, symbols)
+			}
+		}
+	}
+	b.table.Terminals = append(b.table.Terminals, EndOfInput)
+	for _, name := range b.table.Terminals {
+		b.addSymbol(name)
+	}
+	b.terminals = len(b.table.Terminals)
+	for _, name := range b.table.NonTerminals {
+		b.addSymbol(name)
+	}
+	for i, symbols := range rules {
+		b.addRule(b.symbolIndex[b.table.Rules[i].Production], symbols)
+	}
+	for _, start := range starts {
+		b.addRule(b.addSymbol(goalName(start)), []string{start})
+	}
+	return b
+}
+
+// Provides every combination of the rule with and without each of its optional symbols.
+func expandOptionalSymbols(rule []RHSRuleSymbol) [][]RHSRuleSymbol {
+	combinations := [][]RHSRuleSymbol{{}}
+	for _, symbol := range rule {
+		next := [][]RHSRuleSymbol{}
+		params, isNtParams := symbol.Params().(*NtRHSParams)
+		optional := isNtParams && params != nil && params.Optional != nil && *params.Optional
+		for _, combination := range combinations {
+			next = append(next, append(append([]RHSRuleSymbol{}, combination...), symbol))
+			if optional {
+				next = append(next, combination)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+func (b *lrBuilder) addSymbol(name string) int {
+	b.symbolIndex[name] = len(b.symbols)
+	b.symbols = append(b.symbols, name)
+	return len(b.symbols) - 1
+}
+
+func (b *lrBuilder) addRule(production int, names []string) {
+	rule := lrRule{production: production}
+	for _, name := range names {
+		rule.symbols = append(rule.symbols, b.symbolIndex[name])
+	}
+	b.rulesOf[production] = append(b.rulesOf[production], len(b.rules))
+	b.itemBase = append(b.itemBase, 0)
+	if len(b.rules) > 0 {
+		last := len(b.rules) - 1
+		b.itemBase[len(b.rules)] = b.itemBase[last] + len(b.rules[last].symbols) + 1
+	}
+	b.rules = append(b.rules, rule)
+}
+
+// Items are numbered so that the items of a rule are consecutive.
+func (b *lrBuilder) item(rule int, dot int) int {
+	return b.itemBase[rule] + dot
+}
+
+func (b *lrBuilder) itemOf(item int) LRItem {
+	rule := sort.Search(len(b.itemBase), func(i int) bool { return b.itemBase[i] > item }) - 1
+	return LRItem{Rule: rule, Dot: item - b.itemBase[rule]}
+}
+
+// Provides the symbol after the position of the item or -1 when the item is complete.
+func (b *lrBuilder) next(item int) int {
+	lrItem := b.itemOf(item)
+	symbols := b.rules[lrItem.Rule].symbols
+	if lrItem.Dot < len(symbols) {
+		return symbols[lrItem.Dot]
+	}
+	return -1
+}
+
+// Deals with building the LR(0) automaton from the start state of each goal symbol.
+func (b *lrBuilder) buildAutomaton() {
+	b.stateIndex = map[string]int{}
+	for i, start := range b.table.Starts {
+		goalRule := len(b.table.Rules) + i
+		b.table.StartStates[start] = b.addState([]int{b.item(goalRule, 0)})
+	}
+	for state := 0; state < len(b.states); state++ {
+		kernels := map[int][]int{}
+		order := []int{}
+		for _, item := range b.closure0(b.states[state]) {
+			if symbol := b.next(item); symbol >= 0 {
+				if _, exists := kernels[symbol]; !exists {
+					order = append(order, symbol)
+				}
+				kernels[symbol] = append(kernels[symbol], item+1)
+			}
+		}
+		for _, symbol := range order {
+			b.transitions[state][symbol] = b.addState(kernels[symbol])
+		}
+	}
+}
+
+// Provides the state with the given kernel items, creating it when it does not exist.
+func (b *lrBuilder) addState(kernel []int) int {
+	sort.Ints(kernel)
+	keyParts := []string{}
+	for _, item := range kernel {
+		keyParts = append(keyParts, strconv.Itoa(item))
+	}
+	key := strings.Join(keyParts, ",")
+	if state, exists := b.stateIndex[key]; exists {
+		return state
+	}
+	b.stateIndex[key] = len(b.states)
+	b.states = append(b.states, kernel)
+	b.transitions = append(b.transitions, map[int]int{})
+	return len(b.states) - 1
+}
+
+// Provides the LR(0) closure of the kernel items.
+func (b *lrBuilder) closure0(kernel []int) []int {
+	items := append([]int{}, kernel...)
+	expanded := map[int]bool{}
+	for i := 0; i < len(items); i++ {
+		if symbol := b.next(items[i]); symbol >= b.terminals && !expanded[symbol] {
+			expanded[symbol] = true
+			for _, rule := range b.rulesOf[symbol] {
+				items = append(items, b.item(rule, 0))
+			}
+		}
+	}
+	return items
+}
+
+// Provides the LR(1) closure of the given items where each item has a set
+// of lookahead terminals, the order in which items were added is also provided.
+func (b *lrBuilder) closure1(items map[int]lrSet) (map[int]lrSet, []int) {
+	order := []int{}
+	for item := range items {
+		order = append(order, item)
+	}
+	sort.Ints(order)
+	queue := append([]int{}, order...)
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+		symbol := b.next(item)
+		if symbol < b.terminals {
+			continue
+		}
+		lrItem := b.itemOf(item)
+		lookahead := b.firstOf(b.rules[lrItem.Rule].symbols[lrItem.Dot+1:], items[item])
+		for _, rule := range b.rulesOf[symbol] {
+			start := b.item(rule, 0)
+			set, exists := items[start]
+			if !exists {
+				set = newLRSet(b.terminals + 1)
+				items[start] = set
+				order = append(order, start)
+			}
+			if set.addAll(lookahead) {
+				queue = append(queue, start)
+			}
+		}
+	}
+	return items, order
+}
+
+// Deals with computing which non-terminals are nullable and their FIRST_1 sets.
+func (b *lrBuilder) computeFirst() {
+	b.nullable = make([]bool, len(b.symbols))
+	b.first = make([]lrSet, len(b.symbols))
+	for symbol := range b.symbols {
+		b.first[symbol] = newLRSet(b.terminals + 1)
+		if symbol < b.terminals {
+			b.first[symbol].add(symbol)
+		}
+	}
+	changed := true
+	for changed {
+		changed = false
+		for _, rule := range b.rules {
+			nullable := true
+			i := 0
+			for nullable && i < len(rule.symbols) {
+				if b.first[rule.production].addAll(b.first[rule.symbols[i]]) {
+					changed = true
+				}
+				nullable = b.nullable[rule.symbols[i]]
+				i++
+			}
+			if nullable && !b.nullable[rule.production] {
+				b.nullable[rule.production] = true
+				changed = true
+			}
+		}
+	}
+}
+
+// Provides FIRST_1 of the symbols followed by the given lookahead set.
+func (b *lrBuilder) firstOf(symbols []int, lookahead lrSet) lrSet {
+	result := newLRSet(b.terminals + 1)
+	for _, symbol := range symbols {
+		result.addAll(b.first[symbol])
+		if !b.nullable[symbol] {
+			return result
+		}
+	}
+	result.addAll(lookahead)
+	return result
+}
+
+// Deals with computing the LALR(1) lookaheads of every kernel item by determining
+// the lookaheads generated spontaneously and those propagated between kernel items.
+func (b *lrBuilder) computeLookaheads() {
+	b.computeFirst()
+	// An extra terminal is used as the placeholder that
+	// marks lookaheads which are propagated.
+	propagate := b.terminals
+	b.lookaheads = make([][]lrSet, len(b.states))
+	for state, kernel := range b.states {
+		b.lookaheads[state] = make([]lrSet, len(kernel))
+		for i := range kernel {
+			b.lookaheads[state][i] = newLRSet(b.terminals + 1)
+		}
+	}
+	for _, state := range b.table.StartStates {
+		b.lookaheads[state][0].add(b.terminals - 1)
+	}
+	type kernelItem struct{ state, index int }
+	edges := map[kernelItem][]kernelItem{}
+	for state, kernel := range b.states {
+		for i, kernelItemIndex := range kernel {
+			placeholder := newLRSet(b.terminals + 1)
+			placeholder.add(propagate)
+			items, order := b.closure1(map[int]lrSet{kernelItemIndex: placeholder})
+			for _, item := range order {
+				symbol := b.next(item)
+				if symbol < 0 {
+					continue
+				}
+				target := b.transitions[state][symbol]
+				index := sort.SearchInts(b.states[target], item+1)
+				for _, terminal := range items[item].members() {
+					if terminal == propagate {
+						from := kernelItem{state, i}
+						edges[from] = append(edges[from], kernelItem{target, index})
+					} else {
+						b.lookaheads[target][index].add(terminal)
+					}
+				}
+			}
+		}
+	}
+	changed := true
+	for changed {
+		changed = false
+		for from, targets := range edges {
+			for _, to := range targets {
+				if b.lookaheads[to.state][to.index].addAll(b.lookaheads[from.state][from.index]) {
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// Deals with filling in the action and goto tables from the automaton
+// and the lookaheads of each state, reporting every conflict.
+func (b *lrBuilder) buildTables() (*LRTable, []*LRConflict) {
+	conflicts := []*LRConflict{}
+	goalRules := len(b.table.Rules)
+	for state, kernel := range b.states {
+		lrState := &LRState{}
+		kernelLookaheads := map[int]lrSet{}
+		for i, item := range kernel {
+			lrState.Kernel = append(lrState.Kernel, b.itemOf(item))
+			lrState.Lookaheads = append(lrState.Lookaheads, b.terminalNames(b.lookaheads[state][i]))
+			kernelLookaheads[item] = b.lookaheads[state][i].copy()
+		}
+		b.table.States = append(b.table.States, lrState)
+		actions := map[string]LRAction{}
+		gotos := map[string]int{}
+		for symbol, target := range b.transitions[state] {
+			if symbol < b.terminals {
+				actions[b.symbols[symbol]] = LRAction{Kind: ShiftAction, Target: target}
+			} else {
+				gotos[b.symbols[symbol]] = target
+			}
+		}
+		items, order := b.closure1(kernelLookaheads)
+		for _, item := range order {
+			if b.next(item) >= 0 {
+				continue
+			}
+			lrItem := b.itemOf(item)
+			action := LRAction{Kind: ReduceAction, Target: lrItem.Rule}
+			if lrItem.Rule >= goalRules {
+				action = LRAction{Kind: AcceptAction}
+			}
+			for _, terminal := range items[item].members() {
+				name := b.symbols[terminal]
+				existing, exists := actions[name]
+				if !exists {
+					actions[name] = action
+				} else if existing != action {
+					conflict := &LRConflict{
+						Kind:     ShiftReduceConflict,
+						State:    state,
+						Terminal: name,
+						Actions:  [2]LRAction{existing, action},
+					}
+					if existing.Kind != ShiftAction {
+						conflict.Kind = ReduceReduceConflict
+						if action.Target < existing.Target {
+							conflict.Actions = [2]LRAction{action, existing}
+							actions[name] = action
+						}
+					}
+					conflict.Items = b.conflictItems(items, order, terminal)
+					for _, conflicting := range conflict.Actions {
+						if conflicting.Kind == ReduceAction && b.table.Rules[conflicting.Target].Provenance != nil {
+							conflict.Provenance = append(conflict.Provenance, b.table.Rules[conflicting.Target].Provenance)
+						}
+					}
+					conflicts = append(conflicts, conflict)
+				}
+			}
+		}
+		b.table.Action = append(b.table.Action, actions)
+		b.table.Goto = append(b.table.Goto, gotos)
+	}
+	return b.table, conflicts
+}
+
+// Provides the items of a state which shift the terminal or
+// reduce when the terminal is the lookahead.
+func (b *lrBuilder) conflictItems(items map[int]lrSet, order []int, terminal int) []string {
+	descriptions := []string{}
+	for _, item := range order {
+		next := b.next(item)
+		if next == terminal || (next < 0 && items[item].contains(terminal)) {
+			descriptions = append(descriptions, b.table.ItemString(b.itemOf(item)))
+		}
+	}
+	return descriptions
+}
+
+func (b *lrBuilder) terminalNames(set lrSet) []string {
+	names := []string{}
+	for _, terminal := range set.members() {
+		names = append(names, b.symbols[terminal])
+	}
+	return names
+}
+
+// Provides a set of small integers such as terminal symbol numbers.
+type lrSet []uint64
+
+func newLRSet(size int) lrSet {
+	return make(lrSet, (size+63)/64)
+}
+
+func (s lrSet) add(member int) bool {
+	word, bit := member/64, uint64(1)<<uint(member%64)
+	if s[word]&bit != 0 {
+		return false
+	}
+	s[word] |= bit
+	return true
+}
+
+func (s lrSet) addAll(other lrSet) bool {
+	changed := false
+	for i, word := range other {
+		if s[i]|word != s[i] {
+			s[i] |= word
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (s lrSet) contains(member int) bool {
+	return s[member/64]&(uint64(1)<<uint(member%64)) != 0
+}
+
+func (s lrSet) copy() lrSet {
+	return append(lrSet{}, s...)
+}
+
+func (s lrSet) members() []int {
+	members := []int{}
+	for i, word := range s {
+		for bit := 0; word != 0; bit++ {
+			if word&1 != 0 {
+				members = append(members, i*64+bit)
+			}
+			word >>= 1
+		}
+	}
+	return members
+}
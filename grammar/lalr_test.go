@@ -0,0 +1,186 @@
+package grammar
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Determines whether the LR table accepts the sequence of
+// terminals as the given start symbol.
+func lrAccepts(table *LRTable, start string, input ...string) bool {
+	stack := []int{table.StartStates[start]}
+	input = append(input, EndOfInput)
+	position := 0
+	for {
+		action, exists := table.Action[stack[len(stack)-1]][input[position]]
+		if !exists {
+			return false
+		}
+		switch action.Kind {
+		case ShiftAction:
+			stack = append(stack, action.Target)
+			position++
+		case ReduceAction:
+			rule := table.Rules[action.Target]
+			stack = stack[:len(stack)-len(rule.Symbols)]
+			stack = append(stack, table.Goto[stack[len(stack)-1]][rule.Production])
+		case AcceptAction:
+			return true
+		}
+	}
+}
+
+func loadTestGrammar(t *testing.T, input string) *Grammar {
+	grammar, err := LoadBytes([]byte(input), "yaml", "test.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return grammar
+}
+
+func TestBuildLALRTable(t *testing.T) {
+	input, _ := loadGrammarFixture("elr1")
+	table, conflicts := BuildLALRTable(input)
+	if len(conflicts) != 0 {
+		t.Fatalf("Expected no conflicts but got %v", conflicts)
+	}
+	accepted := [][]string{
+		{"id"},
+		{"id", "+", "id", "*", "id"},
+		{"(", "id", "+", "id", ")", "*", "id"},
+	}
+	for _, sentence := range accepted {
+		if !lrAccepts(table, "E", sentence...) {
+			t.Errorf("Expected %v to be accepted", sentence)
+		}
+	}
+	rejected := [][]string{
+		{"id", "+"},
+		{"(", "id"},
+		{"id", "id"},
+	}
+	for _, sentence := range rejected {
+		if lrAccepts(table, "E", sentence...) {
+			t.Errorf("Expected %v to be rejected", sentence)
+		}
+	}
+}
+
+func TestBuildLALRTableNotSLR(t *testing.T) {
+	// The classic grammar which is LALR(1) but not SLR(1).
+	grammar := loadTestGrammar(t, "<S>:\n"+
+		"  rhs:\n"+
+		"    - [<L>, '=', <R>]\n"+
+		"    - [<R>]\n"+
+		"<L>:\n"+
+		"  rhs:\n"+
+		"    - ['*', <R>]\n"+
+		"    - [id]\n"+
+		"<R>:\n"+
+		"  rhs:\n"+
+		"    - [<L>]\n")
+	table, conflicts := BuildLALRTable(grammar)
+	if len(conflicts) != 0 {
+		t.Fatalf("Expected no conflicts but got %v", conflicts)
+	}
+	if !lrAccepts(table, "S", "*", "id", "=", "id") || lrAccepts(table, "S", "id", "=") {
+		t.Errorf("Expected the table to recognise the language of the grammar")
+	}
+}
+
+func TestBuildLALRTableOptionals(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n"+
+		"  rhs:\n"+
+		"    -\n"+
+		"      - <A>:\n"+
+		"          params:\n"+
+		"            optional: true\n"+
+		"      - b\n"+
+		"      - <A>:\n"+
+		"          params:\n"+
+		"            optional: true\n"+
+		"<A>:\n"+
+		"  rhs:\n"+
+		"    - [a]\n")
+	table, conflicts := BuildLALRTable(grammar)
+	if len(conflicts) != 0 {
+		t.Fatalf("Expected no conflicts but got %v", conflicts)
+	}
+	if len(table.Rules) != 5 {
+		t.Errorf("Expected every combination of the optional symbols to be a rule but got %v rules", len(table.Rules))
+	}
+	for _, sentence := range [][]string{{"b"}, {"a", "b"}, {"b", "a"}, {"a", "b", "a"}} {
+		if !lrAccepts(table, "S", sentence...) {
+			t.Errorf("Expected %v to be accepted", sentence)
+		}
+	}
+}
+
+func TestBuildLALRTableShiftReduceConflict(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n"+
+		"  rhs:\n"+
+		"    - [if, c, <S>]\n"+
+		"    - [if, c, <S>, else, <S>]\n"+
+		"    - [other]\n")
+	table, conflicts := BuildLALRTable(grammar)
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected a single conflict but got %v", conflicts)
+	}
+	conflict := conflicts[0]
+	if conflict.Kind != ShiftReduceConflict || conflict.Terminal != "else" ||
+		conflict.Actions[0].Kind != ShiftAction || conflict.Actions[1].Kind != ReduceAction {
+		t.Errorf("Expected a shift/reduce conflict on else but got %v", conflict)
+	}
+	expected := "shift/reduce conflict in state " + strconv.Itoa(conflict.State) + " on else:\n" +
+		"    shift " + strconv.Itoa(conflict.Actions[0].Target) + "\n" +
+		"    reduce 0\n" +
+		"    item: S → if c S ·\n" +
+		"    item: S → if c S · else S\n" +
+		"    from: test.yml:3: <S>: `if` `c` S\n"
+	if conflict.String() != expected {
+		t.Errorf("Expected the conflict report \n%v but got \n%v", expected, conflict)
+	}
+	// The shift is chosen so else binds to the nearest if.
+	if !lrAccepts(table, "S", "if", "c", "if", "c", "other", "else", "other") {
+		t.Errorf("Expected the nested if statement to be accepted")
+	}
+}
+
+func TestBuildLALRTableReduceReduceConflict(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n"+
+		"  rhs:\n"+
+		"    - [<A>]\n"+
+		"    - [<B>]\n"+
+		"<A>:\n"+
+		"  rhs:\n"+
+		"    - [a]\n"+
+		"<B>:\n"+
+		"  rhs:\n"+
+		"    - [a]\n")
+	_, conflicts := BuildLALRTable(grammar)
+	if len(conflicts) != 1 || conflicts[0].Kind != ReduceReduceConflict || conflicts[0].Terminal != EndOfInput {
+		t.Fatalf("Expected a reduce/reduce conflict at the end of input but got %v", conflicts)
+	}
+	if conflicts[0].Actions[0].Target != 2 || conflicts[0].Actions[1].Target != 3 {
+		t.Errorf("Expected the rule which appears first to be chosen but got %v", conflicts[0])
+	}
+}
+
+func TestBuildBytesLALR(t *testing.T) {
+	input := "<E>:\n  rhs:\n    - [<E>, '+', id]\n    - [id]\n"
+	artefacts, err := BuildBytes([]byte(input), &BuildOptions{Package: "parser", Algorithm: "lalr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := string(artefacts.Source)
+	if artefacts.LRTable == nil || !strings.Contains(source, "generatedLRTables = &LRTables{") ||
+		!strings.Contains(source, "{ShiftAction, ") || !strings.Contains(source, "{AcceptAction, 0}") {
+		t.Errorf("Expected the LALR(1) tables to be generated but got \n%v", source)
+	}
+	_, err = BuildBytes([]byte(input), &BuildOptions{Package: "parser", Algorithm: "glr"})
+	if !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("Expected an unknown algorithm error but got %v", err)
+	}
+}
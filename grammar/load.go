@@ -0,0 +1,224 @@
+package grammar
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	// ErrUnknownFormat provides the error for the case when a grammar
+	// is provided in a storage format that is not supported.
+	ErrUnknownFormat = errors.New("unknown grammar storage format")
+	// ErrUnknownAlgorithm provides the error for the case when a parse
+	// table construction algorithm that is not supported is requested.
+	ErrUnknownAlgorithm = errors.New("unknown parse table algorithm")
+)
+
+// BuildError provides the error for a failure to load or build a grammar
+// along with the location in the grammar source it applies to.
+// Line and Column are 1-based and are 0 when the location is not known.
+type BuildError struct {
+	File       string
+	Line       int
+	Column     int
+	Production string
+	Err        error
+	// Holds the text of the symbol the error applies to
+	// so it can be located within the production.
+	symbol string
+}
+
+func (e *BuildError) Error() string {
+	location := e.File
+	if e.Line > 0 {
+		location += ":" + strconv.Itoa(e.Line)
+		if e.Column > 0 {
+			location += ":" + strconv.Itoa(e.Column)
+		}
+	}
+	msg := ""
+	if location != "" {
+		msg += location + ": "
+	}
+	if e.Production != "" {
+		msg += "production <" + e.Production + ">: "
+	}
+	return msg + e.Err.Error()
+}
+
+// Unwrap provides the underlying error.
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// ConflictError provides the error for the case when
+// a grammar has LL conflicts once transformed or
+// LR conflicts when the LALR(1) tables are built.
+type ConflictError struct {
+	Conflicts   []*Conflict
+	LRConflicts []*LRConflict
+}
+
+func (e *ConflictError) Error() string {
+	if len(e.LRConflicts) > 0 {
+		return fmt.Sprintf("the grammar is not LALR(1), %v conflicts were found", len(e.LRConflicts))
+	}
+	return fmt.Sprintf("the grammar is not LL(1), %v conflicts were found", len(e.Conflicts))
+}
+
+// LoadFile deals with loading the grammar in the given file
+// which is stored in the provided format.
+func LoadFile(grammarFile string, format string) (*Grammar, error) {
+	data, err := ioutil.ReadFile(grammarFile)
+	if err != nil {
+		return nil, &BuildError{File: grammarFile, Err: err}
+	}
+	return LoadBytes(data, format, grammarFile)
+}
+
+// Load deals with loading a grammar stored in the provided format
+// from the given reader, the file name is only used to describe
+// where errors occurred.
+func Load(r io.Reader, format string, fileName string) (*Grammar, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, &BuildError{File: fileName, Err: err}
+	}
+	return LoadBytes(data, format, fileName)
+}
+
+// LoadBytes deals with loading a grammar stored in the provided format
+// from the given data, the file name is only used to describe
+// where errors occurred.
+// The supported formats are "yaml", "json" which follows the same
+// structure as the YAML grammar and "ebnf" for the notation used
+// by the ECMAScript specification.
+func LoadBytes(data []byte, format string, fileName string) (*Grammar, error) {
+	grammar, problems, err := decode(data, format)
+	if err != nil {
+		return nil, locateError(err, data, fileName)
+	}
+	if len(problems) > 0 {
+		return nil, locateError(problems[0], data, fileName)
+	}
+	recordProvenance(grammar, data, fileName)
+	return grammar, nil
+}
+
+// Deals with decoding a grammar stored in the provided format, the problems
+// hold every malformed production while the error is for data that could not be decoded at all.
+func decode(data []byte, format string) (*Grammar, []*BuildError, error) {
+	target := yaml.MapSlice{}
+	switch format {
+	case "yaml":
+		err := yaml.Unmarshal(data, &target)
+		if err != nil {
+			return nil, nil, err
+		}
+	case "json":
+		// JSON is decoded with the YAML decoder as it is a subset of YAML
+		// that retains the order of productions, but it is checked first
+		// so syntax that is only valid in YAML is rejected.
+		var value interface{}
+		err := json.Unmarshal(data, &value)
+		if err == nil {
+			err = yaml.Unmarshal(data, &target)
+		}
+		if err != nil {
+			return nil, nil, locateJSONError(err, data)
+		}
+	case "ebnf":
+		grammar, problems := parseEBNF(data)
+		return grammar, problems, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %v", ErrUnknownFormat, format)
+	}
+	grammar, problems := decodeGrammar(target)
+	return grammar, problems, nil
+}
+
+// Deals with wrapping a JSON syntax error in a build error
+// which holds the line and column the error occurred at.
+func locateJSONError(err error, data []byte) error {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+	offset := int(syntaxErr.Offset)
+	if offset > len(data) {
+		offset = len(data)
+	}
+	// The offset is just after the character that caused the error.
+	if offset > 0 {
+		offset--
+	}
+	preceding := data[:offset]
+	line := bytes.Count(preceding, []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(preceding, '\n')
+	return &BuildError{Line: line, Column: column, Err: err}
+}
+
+// Deals with wrapping an error from decoding a grammar in a build error
+// which holds the location of the error in the source data.
+func locateError(err error, data []byte, fileName string) *BuildError {
+	buildErr, isBuildErr := err.(*BuildError)
+	if !isBuildErr {
+		buildErr = &BuildError{Err: err}
+		// The YAML decoder only reports the line an error occurred at
+		// as part of the error message.
+		lineMatch := regexp.MustCompile(`line (\d+)`).FindStringSubmatch(err.Error())
+		if lineMatch != nil {
+			buildErr.Line, _ = strconv.Atoi(lineMatch[1])
+		}
+	}
+	buildErr.File = fileName
+	if buildErr.Line == 0 && buildErr.Production != "" {
+		buildErr.Line, buildErr.Column = ProductionPosition(data, buildErr.Production)
+	}
+	return buildErr
+}
+
+// ProductionPosition provides the 1-based line and column the key of the production
+// with the given name is declared at in the source data, or 0 for both when it is not found.
+// References to the production from right-hand side rules share the same form
+// but are nested deeper, so the least indented match is taken to be the declaration.
+// Productions written in the specification notation are declared at the start of a line.
+func ProductionPosition(data []byte, name string) (int, int) {
+	position := productionPositions(bytes.Split(data, []byte("\n")))[name]
+	return position[0], position[1]
+}
+
+// Provides the 1-based line and column of the declaration of every production
+// in the lines of the source data keyed by the name of the production.
+func productionPositions(lines [][]byte) map[string][2]int {
+	positions := map[string][2]int{}
+	for i, text := range lines {
+		trimmed := strings.TrimLeft(string(text), " \t-")
+		indent := len(text) - len(trimmed)
+		trimmed = strings.Trim(trimmed, `"'`+"\r")
+		end := strings.Index(trimmed, ">")
+		if strings.HasPrefix(trimmed, "<") && end > 0 &&
+			strings.HasPrefix(strings.TrimLeft(trimmed[end+1:], `"'`), ":") {
+			name := trimmed[1:end]
+			if position, exists := positions[name]; !exists || indent+1 < position[1] {
+				positions[name] = [2]int{i + 1, indent + 1}
+			}
+		} else if indent == 0 {
+			if match := ebnfHeaderPattern.FindStringSubmatch(trimmed); match != nil {
+				if _, exists := positions[match[1]]; !exists {
+					positions[match[1]] = [2]int{i + 1, 1}
+				}
+			}
+		}
+	}
+	return positions
+}
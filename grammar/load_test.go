@@ -0,0 +1,96 @@
+package grammar
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadBytesUnknownFormat(t *testing.T) {
+	_, err := LoadBytes([]byte("<A>:\n  rhs: [[a]]\n"), "toml", "grammar.toml")
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected an unknown format error but got %v", err)
+	}
+}
+
+func TestLoadBytesReportsYAMLLine(t *testing.T) {
+	_, err := LoadBytes([]byte("<A>:\n  rhs:\n    - [a\n<B>:\n"), "yaml", "broken.yml")
+	var buildErr *BuildError
+	if !errors.As(err, &buildErr) {
+		t.Fatalf("Expected a build error but got %v", err)
+	}
+	if buildErr.File != "broken.yml" || buildErr.Line == 0 {
+		t.Errorf("Expected the error to hold the file and line but got %v", buildErr)
+	}
+}
+
+func TestLoadBytesReportsProduction(t *testing.T) {
+	input := "<A>:\n  rhs:\n    - [<B>]\n<B>:\n  rhs: b\n"
+	_, err := Load(strings.NewReader(input), "yaml", "invalid.yml")
+	var buildErr *BuildError
+	if !errors.As(err, &buildErr) {
+		t.Fatalf("Expected a build error but got %v", err)
+	}
+	if buildErr.Production != "B" || buildErr.Line != 4 || buildErr.Column != 1 ||
+		!errors.Is(err, ErrInvalidRightHandSide) {
+		t.Errorf("Expected an invalid right-hand side error for <B> at 4:1 but got %v", buildErr)
+	}
+	if buildErr.Error() != "invalid.yml:4:1: production <B>: "+ErrInvalidRightHandSide.Error() {
+		t.Errorf("Unexpected error message %q", buildErr.Error())
+	}
+}
+
+func TestBuildBytes(t *testing.T) {
+	input := "<E>:\n  rhs:\n    - [<E>, '+', id]\n    - [id]\n"
+	artefacts, err := BuildBytes([]byte(input), &BuildOptions{Package: "parser", File: "e.yml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(artefacts.Grammar.Productions) != 2 || artefacts.Table == nil ||
+		!strings.Contains(string(artefacts.Source), "package parser") {
+		t.Errorf("Expected the transformed grammar, table and source to be built but got %+v", artefacts)
+	}
+}
+
+func TestBuildBytesDefaultOptions(t *testing.T) {
+	input := "<E>:\n  rhs:\n    - [<E>, '+', id]\n    - [id]\n"
+	artefacts, err := BuildBytes([]byte(input), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if artefacts.Table == nil || !strings.Contains(string(artefacts.Source), "package parser") {
+		t.Errorf("Expected the table and source to be built with the default options but got %+v", artefacts)
+	}
+}
+
+func TestBuildBytesConflicts(t *testing.T) {
+	input := "<A>:\n  rhs:\n    - [<B>]\n    - [b, c]\n<B>:\n  rhs:\n    - [b]\n"
+	artefacts, err := BuildBytes([]byte(input), &BuildOptions{Package: "parser"})
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Expected a conflict error but got %v", err)
+	}
+	if len(conflictErr.Conflicts) != 1 || artefacts == nil || artefacts.Source != nil {
+		t.Errorf("Expected a single conflict and no generated source but got %v", conflictErr.Conflicts)
+	}
+}
+
+func TestLoadBytesReportsMalformedSymbol(t *testing.T) {
+	input := "<A>:\n  rhs:\n    -\n      - <B>:\n          optional: true\n<B>:\n  rhs: [[b]]\n"
+	_, err := LoadBytes([]byte(input), "yaml", "malformed.yml")
+	var buildErr *BuildError
+	if !errors.As(err, &buildErr) || !errors.Is(err, ErrMalformedSymbol) {
+		t.Fatalf("Expected a malformed symbol error but got %v", err)
+	}
+	if buildErr.Production != "A" || buildErr.Line != 1 {
+		t.Errorf("Expected the error to be located at the declaration of <A> but got %v", buildErr)
+	}
+}
+
+func TestProductionPositionSkipsReferences(t *testing.T) {
+	input := "<A>:\n  rhs:\n    -\n      - <B>:\n          params:\n            optional: true\n<B>:\n  rhs: [[b]]\n"
+	line, column := ProductionPosition([]byte(input), "B")
+	if line != 7 || column != 1 {
+		t.Errorf("Expected <B> to be declared at 7:1 but got %v:%v", line, column)
+	}
+}
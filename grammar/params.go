@@ -0,0 +1,245 @@
+package grammar
+
+import (
+	"strings"
+)
+
+// ExpandParameters deals with expanding every parameterised production
+// into one concrete production for each combination of its parameters,
+// e.g. Statement[Yield, Await, Return] with Yield and Return set becomes Statement_Yield_Return.
+// Passthrough arguments of non-terminals are resolved to the concrete production
+// they refer to, alternatives with conditions that do not hold for a combination are
+// dropped and combinations which are unreachable from the start symbols are pruned.
+// Productions which refine a cover production are instantiated for each combination
+// of the cover which has been reached and refine the concrete covers they were instantiated for.
+// When no start symbols are provided the default start symbols of the grammar are used.
+func ExpandParameters(grammar *Grammar, starts ...string) {
+	if len(starts) == 0 {
+		starts = StartSymbols(grammar)
+	}
+	productions := map[string]*Production{}
+	for _, prod := range grammar.Productions {
+		productions[prod.Name] = prod
+	}
+	// Holds the concrete productions created for each combination
+	// which has been reached, keyed by the original production name.
+	expanded := map[string]map[string]*Production{}
+	// Holds the instances of each production in the order they were reached.
+	instances := map[string][]*paramInstance{}
+	queue := []*paramInstance{}
+	enqueue := func(name string, enabled map[string]bool) string {
+		prod, exists := productions[name]
+		if !exists {
+			return name
+		}
+		concreteName := concreteProductionName(prod, enabled)
+		if _, exists := expanded[name]; !exists {
+			expanded[name] = map[string]*Production{}
+		}
+		if _, reached := expanded[name][concreteName]; !reached {
+			expanded[name][concreteName] = nil
+			instance := &paramInstance{prod, enabled, concreteName}
+			queue = append(queue, instance)
+			instances[name] = append(instances[name], instance)
+		}
+		return concreteName
+	}
+	for _, start := range starts {
+		enqueue(start, map[string]bool{})
+	}
+	// Holds the concrete covers each concrete refining production refines.
+	refines := map[string][]string{}
+	for len(queue) > 0 {
+		for len(queue) > 0 {
+			instance := queue[0]
+			queue = queue[1:]
+			concrete := &Production{
+				Name:       instance.name,
+				Provenance: instance.prod.Provenance,
+			}
+			if len(instance.prod.Params) > 0 {
+				concrete.Provenance = deriveProductionProvenance(instance.prod, ExpandParametersTransform)
+			}
+			for i, rule := range instance.prod.RHS {
+				newRule, holds := instantiateRule(rule, instance.enabled, productions, enqueue)
+				if holds {
+					concrete.RHS = append(concrete.RHS, newRule)
+					provenance := instance.prod.ProvenanceOf(i)
+					if len(instance.prod.Params) > 0 {
+						provenance = deriveProvenance(provenance, ExpandParametersTransform)
+					}
+					concrete.RuleProvenance = append(concrete.RuleProvenance, provenance)
+				}
+			}
+			expanded[instance.prod.Name][instance.name] = concrete
+		}
+		for _, prod := range grammar.Productions {
+			for _, cover := range prod.Refines {
+				for _, coverInstance := range instances[cover] {
+					// Only parameters declared by the refining production are passed on.
+					enabled := map[string]bool{}
+					for _, param := range prod.Params {
+						enabled[param] = coverInstance.enabled[param]
+					}
+					name := enqueue(prod.Name, enabled)
+					if !contains(refines[name], coverInstance.name) {
+						refines[name] = append(refines[name], coverInstance.name)
+					}
+				}
+			}
+		}
+	}
+	newProductions := []*Production{}
+	for _, prod := range grammar.Productions {
+		if concretes, reached := expanded[prod.Name]; reached {
+			for _, combination := range paramCombinations(prod.Params) {
+				enabled := map[string]bool{}
+				for _, param := range combination {
+					enabled[param] = true
+				}
+				if concrete, exists := concretes[concreteProductionName(prod, enabled)]; exists {
+					concrete.Refines = refines[concrete.Name]
+					newProductions = append(newProductions, concrete)
+				}
+			}
+		}
+	}
+	grammar.Productions = newProductions
+}
+
+// A production to be instantiated with a combination of parameters.
+type paramInstance struct {
+	prod    *Production
+	enabled map[string]bool
+	name    string
+}
+
+// Deals with instantiating a right-hand side rule for the given set of enabled
+// parameters, the second return value is false when the conditions of the rule do not hold.
+func instantiateRule(
+	rule []RHSRuleSymbol, enabled map[string]bool, productions map[string]*Production,
+	enqueue func(string, map[string]bool) string,
+) ([]RHSRuleSymbol, bool) {
+	newRule := []RHSRuleSymbol{}
+	holds := true
+	i := 0
+	for holds && i < len(rule) {
+		switch symbol := rule[i].(type) {
+		case *NonTerminalRHSRuleSymbol:
+			var optional *bool
+			args := map[string]bool{}
+			if symbol.params != nil {
+				holds = conditionsHold(symbol.params.Conditions, enabled)
+				optional = symbol.params.Optional
+				args = passthroughArguments(symbol.params.Passthrough, enabled)
+			}
+			if holds {
+				// Only parameters declared by the target production are passed on.
+				targetArgs := map[string]bool{}
+				if target, exists := productions[symbol.name]; exists {
+					for _, param := range target.Params {
+						if args[param] {
+							targetArgs[param] = true
+						}
+					}
+				}
+				newSymbol := &NonTerminalRHSRuleSymbol{name: enqueue(symbol.name, targetArgs)}
+				if optional != nil {
+					newSymbol.params = &NtRHSParams{Optional: optional}
+				}
+				newRule = append(newRule, newSymbol)
+			}
+		case *TerminalRHSRuleSymbol:
+			if symbol.params != nil {
+				holds = conditionsHold(symbol.params.Conditions, enabled)
+			}
+			if holds {
+				newRule = append(newRule, &TerminalRHSRuleSymbol{name: symbol.name})
+			}
+		case *ConditionalRHSRuleSymbol:
+			if symbol.params != nil {
+				holds = conditionsHold(symbol.params.Conditions, enabled)
+			}
+			if holds {
+				var parts []RHSRuleSymbol
+				parts, holds = instantiateRule(symbol.Parts, enabled, productions, enqueue)
+				newRule = append(newRule, parts...)
+			}
+		default:
+			newRule = append(newRule, symbol)
+		}
+		i++
+	}
+	return newRule, holds
+}
+
+// Determines whether each of the given conditions of the form
+// +Param or ~Param hold for the set of enabled parameters.
+func conditionsHold(conditions []string, enabled map[string]bool) bool {
+	holds := true
+	i := 0
+	for holds && i < len(conditions) {
+		condition := conditions[i]
+		if strings.HasPrefix(condition, "+") {
+			holds = enabled[condition[1:]]
+		} else if strings.HasPrefix(condition, "~") {
+			holds = !enabled[condition[1:]]
+		}
+		i++
+	}
+	return holds
+}
+
+// Resolves passthrough arguments of the form ?Param, +Param and ~Param
+// to the set of parameters enabled for the referenced production.
+func passthroughArguments(passthrough []string, enabled map[string]bool) map[string]bool {
+	args := map[string]bool{}
+	for _, arg := range passthrough {
+		if strings.HasPrefix(arg, "?") {
+			if enabled[arg[1:]] {
+				args[arg[1:]] = true
+			}
+		} else if strings.HasPrefix(arg, "+") {
+			args[arg[1:]] = true
+		}
+	}
+	return args
+}
+
+// Provides the name of the concrete production for the given
+// production and set of enabled parameters.
+func concreteProductionName(prod *Production, enabled map[string]bool) string {
+	name := prod.Name
+	for _, param := range prod.Params {
+		if enabled[param] {
+			name += "_" + param
+		}
+	}
+	return name
+}
+
+// Provides every combination of the given parameters, ordered by
+// the number of parameters in the combination and then by declaration order.
+func paramCombinations(params []string) [][]string {
+	combinations := [][]string{[]string{}}
+	for size := 1; size <= len(params); size++ {
+		data := make([]string, size)
+		combineParams(params, data, &combinations, 0, 0)
+	}
+	return combinations
+}
+
+// Creates each combination of the size of data from the parameters
+// starting at the given position and appends them to the combined list.
+func combineParams(params []string, data []string, combined *[][]string, start int, index int) {
+	if index == len(data) {
+		combination := make([]string, len(data))
+		copy(combination, data)
+		*combined = append(*combined, combination)
+		return
+	}
+	for i := start; i < len(params) && len(params)-i >= len(data)-index; i++ {
+		data[index] = params[i]
+		combineParams(params, data, combined, i+1, index+1)
+	}
+}
@@ -1,18 +1,75 @@
 package grammar
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 
 	yaml "gopkg.in/yaml.v2"
 )
 
+var (
+	nonTerminalPattern = regexp.MustCompile("^<(\\w|')+>$")
+	excludePattern     = regexp.MustCompile("^<\\!\\w+\\!>$")
+	operationPattern   = regexp.MustCompile("^<\\*\\w+\\*>$")
+)
+
 // Production provides a left hand side production
 // of the syntactic grammar of ECMAScript.
 type Production struct {
 	Name   string
 	Params []string
 	RHS    [][]RHSRuleSymbol
+	// Provenance holds where the production came from in the source grammar,
+	// nil when not known, productions introduced by a transformation always have one.
+	Provenance *Provenance
+	// RuleProvenance holds where each right-hand side rule came from
+	// in the source grammar, in the same order as the rules.
+	RuleProvenance []*Provenance
+	// Refines holds the names of the cover productions a production of a
+	// supplemental grammar refines, the tokens matched by one of the covers can be
+	// parsed again with the production as the goal symbol. Once parameters have
+	// been expanded these are the concrete cover productions.
+	Refines []string
+	// AST holds the annotation of each right-hand side rule of a source production
+	// which describes the abstract syntax tree node built when the rule is parsed,
+	// in the same order as the rules with nil for rules which are not annotated.
+	// Annotations are not carried over to the productions of a transformed grammar.
+	AST []*ASTMapping
+}
+
+// OriginName provides the name of the production in the source grammar
+// the production was derived from, which is its own name for source productions.
+func (p *Production) OriginName() string {
+	if p.Provenance != nil && p.Provenance.Production != "" {
+		return p.Provenance.Production
+	}
+	return p.Name
+}
+
+// Helper provides the name of the transformation which introduced the production
+// to eliminate left recursion or factor out common prefixes, empty for productions
+// which have a counterpart in the source grammar.
+func (p *Production) Helper() string {
+	if p.Provenance == nil {
+		return ""
+	}
+	switch p.Provenance.Transform {
+	case EliminateLeftRecursionTransform, LeftFactorTransform:
+		return p.Provenance.Transform
+	}
+	return ""
+}
+
+// Provides a production with the same name, parameters and metadata
+// as the production but without any right-hand side rules.
+func (p *Production) withoutRules() *Production {
+	return &Production{
+		Name:       p.Name,
+		Params:     p.Params,
+		Provenance: p.Provenance,
+		Refines:    p.Refines,
+	}
 }
 
 // Deals with extracting right hand side rules from the given
@@ -23,156 +80,280 @@ func (p *Production) extractRHSRuleSymbols(rhsIface interface{}) error {
 		return ErrInvalidRightHandSide
 	}
 	for i, ruleIface := range rhs {
-		p.RHS = append(p.RHS, []RHSRuleSymbol{})
 		rule, isRule := ruleIface.([]interface{})
-		if isRule {
-			for _, part := range rule {
-				name, isStr := part.(string)
-				if isStr {
-					// In the case the rule part is a string we only expect
-					// a non-terminal, terminal or an exclusion.
-					// This is down to the fact that only these types of
-					// symbols/functions that do not require parameters.
-					matched, err := regexp.MatchString("^<(\\w|')+>$", name)
-					if err != nil {
-						return err
-					}
-					if matched {
-						p.RHS[i] = append(p.RHS[i], &NonTerminalRHSRuleSymbol{
-							name: strings.TrimSuffix(strings.TrimPrefix(name, "<"), ">"),
-						})
-					} else {
-						matched, err = regexp.MatchString("^<\\!\\w+\\!>$", name)
-						if err != nil {
-							return err
-						}
-						if matched {
-							p.RHS[i] = append(p.RHS[i], &ExcludeRHSRuleSymbol{
-								name: strings.TrimSuffix(strings.TrimPrefix(name, "<!"), "!>"),
-							})
-						} else {
-							p.RHS[i] = append(p.RHS[i], &TerminalRHSRuleSymbol{
-								name: name,
-							})
-						}
-					}
-				} else {
-					// Extract the right hand side rule as a map
-					// we can extract parameters from.
-					pMap, isMapSlice := part.(yaml.MapSlice)
-					if isMapSlice {
-						for _, v := range pMap {
-							name, keyIsStr := v.Key.(string)
-							vMap, valueIsMapSlice := v.Value.(yaml.MapSlice)
-							if keyIsStr && valueIsMapSlice {
-								err := p.extractMapRule(name, i, vMap)
-								if err != nil {
-									return err
-								}
-							}
-						}
-					}
-				}
+		if !isRule {
+			return fmt.Errorf("%w: alternative %v is not a list of symbols", ErrInvalidRightHandSide, i)
+		}
+		symbols := []RHSRuleSymbol{}
+		for _, part := range rule {
+			symbol, err := extractSymbol(part, true)
+			if err != nil {
+				return fmt.Errorf("alternative %v: %w", i, err)
 			}
+			symbols = append(symbols, symbol)
 		}
+		p.RHS = append(p.RHS, symbols)
 	}
 	return nil
 }
 
-// Deals with extracting a rule as a map
-// in order to extract parameters for the rule.
-func (p *Production) extractMapRule(name string, i int, v yaml.MapSlice) error {
-	matched, err := regexp.MatchString("^<(\\w|')+>$", name)
+// Deals with extracting a single right hand side symbol which is either
+// a string or a map of the symbol to its parameters.
+// Operations such as conditionals and lookaheads are only allowed
+// at the top level of a rule.
+func extractSymbol(part interface{}, allowOperations bool) (RHSRuleSymbol, error) {
+	if name, isStr := part.(string); isStr {
+		// In the case the rule part is a string we only expect
+		// a non-terminal, terminal or an exclusion.
+		// This is down to the fact that only these types of
+		// symbols/functions that do not require parameters.
+		return extractStringSymbol(name)
+	}
+	pMap, isMapSlice := part.(yaml.MapSlice)
+	if !isMapSlice || len(pMap) != 1 {
+		return nil, fmt.Errorf("%w: expected a symbol name or a map of a single symbol to its parameters", ErrMalformedSymbol)
+	}
+	name, keyIsStr := pMap[0].Key.(string)
+	if !keyIsStr {
+		return nil, fmt.Errorf("%w: the key %v is not a symbol name", ErrMalformedSymbol, pMap[0].Key)
+	}
+	v, isMap := pMap[0].Value.(yaml.MapSlice)
+	if !isMap {
+		return nil, fmt.Errorf("%w: %v must map to its parameters", ErrMalformedSymbol, name)
+	}
+	switch {
+	case name == "<*Conditional*>" && allowOperations:
+		return extractConditional(v)
+	case name == "<*Lookahead*>" && allowOperations:
+		return extractLookahead(v)
+	case operationPattern.MatchString(name):
+		return nil, fmt.Errorf("%w: %v is not allowed here", ErrMalformedSymbol, name)
+	case nonTerminalPattern.MatchString(name):
+		return extractNonTerminal(name, v)
+	default:
+		// If we get here, it is a terminal symbol which has some parameters.
+		return extractTerminal(name, v)
+	}
+}
+
+// Deals with extracting a symbol which is provided without parameters.
+func extractStringSymbol(name string) (RHSRuleSymbol, error) {
+	if nonTerminalPattern.MatchString(name) {
+		return &NonTerminalRHSRuleSymbol{
+			name: strings.TrimSuffix(strings.TrimPrefix(name, "<"), ">"),
+		}, nil
+	}
+	if excludePattern.MatchString(name) {
+		return &ExcludeRHSRuleSymbol{
+			name: strings.TrimSuffix(strings.TrimPrefix(name, "<!"), "!>"),
+		}, nil
+	}
+	if operationPattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: %v requires parameters", ErrMalformedSymbol, name)
+	}
+	return &TerminalRHSRuleSymbol{name: name}, nil
+}
+
+// Deals with extracting a non-terminal symbol along with
+// its passthrough arguments, conditions and whether it is optional.
+func extractNonTerminal(name string, v yaml.MapSlice) (RHSRuleSymbol, error) {
+	symbol := &NonTerminalRHSRuleSymbol{
+		name: strings.TrimSuffix(strings.TrimPrefix(name, "<"), ">"),
+	}
+	paramMapSlice, err := extractParamsMap(name, ErrMalformedSymbol, v, "params")
+	if err != nil || paramMapSlice == nil {
+		return symbol, err
+	}
+	err = checkKeys(paramMapSlice, ErrMalformedSymbol, name+" params", "passthrough", "conditions", "optional")
+	if err != nil {
+		return nil, err
+	}
+	params := &NtRHSParams{}
+	params.Passthrough, err = extractStringList(get(paramMapSlice, "passthrough"), ErrMalformedSymbol, name+" passthrough")
+	if err != nil {
+		return nil, err
+	}
+	err = checkPrefixes(params.Passthrough, ErrMalformedSymbol, name+" passthrough", "?+~")
+	if err != nil {
+		return nil, err
+	}
+	params.Conditions, err = extractConditions(get(paramMapSlice, "conditions"), ErrMalformedSymbol, name)
+	if err != nil {
+		return nil, err
+	}
+	if optional := get(paramMapSlice, "optional"); optional != nil {
+		optionalBool, isBool := optional.(bool)
+		if !isBool {
+			return nil, fmt.Errorf("%w: %v optional must be true or false", ErrMalformedSymbol, name)
+		}
+		params.Optional = &optionalBool
+	}
+	symbol.params = params
+	return symbol, nil
+}
+
+// Deals with extracting a terminal symbol which has conditions.
+func extractTerminal(name string, v yaml.MapSlice) (RHSRuleSymbol, error) {
+	paramMapSlice, err := extractParamsMap(name, ErrMalformedSymbol, v, "params")
+	if err != nil {
+		return nil, err
+	}
+	err = checkKeys(paramMapSlice, ErrMalformedSymbol, name+" params", "conditions")
+	if err != nil {
+		return nil, err
+	}
+	params := &TRHSParams{}
+	params.Conditions, err = extractConditions(get(paramMapSlice, "conditions"), ErrMalformedSymbol, name)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	return &TerminalRHSRuleSymbol{
+		name:   name,
+		params: params,
+	}, nil
+}
+
+// Deals with extracting a <*Conditional*> block, which must have
+// at least one condition and at least one part.
+func extractConditional(v yaml.MapSlice) (RHSRuleSymbol, error) {
+	paramMapSlice, err := extractParamsMap("<*Conditional*>", ErrMalformedConditional, v, "params", "parts")
+	if err == nil {
+		err = checkKeys(paramMapSlice, ErrMalformedConditional, "<*Conditional*> params", "conditions")
+	}
+	if err != nil {
+		return nil, err
+	}
+	params := &CRHSParams{}
+	params.Conditions, err = extractConditions(get(paramMapSlice, "conditions"), ErrMalformedConditional, "<*Conditional*>")
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Conditions) == 0 {
+		return nil, fmt.Errorf("%w: at least one condition is required", ErrMalformedConditional)
+	}
+	partsList, isPartsList := get(v, "parts").([]interface{})
+	if !isPartsList || len(partsList) == 0 {
+		return nil, fmt.Errorf("%w: parts must be a non-empty list of symbols", ErrMalformedConditional)
+	}
+	parts, err := ExtractConditionalPartRules(partsList)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedConditional, err)
+	}
+	return &ConditionalRHSRuleSymbol{
+		Parts:  parts,
+		params: params,
+	}, nil
+}
+
+// Deals with extracting a <*Lookahead*> block, which must
+// exclude at least one sequence of symbols.
+func extractLookahead(v yaml.MapSlice) (RHSRuleSymbol, error) {
+	paramMapSlice, err := extractParamsMap("<*Lookahead*>", ErrMalformedLookahead, v, "params")
+	if err == nil {
+		err = checkKeys(paramMapSlice, ErrMalformedLookahead, "<*Lookahead*> params", "exclude")
+	}
+	if err != nil {
+		return nil, err
+	}
+	exclusions, isExclusionList := get(paramMapSlice, "exclude").([]interface{})
+	if !isExclusionList || len(exclusions) == 0 {
+		return nil, fmt.Errorf("%w: exclude must be a non-empty list of symbol sequences", ErrMalformedLookahead)
+	}
+	params := &LaRHSParams{}
+	params.Exclude, err = ExtractLookaheadExclusions(exclusions)
+	if err != nil {
+		return nil, err
+	}
+	return &LookaheadRHSRuleSymbol{
+		params: params,
+	}, nil
+}
+
+// Deals with retrieving the params map of a symbol, ensuring
+// the symbol map only holds the allowed keys.
+// A nil map is returned when the symbol has no params.
+func extractParamsMap(name string, kind error, v yaml.MapSlice, allowed ...string) (yaml.MapSlice, error) {
+	err := checkKeys(v, kind, name, allowed...)
+	if err != nil {
+		return nil, err
 	}
 	paramIface := get(v, "params")
+	if paramIface == nil {
+		return nil, nil
+	}
 	paramMapSlice, isParamMapSlice := paramIface.(yaml.MapSlice)
-	if matched {
-		if paramIface != nil && isParamMapSlice {
-			params := &NtRHSParams{}
-			pt := get(paramMapSlice, "passthrough")
-			conditions := get(paramMapSlice, "conditions")
-			optional := get(paramMapSlice, "optional")
-			if pt != nil {
-				ptSlice := pt.([]interface{})
-				for _, ptParam := range ptSlice {
-					ptParamStr := ptParam.(string)
-					params.Passthrough = append(params.Passthrough, ptParamStr)
-				}
-			}
-			if conditions != nil {
-				condSlice := conditions.([]interface{})
-				for _, condParam := range condSlice {
-					condParamStr := condParam.(string)
-					params.Conditions = append(params.Conditions, condParamStr)
-				}
-			}
-			if optional != nil {
-				optionalBool := optional.(bool)
-				params.Optional = &optionalBool
-			}
-			p.RHS[i] = append(p.RHS[i], &NonTerminalRHSRuleSymbol{
-				name:   strings.TrimSuffix(strings.TrimPrefix(name, "<"), ">"),
-				params: params,
-			})
+	if !isParamMapSlice {
+		return nil, fmt.Errorf("%w: %v params must be a map", kind, name)
+	}
+	return paramMapSlice, nil
+}
+
+// Deals with extracting a list of conditions of the form +Param or ~Param.
+func extractConditions(conditions interface{}, kind error, name string) ([]string, error) {
+	list, err := extractStringList(conditions, kind, name+" conditions")
+	if err != nil {
+		return nil, err
+	}
+	return list, checkPrefixes(list, kind, name+" conditions", "+~")
+}
+
+// Deals with extracting a list of strings from a YAML sequence,
+// a missing value provides an empty list.
+func extractStringList(value interface{}, kind error, field string) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, isList := value.([]interface{})
+	if !isList {
+		return nil, fmt.Errorf("%w: %v must be a list", kind, field)
+	}
+	list := []string{}
+	for _, item := range items {
+		str, isStr := item.(string)
+		if !isStr {
+			return nil, fmt.Errorf("%w: %v must only hold strings, found %v", kind, field, item)
 		}
-	} else {
-		if name == "<*Conditional*>" {
-			params := &CRHSParams{}
-			conditions := get(paramMapSlice, "conditions")
-			if conditions != nil {
-				condSlice := conditions.([]interface{})
-				for _, condParam := range condSlice {
-					condParamStr := condParam.(string)
-					params.Conditions = append(params.Conditions, condParamStr)
-				}
-			}
-			partsIface := get(v, "parts")
-			partsList, isPartsList := partsIface.([]interface{})
-			parts := []RHSRuleSymbol{}
-			if partsIface != nil && isPartsList {
-				parts, err = ExtractConditionalPartRules(partsList)
-				if err != nil {
-					return err
-				}
-			}
-			p.RHS[i] = append(p.RHS[i], &ConditionalRHSRuleSymbol{
-				Parts:  parts,
-				params: params,
-			})
-		} else {
-			if name == "<*Lookahead*>" {
-				params := &LaRHSParams{}
-				params.Exclude = [][]RHSRuleSymbol{}
-				if paramIface != nil && isParamMapSlice {
-					exclusionsIface := get(paramMapSlice, "exclude")
-					exclusions, isExclusionList := exclusionsIface.([]interface{})
-					if exclusionsIface != nil && isExclusionList {
-						params.Exclude = ExtractLookaheadExclusions(exclusions)
-					}
-				}
-				p.RHS[i] = append(p.RHS[i], &LookaheadRHSRuleSymbol{
-					params: params,
-				})
-			} else {
-				// If we get here, it is a terminal symbol which has some parameters.
-				params := &TRHSParams{}
-				if paramIface != nil && isParamMapSlice {
-					conditions := get(paramMapSlice, "conditions")
-					if conditions != nil {
-						condSlice := conditions.([]interface{})
-						for _, condParam := range condSlice {
-							condParamStr := condParam.(string)
-							params.Conditions = append(params.Conditions, condParamStr)
-						}
-					}
-				}
-				p.RHS[i] = append(p.RHS[i], &TerminalRHSRuleSymbol{
-					name:   name,
-					params: params,
-				})
-			}
+		list = append(list, str)
+	}
+	return list, nil
+}
+
+// Extracts a list of non-terminals of the form <Name> as their names.
+func extractNonTerminalList(value interface{}, kind error, field string) ([]string, error) {
+	list, err := extractStringList(value, kind, field)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, item := range list {
+		if !nonTerminalPattern.MatchString(item) {
+			return nil, fmt.Errorf("%w: %v in %v must be a non-terminal of the form <Name>", kind, item, field)
+		}
+		names = append(names, strings.TrimSuffix(strings.TrimPrefix(item, "<"), ">"))
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return names, nil
+}
+
+// Ensures every key of the given map is one of the allowed keys.
+func checkKeys(mapSlc yaml.MapSlice, kind error, field string, allowed ...string) error {
+	for _, item := range mapSlc {
+		key, isStr := item.Key.(string)
+		if !isStr || !contains(allowed, key) {
+			return fmt.Errorf("%w: unexpected key %v in %v", kind, item.Key, field)
+		}
+	}
+	return nil
+}
+
+// Ensures each of the given parameter references starts with
+// one of the allowed prefix characters.
+func checkPrefixes(list []string, kind error, field string, prefixes string) error {
+	for _, item := range list {
+		if len(item) < 2 || !strings.ContainsAny(item[:1], prefixes) {
+			return fmt.Errorf("%w: %v in %v must be a parameter prefixed with one of %v", kind, item, field, prefixes)
 		}
 	}
 	return nil
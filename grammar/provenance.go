@@ -0,0 +1,175 @@
+package grammar
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ExpandParametersTransform provides the name of the transformation
+	// which instantiates parameterised productions.
+	ExpandParametersTransform = "expand parameters"
+	// ExpandOptionalsTransform provides the name of the transformation
+	// which expands rules with optional symbols.
+	ExpandOptionalsTransform = "expand optionals"
+	// EliminateLeftRecursionTransform provides the name of the
+	// transformation which eliminates left recursion.
+	EliminateLeftRecursionTransform = "eliminate left recursion"
+	// LeftFactorTransform provides the name of the transformation
+	// which factors out the common prefixes of rules.
+	LeftFactorTransform = "left factor"
+)
+
+// Provenance provides where a production or right-hand side rule
+// came from in the source grammar, which is retained as the grammar is
+// transformed so synthetic productions can be traced back to the source.
+type Provenance struct {
+	// File holds the name of the source file, empty when not known.
+	File string
+	// Line holds the 1-based line of the source production or alternative,
+	// 0 when not known.
+	Line int
+	// Production holds the name of the source production.
+	Production string
+	// Rule holds the source alternative in the notation of the ECMAScript
+	// specification, empty for the provenance of a production.
+	Rule string
+	// Alternative holds the 0-based index of the source alternative
+	// in its production, only meaningful when Rule is set.
+	Alternative int
+	// Transform holds the name of the last transformation that created
+	// the production or rule, empty for those in the source grammar.
+	Transform string
+}
+
+// String provides a human-readable description of the provenance
+// in the form file:line: <Production>: rule (transform).
+func (p *Provenance) String() string {
+	location := p.File
+	if p.Line > 0 {
+		location += ":" + strconv.Itoa(p.Line)
+	}
+	description := ""
+	if location != "" {
+		description += location + ": "
+	}
+	description += "<" + p.Production + ">"
+	if p.Rule != "" {
+		description += ": " + p.Rule
+	}
+	if p.Transform != "" {
+		description += " (" + p.Transform + ")"
+	}
+	return description
+}
+
+// ProvenanceOf provides where the right-hand side rule at the given index
+// came from, falling back to the provenance of the production when the rule
+// can not be traced, nil when neither is known.
+func (p *Production) ProvenanceOf(alternative int) *Provenance {
+	if len(p.RuleProvenance) == len(p.RHS) && alternative < len(p.RuleProvenance) &&
+		p.RuleProvenance[alternative] != nil {
+		return p.RuleProvenance[alternative]
+	}
+	return p.Provenance
+}
+
+// Provides the provenance of a production or rule created from
+// another by the given transformation.
+func deriveProvenance(source *Provenance, transform string) *Provenance {
+	if source == nil {
+		return nil
+	}
+	derived := *source
+	derived.Transform = transform
+	return &derived
+}
+
+// Provides the provenance of a production created from the given production
+// by the provided transformation, which has no specific rule. Productions which
+// were not loaded from a source are given a provenance of their own so that
+// the origin of the production and the transformation are still known.
+func deriveProductionProvenance(prod *Production, transform string) *Provenance {
+	source := prod.Provenance
+	if source == nil {
+		source = &Provenance{Production: prod.OriginName()}
+	}
+	derived := deriveProvenance(source, transform)
+	derived.Rule = ""
+	return derived
+}
+
+// Deals with recording where each production and right-hand side rule of a
+// loaded grammar came from in the source data. Alternatives which can not be
+// located are given the line of their production.
+func recordProvenance(grammar *Grammar, data []byte, fileName string) {
+	lines := bytes.Split(data, []byte("\n"))
+	positions := productionPositions(lines)
+	for _, prod := range grammar.Productions {
+		line, column := positions[prod.Name][0], positions[prod.Name][1]
+		prod.Provenance = &Provenance{File: fileName, Line: line, Production: prod.Name}
+		if len(prod.RuleProvenance) == len(prod.RHS) {
+			// The specification notation records the line of each alternative as it is parsed.
+			for _, provenance := range prod.RuleProvenance {
+				provenance.File = fileName
+			}
+			continue
+		}
+		ruleLines := []int{}
+		if line > 0 {
+			ruleLines = alternativeLines(lines, line, column)
+		}
+		prod.RuleProvenance = []*Provenance{}
+		for i, rule := range prod.RHS {
+			ruleLine := line
+			if len(ruleLines) == len(prod.RHS) {
+				ruleLine = ruleLines[i]
+			}
+			prod.RuleProvenance = append(prod.RuleProvenance, &Provenance{
+				File:        fileName,
+				Line:        ruleLine,
+				Production:  prod.Name,
+				Rule:        ruleText(rule),
+				Alternative: i,
+			})
+		}
+	}
+}
+
+// Provides the 1-based line of each item of the rhs list of the YAML production
+// declared at the given position, the items are the least indented list entries
+// which follow the rhs key up until the next key of the production.
+func alternativeLines(lines [][]byte, prodLine int, prodColumn int) []int {
+	ruleLines := []int{}
+	rhsIndent := -1
+	itemIndent := -1
+	for i := prodLine; i < len(lines); i++ {
+		text := strings.TrimRight(string(lines[i]), "\r")
+		trimmed := strings.TrimLeft(text, " ")
+		indent := len(text) - len(trimmed)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if indent < prodColumn {
+			// The next production has been reached.
+			return ruleLines
+		}
+		isItem := strings.HasPrefix(trimmed, "-")
+		switch {
+		case rhsIndent < 0:
+			if strings.HasPrefix(trimmed, "rhs:") {
+				rhsIndent = indent
+			}
+		case itemIndent < 0 && isItem:
+			itemIndent = indent
+			ruleLines = append(ruleLines, i+1)
+		case indent < itemIndent || (indent == itemIndent && !isItem) || (itemIndent < 0 && indent <= rhsIndent):
+			// The list of alternatives has ended.
+			return ruleLines
+		case indent == itemIndent:
+			ruleLines = append(ruleLines, i+1)
+		}
+	}
+	return ruleLines
+}
@@ -0,0 +1,139 @@
+package grammar
+
+import (
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const provenanceGrammar = `# Expressions
+<E>:
+  params: [In]
+  rhs:
+    - [<E>, '+', <T>]
+    -
+      - <T>:
+          params:
+            optional: true
+<T>:
+  rhs:
+    - [id, '(', ')']
+    - [id]
+`
+
+func TestLoadRecordsProvenance(t *testing.T) {
+	grammar := loadTestGrammar(t, provenanceGrammar)
+	expected := map[string][]int{"E": {5, 6}, "T": {12, 13}}
+	for _, prod := range grammar.Productions {
+		if prod.Provenance == nil || prod.Provenance.File != "test.yml" || prod.Provenance.Transform != "" {
+			t.Fatalf("Expected <%v> to be loaded from test.yml but got %v", prod.Name, prod.Provenance)
+		}
+		for i, line := range expected[prod.Name] {
+			provenance := prod.ProvenanceOf(i)
+			if provenance.Line != line || provenance.Rule != ruleText(prod.RHS[i]) {
+				t.Errorf("Expected alternative %v of <%v> to be from line %v but got %v", i, prod.Name, line, provenance)
+			}
+		}
+	}
+	if grammar.Productions[0].ProvenanceOf(0).String() != "test.yml:5: <E>: E `+` T" {
+		t.Errorf("Expected the provenance to be described in the specification notation but got %v",
+			grammar.Productions[0].ProvenanceOf(0))
+	}
+}
+
+func TestLoadEBNFRecordsProvenance(t *testing.T) {
+	input := "Statement :\n" +
+		"  Block\n" +
+		"  // The empty statement.\n" +
+		"  `;`\n" +
+		"Block :\n" +
+		"  `{` `}`\n"
+	grammar, err := LoadBytes([]byte(input), "ebnf", "test.grammar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement := grammar.Productions[0]
+	if statement.Provenance.Line != 1 || statement.ProvenanceOf(0).Line != 2 || statement.ProvenanceOf(1).Line != 4 ||
+		statement.ProvenanceOf(1).File != "test.grammar" {
+		t.Errorf("Expected the alternatives to be from lines 2 and 4 but got %v and %v",
+			statement.ProvenanceOf(0), statement.ProvenanceOf(1))
+	}
+}
+
+func TestTransformRetainsProvenance(t *testing.T) {
+	grammar := loadTestGrammar(t, provenanceGrammar)
+	ExpandParameters(grammar, "E")
+	ExpandOptionals(grammar)
+	LLkify(grammar)
+	expected := map[string][]string{
+		// Both alternatives come from the optional T.
+		"E": {
+			"test.yml:6: <E>: Topt (eliminate left recursion)",
+			"test.yml:6: <E>: Topt (eliminate left recursion)",
+		},
+		"E'": {
+			"test.yml:5: <E>: E `+` T (eliminate left recursion)",
+			"test.yml:2: <E> (eliminate left recursion)",
+		},
+		"T": {"test.yml:12: <T>: `id` `(` `)` (left factor)"},
+		"TA0": {
+			"test.yml:12: <T>: `id` `(` `)` (left factor)",
+			"test.yml:13: <T>: `id` (left factor)",
+		},
+	}
+	for _, prod := range grammar.Productions {
+		if len(prod.RuleProvenance) != len(prod.RHS) {
+			t.Fatalf("Expected every rule of <%v> to have a provenance", prod.Name)
+		}
+		for i, description := range expected[prod.Name] {
+			if prod.ProvenanceOf(i).String() != description {
+				t.Errorf("Expected alternative %v of <%v> to be from %v but got %v", i, prod.Name, description, prod.ProvenanceOf(i))
+			}
+		}
+	}
+}
+
+func TestTransformDerivesOriginAndHelper(t *testing.T) {
+	grammar := &Grammar{}
+	input := "<E>:\n  rhs:\n    - [<E>, '+', <T>]\n    - [<T>]\n<T>:\n  rhs:\n    - [id, '(', ')']\n    - [id]\n"
+	if err := yaml.Unmarshal([]byte(input), grammar); err != nil {
+		t.Fatal(err)
+	}
+	LLkify(grammar)
+	expected := map[string][2]string{
+		"E":   {"E", ""},
+		"E'":  {"E", EliminateLeftRecursionTransform},
+		"T":   {"T", ""},
+		"TA0": {"T", LeftFactorTransform},
+	}
+	for _, prod := range grammar.Productions {
+		if actual := [2]string{prod.OriginName(), prod.Helper()}; actual != expected[prod.Name] {
+			t.Errorf("Expected <%v> to have the origin and helper %v but got %v", prod.Name, expected[prod.Name], actual)
+		}
+	}
+}
+
+func TestConflictsCiteProvenance(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n  rhs:\n    - [a, b]\n    - [a, c]\n")
+	conflicts := DetectConflicts(grammar, Analyse(grammar, 1))
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected a single conflict but got %v", conflicts)
+	}
+	report := conflicts[0].String()
+	for _, expected := range []string{"from: test.yml:3: <S>: `a` `b`", "from: test.yml:4: <S>: `a` `c`"} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected the report to contain %q but got\n%v", expected, report)
+		}
+	}
+}
+
+func TestBuildBytesCitesProvenance(t *testing.T) {
+	artefacts, err := BuildBytes([]byte(provenanceGrammar), &BuildOptions{File: "test.yml", Package: "parser"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(artefacts.Source), "test.yml:5: <E>: E `+` T (eliminate left recursion)") {
+		t.Errorf("Expected the generated rules to cite the source rules but got \n%v", string(artefacts.Source))
+	}
+}
@@ -0,0 +1,348 @@
+package grammar
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+var (
+	// ErrUnknownStartSymbol provides the error for the case when
+	// a sample is requested for a symbol which has no production.
+	ErrUnknownStartSymbol = errors.New("unknown start symbol")
+	// ErrSampleExhausted provides the error for the case when no sample
+	// which satisfies the lookahead restrictions of the grammar could be generated.
+	ErrSampleExhausted = errors.New("unable to generate a sample which satisfies the lookahead restrictions")
+)
+
+const (
+	// DefaultSampleDepth provides the default depth budget of a sample.
+	DefaultSampleDepth = 40
+	// DefaultSampleSize provides the default number of terminals budgeted for a sample.
+	DefaultSampleSize = 200
+	// The number of times a sequence is regenerated when it
+	// starts with a sequence excluded by a lookahead restriction.
+	sampleAttempts = 50
+	// The name of the symbol used in [no LineTerminator here] restrictions.
+	lineTerminator = "LineTerminator"
+)
+
+// ECMAScriptTerminals provides sample source text for the terminals of the
+// ECMAScript syntactic grammar which are non-terminals of the lexical grammar.
+var ECMAScriptTerminals = map[string][]string{
+	"IdentifierName":           {"a", "b", "c", "foo", "bar", "_x", "$y", "value1"},
+	"NumericLiteral":           {"0", "1", "42", "3.14", "0x1F", "0o17", "0b101", "1e3"},
+	"StringLiteral":            {"'a'", "\"text\"", "''", "'\\n'"},
+	"BooleanLiteral":           {"true", "false"},
+	"NullLiteral":              {"null"},
+	"RegularExpressionLiteral": {"/a+/g", "/[0-9]*/", "/\\d{2}/i"},
+	"NoSubstitutionTemplate":   {"`text`", "``"},
+	"TemplateHead":             {"`a${"},
+	"TemplateMiddle":           {"}b${"},
+	"TemplateTail":             {"}c`"},
+}
+
+// SampleOptions provides the options used to generate random
+// sentences from a grammar.
+type SampleOptions struct {
+	// Seed provides the seed of the random source, the same seed
+	// always generates the same samples from the same grammar.
+	Seed int64
+	// MaxDepth provides the depth of the derivation after which the
+	// alternatives that lead to the shallowest derivations are chosen.
+	// Defaults to DefaultSampleDepth.
+	MaxDepth int
+	// MaxSize provides the number of terminals after which the
+	// alternatives that lead to the shallowest derivations are chosen.
+	// Defaults to DefaultSampleSize.
+	MaxSize int
+	// Terminals provides the source text to choose from for each terminal,
+	// terminals without any source text are written as their name.
+	// Defaults to ECMAScriptTerminals.
+	Terminals map[string][]string
+}
+
+// Sampler provides a generator of random sentences of a grammar,
+// which can be used to fuzz a lexer and parser built from the grammar.
+type Sampler struct {
+	productions map[string]*Production
+	// Holds the height of the shallowest derivation tree of each production.
+	heights    map[string]int
+	options    SampleOptions
+	random     *rand.Rand
+	tokens     []sampleToken
+	lookaheads []sampleLookahead
+}
+
+// A terminal of a sample along with whether a
+// line terminator is allowed to precede it.
+type sampleToken struct {
+	terminal         string
+	text             string
+	noLineTerminator bool
+}
+
+// A lookahead restriction at a position of a sample.
+type sampleLookahead struct {
+	position   int
+	exclusions [][]*PredicateTerminal
+}
+
+// NewSampler deals with creating a sampler for the given grammar, the grammar
+// is not modified as its parameters are expanded on a copy of its productions.
+// Nil options are treated as the default options.
+// When no start symbols are provided the default start symbols of the grammar are used.
+func NewSampler(grammar *Grammar, options *SampleOptions, starts ...string) *Sampler {
+	if options == nil {
+		options = &SampleOptions{}
+	}
+	expanded := &Grammar{Productions: grammar.Productions}
+	ExpandParameters(expanded, starts...)
+	sampler := &Sampler{
+		productions: map[string]*Production{},
+		options:     *options,
+		random:      rand.New(rand.NewSource(options.Seed)),
+	}
+	if sampler.options.MaxDepth == 0 {
+		sampler.options.MaxDepth = DefaultSampleDepth
+	}
+	if sampler.options.MaxSize == 0 {
+		sampler.options.MaxSize = DefaultSampleSize
+	}
+	if sampler.options.Terminals == nil {
+		sampler.options.Terminals = ECMAScriptTerminals
+	}
+	for _, prod := range expanded.Productions {
+		sampler.productions[prod.Name] = prod
+	}
+	sampler.computeHeights(expanded)
+	return sampler
+}
+
+// Sample provides a random sentence derived from the given start symbol.
+// Lookahead restrictions and [no LineTerminator here] restrictions are respected,
+// consecutive samples from the same sampler continue with the same random source.
+func Sample(grammar *Grammar, start string, options *SampleOptions) (string, error) {
+	return NewSampler(grammar, options, start).Sample(start)
+}
+
+// Sample provides a random sentence derived from the given start symbol,
+// which must be one of the start symbols the sampler was created with.
+func (s *Sampler) Sample(start string) (string, error) {
+	if _, exists := s.productions[start]; !exists {
+		return "", fmt.Errorf("%w: <%v>", ErrUnknownStartSymbol, start)
+	}
+	for attempt := 0; attempt < sampleAttempts; attempt++ {
+		s.tokens = []sampleToken{}
+		s.lookaheads = []sampleLookahead{}
+		if err := s.expandProduction(start, 0); err != nil {
+			return "", err
+		}
+		// Exclusions can extend past the end of the rule they appear in
+		// so each restriction is checked again against the whole sample.
+		satisfied := true
+		i := 0
+		for satisfied && i < len(s.lookaheads) {
+			satisfied = !s.isExcluded(s.lookaheads[i].position, s.lookaheads[i].exclusions)
+			i++
+		}
+		if satisfied {
+			return s.render(), nil
+		}
+	}
+	return "", fmt.Errorf("%w for <%v>", ErrSampleExhausted, start)
+}
+
+// Deals with computing the height of the shallowest derivation tree of each
+// production, productions which never derive a sequence of terminals have no height.
+func (s *Sampler) computeHeights(grammar *Grammar) {
+	s.heights = map[string]int{}
+	for _, prod := range grammar.Productions {
+		s.heights[prod.Name] = math.MaxInt32
+	}
+	changed := true
+	for changed {
+		changed = false
+		for _, prod := range grammar.Productions {
+			for _, rule := range prod.RHS {
+				if height := s.ruleHeight(rule); height < s.heights[prod.Name] {
+					s.heights[prod.Name] = height
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// Provides the height of the shallowest derivation tree of a rule,
+// optional symbols do not count as they can be left out.
+func (s *Sampler) ruleHeight(rule []RHSRuleSymbol) int {
+	height := 0
+	for _, symbol := range rule {
+		if nonTerminal, isNonTerminal := symbol.(*NonTerminalRHSRuleSymbol); isNonTerminal && !isOptional(nonTerminal) {
+			if symbolHeight, exists := s.heights[nonTerminal.name]; exists && symbolHeight > height {
+				height = symbolHeight
+			}
+		}
+	}
+	if height == math.MaxInt32 {
+		return height
+	}
+	return height + 1
+}
+
+// Determines whether the sampler has run out of budget at the given depth,
+// in which case the shallowest derivations are chosen.
+func (s *Sampler) exhausted(depth int) bool {
+	return depth >= s.options.MaxDepth || len(s.tokens) >= s.options.MaxSize
+}
+
+// Deals with appending a random derivation of the named production to the sample.
+func (s *Sampler) expandProduction(name string, depth int) error {
+	prod := s.productions[name]
+	candidates := []int{}
+	least := math.MaxInt32
+	for i, rule := range prod.RHS {
+		height := s.ruleHeight(rule)
+		if s.exhausted(depth) && height < least {
+			least = height
+			candidates = []int{}
+		}
+		if height < math.MaxInt32 && (!s.exhausted(depth) || height == least) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("%w: <%v> never derives a sequence of terminals", ErrNonProductiveProduction, name)
+	}
+	return s.expandRule(prod.RHS[candidates[s.random.Intn(len(candidates))]], depth+1)
+}
+
+// Deals with appending a random derivation of the symbols of a rule to the sample.
+func (s *Sampler) expandRule(rule []RHSRuleSymbol, depth int) error {
+	noLineTerminator := false
+	for i, symbol := range rule {
+		switch sym := symbol.(type) {
+		case *NonTerminalRHSRuleSymbol:
+			_, exists := s.productions[sym.name]
+			if isOptional(sym) && (s.exhausted(depth) || s.random.Intn(4) == 0) {
+				continue
+			}
+			if exists {
+				if err := s.expandProduction(sym.name, depth); err != nil {
+					return err
+				}
+			} else if _, isTerminal := s.options.Terminals[sym.name]; isTerminal {
+				// Non-terminals without a production which have sample text
+				// are non-terminals of the lexical grammar.
+				s.appendTerminal(sym.name, noLineTerminator)
+			} else {
+				return fmt.Errorf("%w: <%v>", ErrUndefinedNonTerminal, sym.name)
+			}
+		case *TerminalRHSRuleSymbol:
+			s.appendTerminal(sym.name, noLineTerminator)
+		case *ConditionalRHSRuleSymbol:
+			if err := s.expandRule(sym.Parts, depth); err != nil {
+				return err
+			}
+		case *ExcludeRHSRuleSymbol:
+			noLineTerminator = sym.name == lineTerminator
+			continue
+		case *LookaheadRHSRuleSymbol:
+			return s.expandRestricted(rule[i+1:], LookaheadTerminals(sym.params), depth)
+		}
+		noLineTerminator = false
+	}
+	return nil
+}
+
+// Deals with appending a random derivation of the rest of a rule which follows
+// a lookahead restriction, derivations that start with one of the excluded
+// sequences of terminals are thrown away and generated again.
+func (s *Sampler) expandRestricted(rest []RHSRuleSymbol, exclusions [][]*PredicateTerminal, depth int) error {
+	position := len(s.tokens)
+	lookaheads := len(s.lookaheads)
+	for attempt := 0; attempt < sampleAttempts; attempt++ {
+		if err := s.expandRule(rest, depth); err != nil {
+			return err
+		}
+		if !s.isExcluded(position, exclusions) {
+			s.lookaheads = append(s.lookaheads, sampleLookahead{position, exclusions})
+			return nil
+		}
+		s.tokens = s.tokens[:position]
+		s.lookaheads = s.lookaheads[:lookaheads]
+	}
+	names := [][]string{}
+	for _, exclusion := range exclusions {
+		names = append(names, terminalNames(exclusion))
+	}
+	return fmt.Errorf("%w: %v", ErrSampleExhausted, names)
+}
+
+// Determines whether the terminals of the sample from the given position start with one
+// of the excluded sequences, a terminal restricted by [no LineTerminator here] is not
+// matched by a token the source text puts on a new line.
+func (s *Sampler) isExcluded(position int, exclusions [][]*PredicateTerminal) bool {
+	excluded := false
+	i := 0
+	for !excluded && i < len(exclusions) {
+		exclusion := exclusions[i]
+		if len(exclusion) > 0 && position+len(exclusion) <= len(s.tokens) {
+			excluded = true
+			for j, terminal := range exclusion {
+				excluded = excluded && s.tokens[position+j].terminal == terminal.Name &&
+					!(terminal.NoLineTerminator && s.lineTerminatorBefore(position+j))
+			}
+		}
+		i++
+	}
+	return excluded
+}
+
+// Deals with appending a terminal to the sample using
+// one of its sample texts chosen at random.
+func (s *Sampler) appendTerminal(terminal string, noLineTerminator bool) {
+	if terminal == Epsilon {
+		return
+	}
+	text := terminal
+	if texts := s.options.Terminals[terminal]; len(texts) > 0 {
+		text = texts[s.random.Intn(len(texts))]
+	}
+	s.tokens = append(s.tokens, sampleToken{terminal, text, noLineTerminator})
+}
+
+// Provides the source text of the sample, terminals are separated by spaces
+// and a new line follows semicolons and braces unless a line terminator is not allowed.
+func (s *Sampler) render() string {
+	var text strings.Builder
+	for i, token := range s.tokens {
+		if i > 0 {
+			if s.lineTerminatorBefore(i) {
+				text.WriteString("\n")
+			} else {
+				text.WriteString(" ")
+			}
+		}
+		text.WriteString(token.text)
+	}
+	return text.String()
+}
+
+// Determines whether the source text of the sample has a new line
+// before the token at the given index.
+func (s *Sampler) lineTerminatorBefore(index int) bool {
+	if index == 0 || s.tokens[index].noLineTerminator {
+		return false
+	}
+	previous := s.tokens[index-1].text
+	return previous == ";" || previous == "{" || previous == "}"
+}
+
+// Determines whether the given non-terminal is marked as optional.
+func isOptional(symbol *NonTerminalRHSRuleSymbol) bool {
+	return symbol.params != nil && symbol.params.Optional != nil && *symbol.params.Optional
+}
@@ -0,0 +1,144 @@
+package grammar
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSampleIsDeterministic(t *testing.T) {
+	grammar, err := LoadFile("../parser/grammar.yml", "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seed := int64(0); seed < 20; seed++ {
+		options := &SampleOptions{Seed: seed}
+		first, err := Sample(grammar, "Script", options)
+		if err != nil {
+			t.Fatalf("Expected seed %v to generate a sample but got %v", seed, err)
+		}
+		second, _ := Sample(grammar, "Script", options)
+		if first != second {
+			t.Errorf("Expected seed %v to generate the same sample twice but got \n%v\nand\n%v", seed, first, second)
+		}
+	}
+}
+
+func TestSampleRespectsConditions(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n"+
+		"  rhs:\n"+
+		"    -\n"+
+		"      - <A>:\n"+
+		"          params:\n"+
+		"            passthrough: [+In]\n"+
+		"<A>:\n"+
+		"  params: [In]\n"+
+		"  rhs:\n"+
+		"    -\n"+
+		"      - in:\n"+
+		"          params:\n"+
+		"            conditions: [+In]\n"+
+		"    -\n"+
+		"      - out:\n"+
+		"          params:\n"+
+		"            conditions: [~In]\n")
+	sampler := NewSampler(grammar, &SampleOptions{Seed: 1})
+	for i := 0; i < 10; i++ {
+		if sample, err := sampler.Sample("S"); err != nil || sample != "in" {
+			t.Errorf("Expected only the alternative whose condition holds but got %v, %v", sample, err)
+		}
+	}
+}
+
+func TestSampleRespectsLookaheads(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n"+
+		"  rhs:\n"+
+		"    -\n"+
+		"      - <*Lookahead*>:\n"+
+		"          params:\n"+
+		"            exclude:\n"+
+		"              - ['{']\n"+
+		"              - [let, '[']\n"+
+		"      - <A>\n"+
+		"      - ;\n"+
+		"<A>:\n"+
+		"  rhs:\n"+
+		"    - ['{', '}']\n"+
+		"    - [let, '[', ']']\n"+
+		"    - [let, x]\n")
+	sampler := NewSampler(grammar, &SampleOptions{Seed: 3})
+	for i := 0; i < 10; i++ {
+		if sample, err := sampler.Sample("S"); err != nil || sample != "let x ;" {
+			t.Errorf("Expected excluded sequences not to be generated but got %v, %v", sample, err)
+		}
+	}
+}
+
+func TestSampleRespectsLineTerminatorRestrictions(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n"+
+		"  rhs:\n"+
+		"    - [a, ;, <!LineTerminator!>, b, ;, c]\n")
+	sample, err := Sample(grammar, "S", &SampleOptions{})
+	if err != nil || sample != "a ; b ;\nc" {
+		t.Errorf("Expected no line terminator before b but got %q, %v", sample, err)
+	}
+}
+
+func TestSampleLookaheadLineTerminatorRestrictions(t *testing.T) {
+	// The excluded sequence only applies when b is on the same line as the ;
+	// before it, which it never is in the source text of samples.
+	grammar := loadTestGrammar(t, "<S>:\n"+
+		"  rhs:\n"+
+		"    -\n"+
+		"      - <*Lookahead*>:\n"+
+		"          params:\n"+
+		"            exclude:\n"+
+		"              - [;, <!LineTerminator!>, b]\n"+
+		"      - ;\n"+
+		"      - b\n")
+	sample, err := Sample(grammar, "S", &SampleOptions{})
+	if err != nil || sample != ";\nb" {
+		t.Errorf("Expected b on a new line to satisfy the restriction but got %q, %v", sample, err)
+	}
+}
+
+func TestSampleRespectsBudget(t *testing.T) {
+	// Without a budget the list would grow without bound.
+	grammar := loadTestGrammar(t, "<S>:\n"+
+		"  rhs:\n"+
+		"    - [<S>, <S>]\n"+
+		"    - [x]\n")
+	for seed := int64(0); seed < 10; seed++ {
+		sample, err := Sample(grammar, "S", &SampleOptions{Seed: seed, MaxDepth: 5, MaxSize: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size := len(strings.Fields(sample)); size > 32 {
+			t.Errorf("Expected the depth budget to bound the sample but got %v terminals", size)
+		}
+	}
+}
+
+func TestSampleUnknownStartSymbol(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n  rhs:\n    - [x]\n")
+	_, err := Sample(grammar, "T", &SampleOptions{})
+	if !errors.Is(err, ErrUnknownStartSymbol) {
+		t.Errorf("Expected an unknown start symbol error but got %v", err)
+	}
+}
+
+func TestSampleUndefinedNonTerminal(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n  rhs:\n    - [x, <Missing>]\n")
+	_, err := Sample(grammar, "S", &SampleOptions{})
+	if !errors.Is(err, ErrUndefinedNonTerminal) {
+		t.Errorf("Expected an undefined non-terminal error but got %v", err)
+	}
+	grammar = loadTestGrammar(t, "<S>:\n  rhs:\n    - [x, <IdentifierName>]\n")
+	sample, err := Sample(grammar, "S", nil)
+	if err != nil {
+		t.Fatalf("Expected a lexical non-terminal with sample text to be sampled but got %v", err)
+	}
+	if !strings.HasPrefix(sample, "x ") {
+		t.Errorf("Expected the sample to start with x but got %v", sample)
+	}
+}
@@ -0,0 +1,171 @@
+package grammar
+
+const (
+	// InputStage provides the name of the stage of the grammar
+	// statistics which describes the grammar as it was loaded.
+	InputStage = "input"
+	// DefaultStatsLookahead provides the default maximum lookahead
+	// tried when working out the lookahead a grammar needs, computing
+	// FIRST_k and FOLLOW_k sets beyond k = 1 for the full ECMAScript
+	// grammar is too slow to be done on every build.
+	DefaultStatsLookahead = 1
+)
+
+// StatsOptions provides the options used to gather
+// statistics for a grammar.
+type StatsOptions struct {
+	// MaxLookahead provides the largest k tried when working out the lookahead
+	// needed to choose between the alternatives of every production.
+	// Defaults to DefaultStatsLookahead.
+	MaxLookahead int
+	// Starts provides the start symbols of the grammar.
+	// Defaults to the start symbols provided by StartSymbols.
+	Starts []string
+}
+
+// Stats provides the size of a grammar at a stage of its transformation,
+// used to keep track of how much the grammar and therefore its parse table grows.
+type Stats struct {
+	// Stage holds the name of the transformation applied last
+	// or InputStage for the grammar as it was loaded.
+	Stage string `json:"stage"`
+	// Productions holds the number of productions.
+	Productions int `json:"productions"`
+	// Alternatives holds the number of right-hand side rules of all productions.
+	Alternatives int `json:"alternatives"`
+	// Symbols holds the number of terminals and non-terminals which
+	// appear in all right-hand side rules, the empty string is not counted.
+	Symbols int `json:"symbols"`
+	// Terminals holds the number of distinct terminals including non-terminals
+	// without a production which are left to the lexer.
+	Terminals int `json:"terminals"`
+	// ParameterisedProductions holds the number of productions which declare parameters.
+	ParameterisedProductions int `json:"parameterisedProductions"`
+	// ParamCombinations holds the number of combinations of parameters the
+	// parameterised productions can be instantiated with.
+	ParamCombinations int `json:"paramCombinations"`
+	// Instantiations holds the number of productions each parameterised production
+	// was instantiated as, only provided once parameters have been expanded.
+	Instantiations map[string]int `json:"instantiations,omitempty"`
+	// Lookahead holds the smallest k up to the maximum lookahead which lets
+	// every production choose between its alternatives, one more than the maximum
+	// when that is not enough. Only provided once parameters and optional symbols
+	// have been expanded as the analysis does not take them into account.
+	Lookahead int `json:"lookahead,omitempty"`
+	// Conflicts holds the number of LL conflicts which remain with the lookahead.
+	Conflicts int `json:"conflicts"`
+}
+
+// GrammarStats deals with gathering the statistics of the grammar as it was
+// loaded and after each transformation made to it before its parse table is built.
+// The grammar is transformed in place the same way as Transform.
+// Nil options are treated as the default options.
+func GrammarStats(grammar *Grammar, options *StatsOptions) []*Stats {
+	if options == nil {
+		options = &StatsOptions{}
+	}
+	stats := []*Stats{StatsOf(grammar, InputStage)}
+	parameterised := map[string]bool{}
+	for _, prod := range grammar.Productions {
+		if len(prod.Params) > 0 {
+			parameterised[prod.Name] = true
+		}
+	}
+	stages := []struct {
+		name      string
+		transform func(*Grammar)
+		analyse   bool
+	}{
+		{ExpandParametersTransform, func(g *Grammar) { ExpandParameters(g, options.Starts...) }, false},
+		{ExpandOptionalsTransform, ExpandOptionals, true},
+		{EliminateLeftRecursionTransform, EliminateLeftRecursion, true},
+		{LeftFactorTransform, LeftFactor, true},
+	}
+	for _, stage := range stages {
+		stage.transform(grammar)
+		stageStats := StatsOf(grammar, stage.name)
+		if stage.name == ExpandParametersTransform {
+			stageStats.Instantiations = instantiations(grammar, parameterised)
+		}
+		if stage.analyse {
+			stageStats.Lookahead, stageStats.Conflicts = NeededLookahead(grammar, options)
+		}
+		stats = append(stats, stageStats)
+	}
+	return stats
+}
+
+// StatsOf provides the size of a grammar at the named stage of its transformation,
+// the lookahead is left to NeededLookahead as it is expensive to work out.
+func StatsOf(grammar *Grammar, stage string) *Stats {
+	stats := &Stats{Stage: stage}
+	nonTerminals := map[string]bool{}
+	for _, prod := range grammar.Productions {
+		nonTerminals[prod.Name] = true
+	}
+	terminals := map[string]bool{}
+	for _, prod := range grammar.Productions {
+		stats.Productions++
+		stats.Alternatives += len(prod.RHS)
+		if len(prod.Params) > 0 {
+			stats.ParameterisedProductions++
+			stats.ParamCombinations += 1 << uint(len(prod.Params))
+		}
+		for _, rule := range prod.RHS {
+			for _, symbol := range flattenRule(rule) {
+				switch s := symbol.(type) {
+				case *TerminalRHSRuleSymbol:
+					if s.name != Epsilon {
+						stats.Symbols++
+						terminals[s.name] = true
+					}
+				case *NonTerminalRHSRuleSymbol:
+					stats.Symbols++
+					if !nonTerminals[s.name] {
+						terminals[s.name] = true
+					}
+				}
+			}
+		}
+	}
+	stats.Terminals = len(terminals)
+	return stats
+}
+
+// Provides the number of productions each of the given parameterised
+// productions was instantiated as once parameters have been expanded.
+func instantiations(grammar *Grammar, parameterised map[string]bool) map[string]int {
+	counts := map[string]int{}
+	for _, prod := range grammar.Productions {
+		if parameterised[prod.OriginName()] {
+			counts[prod.OriginName()]++
+		}
+	}
+	return counts
+}
+
+// NeededLookahead provides the smallest lookahead up to the maximum which leaves
+// no LL conflicts in a grammar along with the number of conflicts which remain,
+// the lookahead is one more than the maximum when conflicts remain.
+// The grammar is expected to have had its parameters and optional symbols expanded.
+func NeededLookahead(grammar *Grammar, options *StatsOptions) (int, int) {
+	if options == nil {
+		options = &StatsOptions{}
+	}
+	maxLookahead := options.MaxLookahead
+	if maxLookahead < 1 {
+		maxLookahead = DefaultStatsLookahead
+	}
+	starts := options.Starts
+	if len(starts) == 0 {
+		starts = StartSymbols(grammar)
+	}
+	conflicts := 0
+	for k := 1; k <= maxLookahead; k++ {
+		conflicts = len(DetectConflicts(grammar, Analyse(grammar, k, starts...)))
+		if conflicts == 0 {
+			return k, 0
+		}
+	}
+	return maxLookahead + 1, conflicts
+}
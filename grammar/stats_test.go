@@ -0,0 +1,81 @@
+package grammar
+
+import (
+	"reflect"
+	"testing"
+)
+
+const statsTestGrammar = "<S>:\n" +
+	"  rhs:\n" +
+	"    - [{<E>: {params: {passthrough: [+In]}}}, ;]\n" +
+	"    - [do, {<E>: {params: {optional: true}}}, ;]\n" +
+	"<E>:\n" +
+	"  params: [In]\n" +
+	"  rhs:\n" +
+	"    - [{<E>: {params: {passthrough: ['?In']}}}, '+', IdentifierName]\n" +
+	"    - [IdentifierName]\n" +
+	"    - [{<*Conditional*>: {params: {conditions: [+In]}, parts: [in, IdentifierName]}}]\n"
+
+func TestGrammarStats(t *testing.T) {
+	grammar := loadTestGrammar(t, statsTestGrammar)
+	stats := GrammarStats(grammar, nil)
+	expected := []*Stats{
+		{
+			Stage:                    InputStage,
+			Productions:              2,
+			Alternatives:             5,
+			Symbols:                  11,
+			Terminals:                5,
+			ParameterisedProductions: 1,
+			ParamCombinations:        2,
+		},
+		{
+			Stage:          ExpandParametersTransform,
+			Productions:    3,
+			Alternatives:   7,
+			Symbols:        15,
+			Terminals:      5,
+			Instantiations: map[string]int{"E": 2},
+		},
+		{
+			Stage:        ExpandOptionalsTransform,
+			Productions:  3,
+			Alternatives: 8,
+			Symbols:      17,
+			Terminals:    5,
+			Lookahead:    2,
+			Conflicts:    4,
+		},
+	}
+	if len(stats) != 5 {
+		t.Fatalf("expected stats for 5 stages, got %v", len(stats))
+	}
+	for i, stage := range expected {
+		if !reflect.DeepEqual(stats[i], stage) {
+			t.Errorf("expected stats %+v, got %+v", stage, stats[i])
+		}
+	}
+	for _, stage := range stats[3:] {
+		if stage.Terminals != 5 {
+			t.Errorf("expected the %v stage to have 5 terminals, got %v", stage.Stage, stage.Terminals)
+		}
+	}
+	if stats[4].Stage != LeftFactorTransform || stats[4].Lookahead != 1 || stats[4].Conflicts != 0 {
+		t.Errorf("expected the left factored grammar to need a lookahead of 1 without conflicts, got %+v", stats[4])
+	}
+}
+
+func TestNeededLookahead(t *testing.T) {
+	grammar := loadTestGrammar(t, "<S>:\n"+
+		"  rhs:\n"+
+		"    - [a, b]\n"+
+		"    - [a, c]\n")
+	lookahead, conflicts := NeededLookahead(grammar, &StatsOptions{MaxLookahead: 2})
+	if lookahead != 2 || conflicts != 0 {
+		t.Errorf("expected a lookahead of 2 without conflicts, got %v with %v conflicts", lookahead, conflicts)
+	}
+	lookahead, conflicts = NeededLookahead(grammar, nil)
+	if lookahead != 2 || conflicts != 1 {
+		t.Errorf("expected a lookahead beyond the default with 1 conflict, got %v with %v conflicts", lookahead, conflicts)
+	}
+}
@@ -0,0 +1,115 @@
+package grammar
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrDuplicateProduction provides the error for the case when a supplemental
+	// grammar defines a production which is already defined by the grammar it supplements.
+	ErrDuplicateProduction = errors.New("duplicate production")
+	// ErrUnknownCover provides the error for the case when a production
+	// refines a cover production which is not defined.
+	ErrUnknownCover = errors.New("unknown cover production")
+)
+
+// LoadSupplementalFile deals with loading the supplemental grammar in the given file,
+// which is stored in the provided format, into the grammar it supplements.
+func LoadSupplementalFile(grammar *Grammar, supplementalFile string, format string) error {
+	supplemental, err := LoadFile(supplementalFile, format)
+	if err != nil {
+		return err
+	}
+	return Supplement(grammar, supplemental)
+}
+
+// Supplement deals with adding the productions of a supplemental grammar, such as the
+// productions that refine the cover grammars of the ECMAScript syntactic grammar,
+// to the grammar they supplement. The productions of the supplemental grammar can refer
+// to the productions of the grammar and each cover production they refine must be defined.
+func Supplement(grammar *Grammar, supplemental *Grammar) error {
+	productions := map[string]bool{}
+	for _, prod := range grammar.Productions {
+		productions[prod.Name] = true
+	}
+	for _, prod := range supplemental.Productions {
+		if productions[prod.Name] {
+			return supplementalError(prod, fmt.Errorf("%w: <%v> is already defined", ErrDuplicateProduction, prod.Name))
+		}
+		productions[prod.Name] = true
+	}
+	for _, prod := range supplemental.Productions {
+		for _, cover := range prod.Refines {
+			if !productions[cover] {
+				return supplementalError(prod, fmt.Errorf("%w: <%v> refines <%v>", ErrUnknownCover, prod.Name, cover))
+			}
+		}
+	}
+	grammar.Productions = append(grammar.Productions, supplemental.Productions...)
+	return nil
+}
+
+// ValidateSupplemental deals with checking the productions of a supplemental grammar
+// in the context of the grammar they supplement, only the problems found in the
+// productions of the supplemental grammar are reported, located at the production.
+func ValidateSupplemental(grammar *Grammar, supplemental *Grammar) []*BuildError {
+	combined := &Grammar{Productions: append([]*Production{}, grammar.Productions...)}
+	if err := Supplement(combined, supplemental); err != nil {
+		var buildErr *BuildError
+		errors.As(err, &buildErr)
+		return []*BuildError{buildErr}
+	}
+	supplementalProductions := map[string]*Production{}
+	for _, prod := range supplemental.Productions {
+		supplementalProductions[prod.Name] = prod
+	}
+	problems := []*BuildError{}
+	for _, problem := range Validate(combined, StartSymbols(grammar)...) {
+		if prod, exists := supplementalProductions[problem.Production]; exists {
+			if prod.Provenance != nil {
+				problem.File = prod.Provenance.File
+				problem.Line = prod.Provenance.Line
+			}
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+// Provides the error for a production of a supplemental grammar
+// located at where the production was loaded from.
+func supplementalError(prod *Production, err error) error {
+	buildErr := &BuildError{Production: prod.Name, Err: err}
+	if prod.Provenance != nil {
+		buildErr.File = prod.Provenance.File
+		buildErr.Line = prod.Provenance.Line
+	}
+	return buildErr
+}
+
+// GoalSymbols provides the start symbols of the grammar followed by the productions
+// which refine a cover production, as the tokens matched by a cover are parsed
+// again with the refining production as the goal symbol.
+func GoalSymbols(grammar *Grammar) []string {
+	goals := StartSymbols(grammar)
+	for _, prod := range grammar.Productions {
+		if len(prod.Refines) > 0 && !contains(goals, prod.Name) {
+			goals = append(goals, prod.Name)
+		}
+	}
+	return goals
+}
+
+// Refinements provides the productions which refine each cover production of the grammar.
+func Refinements(grammar *Grammar) map[string][]string {
+	refinements := map[string][]string{}
+	for _, prod := range grammar.Productions {
+		for _, cover := range prod.Refines {
+			if !contains(refinements[cover], prod.Name) {
+				refinements[cover] = append(refinements[cover], prod.Name)
+			}
+		}
+	}
+	return refinements
+}
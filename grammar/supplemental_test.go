@@ -0,0 +1,158 @@
+package grammar
+
+import (
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const coverTestGrammar = "<Script>:\n" +
+	"  rhs:\n" +
+	"    - [yield, {<Primary>: {params: {passthrough: [+Yield]}}}]\n" +
+	"    - [{<Primary>: {params: {passthrough: [~Yield]}}}]\n" +
+	"<Primary>:\n" +
+	"  params: [Yield]\n" +
+	"  rhs:\n" +
+	"    - [{<Cover>: {params: {passthrough: ['?Yield']}}}]\n" +
+	"<Cover>:\n" +
+	"  params: [Yield]\n" +
+	"  rhs:\n" +
+	"    - ['(', IdentifierName, ')']\n"
+
+const coverTestSupplemental = "<Parenthesized>:\n" +
+	"  params: [Yield]\n" +
+	"  refines: [<Cover>]\n" +
+	"  rhs:\n" +
+	"    - ['(', {<Expression>: {params: {passthrough: ['?Yield']}}}, ')']\n" +
+	"<Expression>:\n" +
+	"  params: [Yield]\n" +
+	"  rhs:\n" +
+	"    - [IdentifierName]\n" +
+	"    - [{<*Conditional*>: {params: {conditions: [+Yield]}, parts: [yield]}}]\n" +
+	"<Head>:\n" +
+	"  refines: [<Cover>]\n" +
+	"  rhs:\n" +
+	"    - ['(', ')']\n"
+
+func loadCoverTestGrammar(t *testing.T) *Grammar {
+	grammar := loadTestGrammar(t, coverTestGrammar)
+	supplemental, err := LoadBytes([]byte(coverTestSupplemental), "yaml", "supplemental.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Supplement(grammar, supplemental); err != nil {
+		t.Fatal(err)
+	}
+	return grammar
+}
+
+func TestSupplement(t *testing.T) {
+	grammar := loadCoverTestGrammar(t)
+	if len(grammar.Productions) != 6 {
+		t.Fatalf("Expected the supplemental productions to be added but got %v productions", len(grammar.Productions))
+	}
+	if problems := Validate(grammar); len(problems) != 0 {
+		t.Errorf("Expected the refining productions to be reachable but got %v", problems)
+	}
+	for _, test := range []struct {
+		supplemental string
+		err          error
+		line         int
+	}{
+		{"<Head>:\n  rhs:\n    - ['(', ')']\n<Cover>:\n  rhs:\n    - ['(', ')']\n", ErrDuplicateProduction, 4},
+		{"<Head>:\n  refines: [<Unknown>]\n  rhs:\n    - ['(', ')']\n", ErrUnknownCover, 1},
+	} {
+		supplemental, err := LoadBytes([]byte(test.supplemental), "yaml", "supplemental.yml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = Supplement(loadTestGrammar(t, coverTestGrammar), supplemental)
+		var buildErr *BuildError
+		if !errors.Is(err, test.err) || !errors.As(err, &buildErr) || buildErr.Line != test.line ||
+			buildErr.File != "supplemental.yml" {
+			t.Errorf("Expected %v at supplemental.yml:%v but got %v", test.err, test.line, err)
+		}
+	}
+}
+
+func TestValidateSupplemental(t *testing.T) {
+	supplemental, err := LoadBytes(
+		[]byte("<Head>:\n  refines: [<Cover>]\n  rhs:\n    - ['(', <Parameters>, ')']\n"), "yaml", "supplemental.yml",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems := ValidateSupplemental(loadTestGrammar(t, coverTestGrammar), supplemental)
+	if len(problems) != 1 || !errors.Is(problems[0], ErrUndefinedNonTerminal) ||
+		problems[0].File != "supplemental.yml" || problems[0].Line != 1 {
+		t.Errorf("Expected the undefined <Parameters> to be reported at supplemental.yml:1 but got %v", problems)
+	}
+}
+
+func TestExpandParametersRefines(t *testing.T) {
+	grammar := loadCoverTestGrammar(t)
+	ExpandParameters(grammar)
+	refines := map[string][]string{}
+	for _, prod := range grammar.Productions {
+		if len(prod.Refines) > 0 {
+			refines[prod.Name] = prod.Refines
+		}
+	}
+	expected := map[string][]string{
+		"Parenthesized":       {"Cover"},
+		"Parenthesized_Yield": {"Cover_Yield"},
+		"Head":                {"Cover_Yield", "Cover"},
+	}
+	if !reflect.DeepEqual(refines, expected) {
+		t.Errorf("Expected the refining productions %v but got %v", expected, refines)
+	}
+	if goals := GoalSymbols(grammar); !reflect.DeepEqual(goals, []string{"Script", "Parenthesized", "Parenthesized_Yield", "Head"}) {
+		t.Errorf("Expected the refining productions to be goal symbols but got %v", goals)
+	}
+	refinements := Refinements(grammar)
+	if len(refinements["Cover"]) != 2 || len(refinements["Cover_Yield"]) != 2 {
+		t.Errorf("Expected both covers to be refined twice but got %v", refinements)
+	}
+	analysis := Analyse(grammar, 1)
+	if conflicts := DetectConflicts(grammar, analysis); len(conflicts) != 0 {
+		t.Fatalf("Expected no conflicts but got %v", conflicts)
+	}
+	table, err := BuildParseTable(grammar, analysis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(table.Refinements, refinements) {
+		t.Errorf("Expected the parse table refinements %v but got %v", refinements, table.Refinements)
+	}
+	if _, exists := table.Entries["Parenthesized_Yield"]; !exists {
+		t.Errorf("Expected the refining productions to have parse table entries")
+	}
+	output, err := generateGrammarOutput(table, "parser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), "Refinements: map[Symbol][]Symbol{") {
+		t.Errorf("Expected the generated output to contain the refinements but got\n%s", output)
+	}
+}
+
+func TestSupplementalGrammar(t *testing.T) {
+	grammar, err := LoadFile("../parser/grammar.yml", "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile("../parser/supplemental-grammar.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	supplemental, err := LoadBytes(data, "yaml", "supplemental-grammar.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := ValidateSupplemental(grammar, supplemental); len(problems) != 0 {
+		t.Errorf("Expected the supplemental grammar to be valid but got %v", problems)
+	}
+	assertRoundTrip(t, supplemental)
+}
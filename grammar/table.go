@@ -0,0 +1,252 @@
+package grammar
+
+import "fmt"
+
+// DefaultStartSymbols provides the names of the goal symbols
+// of the ECMAScript syntactic grammar.
+var DefaultStartSymbols = []string{"Script", "Module"}
+
+// ParseTable provides the symbols, rules and LL(1) parse table
+// generated from a grammar.
+type ParseTable struct {
+	// NonTerminals holds the names of the non-terminal symbols
+	// in the order their productions appear in the grammar.
+	NonTerminals []string
+	// Terminals holds the names of the terminal symbols in the order
+	// they are first referenced, the end of input is always the last terminal.
+	Terminals []string
+	// Starts holds the names of the start symbols of the grammar.
+	Starts []string
+	// Rules holds every right-hand side rule of the grammar,
+	// entries in the table refer to rules by their index.
+	Rules []*TableRule
+	// Entries maps a non-terminal and a lookahead terminal
+	// to the index of the rule to expand.
+	Entries map[string]map[string]int
+	// Fallbacks maps a non-terminal and a lookahead terminal whose rule in the
+	// entries can be rejected by a lookahead restriction for the terminal to the
+	// other rules predicted for the terminal, in the order they are tried when
+	// the restriction rejects the upcoming terminals.
+	Fallbacks map[string]map[string][]int
+	// Refinements maps each cover production to the productions which
+	// refine it, which are start symbols of the table.
+	Refinements map[string][]string
+	// AST holds what is needed to build the abstract syntax tree from the parse tree,
+	// nil when the grammar has no AST annotations.
+	AST *ASTTable
+}
+
+// TableRule provides a single right-hand side rule of a production
+// reduced to the symbols that are pushed on to the parse stack.
+type TableRule struct {
+	Production  string
+	Alternative int
+	Symbols     []string
+	// Provenance holds where the rule came from in the source grammar.
+	Provenance *Provenance
+	// Predicates holds the lookahead restrictions of the rule.
+	Predicates []*LookaheadPredicate
+}
+
+// LookaheadPredicate provides a negative lookahead predicate compiled from a
+// lookahead restriction of a rule, the rest of the rule can only be parsed when
+// the upcoming terminals do not start with any of the excluded sequences.
+type LookaheadPredicate struct {
+	// Position holds the number of symbols of the rule which precede the restriction,
+	// a predicate at position 0 is checked when choosing the rule.
+	Position int
+	// Exclude holds the excluded sequences of terminals.
+	Exclude [][]*PredicateTerminal
+}
+
+// PredicateTerminal provides a terminal of a sequence excluded by a lookahead predicate.
+type PredicateTerminal struct {
+	Name string
+	// NoLineTerminator determines whether the terminal is only matched when no
+	// line terminator precedes it, as with async [no LineTerminator here] function.
+	NoLineTerminator bool
+}
+
+// BuildParseTable deals with building the LL(1) parse table
+// for the given grammar from the provided analysis.
+// Where more than one rule is predicted for the same non-terminal and
+// lookahead terminal, the rule that appears first takes precedence
+// unless a lookahead restriction at its start can reject the input
+// starting with the terminal, in which case the others are tried
+// in order as fallbacks when it does.
+// A non-terminal without a production is reported as undefined.
+func BuildParseTable(grammar *Grammar, analysis *Analysis) (*ParseTable, error) {
+	table := &ParseTable{
+		Starts:      analysis.Starts,
+		Entries:     map[string]map[string]int{},
+		Fallbacks:   map[string]map[string][]int{},
+		Refinements: Refinements(grammar),
+	}
+	nonTerminals := map[string]bool{}
+	// Holds every rule predicted for each non-terminal and lookahead terminal in order.
+	predicted := map[string]map[string][]int{}
+	for _, prod := range grammar.Productions {
+		if !nonTerminals[prod.Name] {
+			nonTerminals[prod.Name] = true
+			table.NonTerminals = append(table.NonTerminals, prod.Name)
+			table.Entries[prod.Name] = map[string]int{}
+			predicted[prod.Name] = map[string][]int{}
+		}
+	}
+	for _, prod := range grammar.Productions {
+		for i, rule := range prod.RHS {
+			tableRule := &TableRule{
+				Production:  prod.Name,
+				Alternative: i,
+				Symbols:     []string{},
+				Provenance:  prod.ProvenanceOf(i),
+			}
+			for _, symbol := range flattenRule(rule) {
+				switch s := symbol.(type) {
+				case *NonTerminalRHSRuleSymbol:
+					if !nonTerminals[s.name] {
+						return nil, &BuildError{
+							Production: prod.Name,
+							symbol:     "<" + s.name + ">",
+							Err:        fmt.Errorf("%w: <%v>", ErrUndefinedNonTerminal, s.name),
+						}
+					}
+					tableRule.Symbols = append(tableRule.Symbols, s.name)
+				case *TerminalRHSRuleSymbol:
+					if s.name != Epsilon {
+						tableRule.Symbols = append(tableRule.Symbols, s.name)
+						if !contains(table.Terminals, s.name) {
+							table.Terminals = append(table.Terminals, s.name)
+						}
+					}
+				case *LookaheadRHSRuleSymbol:
+					predicate := compilePredicate(s.params, len(tableRule.Symbols))
+					for _, exclusion := range predicate.Exclude {
+						for _, terminal := range exclusion {
+							if !contains(table.Terminals, terminal.Name) {
+								table.Terminals = append(table.Terminals, terminal.Name)
+							}
+						}
+					}
+					tableRule.Predicates = append(tableRule.Predicates, predicate)
+				}
+			}
+			ruleIndex := len(table.Rules)
+			table.Rules = append(table.Rules, tableRule)
+			for _, seq := range analysis.PredictOf(prod.Name, i).Sequences {
+				if len(seq) > 0 && !containsIndex(predicted[prod.Name][seq[0]], ruleIndex) {
+					predicted[prod.Name][seq[0]] = append(predicted[prod.Name][seq[0]], ruleIndex)
+				}
+			}
+		}
+	}
+	// Every other rule predicted is a fallback to begin with, as whether the rule in the entries
+	// can be rejected depends on the rules predicted for the non-terminal it starts with.
+	for _, name := range table.NonTerminals {
+		for terminal, ruleIndices := range predicted[name] {
+			table.Entries[name][terminal] = ruleIndices[0]
+			if len(ruleIndices) > 1 {
+				if _, exists := table.Fallbacks[name]; !exists {
+					table.Fallbacks[name] = map[string][]int{}
+				}
+				table.Fallbacks[name][terminal] = ruleIndices[1:]
+			}
+		}
+	}
+	for _, name := range table.NonTerminals {
+		for terminal := range predicted[name] {
+			if !canReject(table, table.Entries[name][terminal], terminal, map[string]bool{}) {
+				delete(table.Fallbacks[name], terminal)
+			}
+		}
+		if len(table.Fallbacks[name]) == 0 {
+			delete(table.Fallbacks, name)
+		}
+	}
+	table.Terminals = append(table.Terminals, EndOfInput)
+	return table, nil
+}
+
+// Provides the predicate compiled from the exclusions of a lookahead restriction
+// found after the given number of symbols of a rule, a [no LineTerminator here]
+// placeholder applies to the terminal which follows it.
+func compilePredicate(params *LaRHSParams, position int) *LookaheadPredicate {
+	predicate := &LookaheadPredicate{Position: position, Exclude: [][]*PredicateTerminal{}}
+	for _, sequence := range LookaheadTerminals(params) {
+		if len(sequence) > 0 {
+			predicate.Exclude = append(predicate.Exclude, sequence)
+		}
+	}
+	return predicate
+}
+
+// Determines whether the rule at the given index of the table can be rejected by a lookahead
+// restriction once it has been chosen for the given lookahead terminal, which is the case when
+// a restriction at its start excludes a sequence starting with the terminal or it starts with
+// a non-terminal whose rules predicted for the terminal can all be rejected.
+func canReject(table *ParseTable, ruleIndex int, terminal string, visited map[string]bool) bool {
+	rule := table.Rules[ruleIndex]
+	for _, predicate := range rule.Predicates {
+		if predicate.Position == 0 {
+			for _, exclusion := range predicate.Exclude {
+				if exclusion[0].Name == terminal {
+					return true
+				}
+			}
+		}
+	}
+	if len(rule.Symbols) == 0 || visited[rule.Symbols[0]] {
+		return false
+	}
+	entry, exists := table.Entries[rule.Symbols[0]][terminal]
+	if !exists {
+		return false
+	}
+	// Only the non-terminals being expanded are visited, which
+	// guards against left recursion that has not been eliminated.
+	visited[rule.Symbols[0]] = true
+	defer delete(visited, rule.Symbols[0])
+	candidates := append([]int{entry}, table.Fallbacks[rule.Symbols[0]][terminal]...)
+	rejected := true
+	i := 0
+	for rejected && i < len(candidates) {
+		rejected = canReject(table, candidates[i], terminal, visited)
+		i++
+	}
+	return rejected
+}
+
+// Determines whether the given index is in the list of indices.
+func containsIndex(haystack []int, needle int) bool {
+	found := false
+	i := 0
+	for !found && i < len(haystack) {
+		found = haystack[i] == needle
+		i++
+	}
+	return found
+}
+
+// StartSymbols provides the default start symbols which are present
+// in the given grammar, falling back to the first production.
+func StartSymbols(grammar *Grammar) []string {
+	starts := []string{}
+	for _, name := range DefaultStartSymbols {
+		found := false
+		i := 0
+		for !found && i < len(grammar.Productions) {
+			if grammar.Productions[i].Name == name {
+				found = true
+			} else {
+				i++
+			}
+		}
+		if found {
+			starts = append(starts, name)
+		}
+	}
+	if len(starts) == 0 && len(grammar.Productions) > 0 {
+		starts = append(starts, grammar.Productions[0].Name)
+	}
+	return starts
+}
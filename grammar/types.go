@@ -8,6 +8,27 @@ var (
 	// ErrInvalidRightHandSide provides the error for the case when a right-hand side
 	// set of rules is not of the expected form.
 	ErrInvalidRightHandSide = errors.New("invalid form for a set of right-hand side rules")
+	// ErrMalformedProduction provides the error for the case when a production
+	// is not a non-terminal mapped to its params and right-hand side rules.
+	ErrMalformedProduction = errors.New("malformed production")
+	// ErrMalformedSymbol provides the error for the case when a symbol
+	// of a right-hand side rule or its parameters are not of the expected form.
+	ErrMalformedSymbol = errors.New("malformed right-hand side symbol")
+	// ErrMalformedConditional provides the error for the case when
+	// a <*Conditional*> block is not of the expected form.
+	ErrMalformedConditional = errors.New("malformed <*Conditional*> block")
+	// ErrMalformedLookahead provides the error for the case when
+	// a <*Lookahead*> block is not of the expected form.
+	ErrMalformedLookahead = errors.New("malformed <*Lookahead*> block")
+)
+
+const (
+	// Epsilon provides the name of the terminal symbol
+	// which represents the empty string.
+	Epsilon = "[empty]"
+	// EndOfInput provides the name of the pseudo terminal symbol
+	// which marks the end of the input in FOLLOW sets.
+	EndOfInput = "[eoi]"
 )
 
 // RHSRuleSymbol provides the base definition for all right
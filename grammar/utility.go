@@ -2,10 +2,7 @@ package grammar
 
 import (
 	"fmt"
-	"regexp"
-	"strings"
-
-	yaml "gopkg.in/yaml.v2"
+	"math/bits"
 )
 
 // ExtractConditionalPartRules deals with extracting conditional
@@ -14,130 +11,58 @@ import (
 func ExtractConditionalPartRules(list []interface{}) ([]RHSRuleSymbol, error) {
 	var parts []RHSRuleSymbol
 	for _, symbol := range list {
-		symbolStr, symbolIsStr := symbol.(string)
-		if symbolIsStr {
-			matched, err := regexp.MatchString("^<(\\w|')+>$", symbolStr)
-			if err != nil {
-				return parts, err
-			}
-			if matched {
-				parts = append(parts, &NonTerminalRHSRuleSymbol{
-					name: strings.TrimSuffix(strings.TrimPrefix(symbolStr, "<"), ">"),
-				})
-			} else if symbolStr == "<!LineTerminator!>" {
-				parts = append(parts, &ExcludeRHSRuleSymbol{
-					name: "LineTerminator",
-				})
-			} else {
-				// If not a non-terminal for conditional parts then it
-				// is a terminal symbol.
-				parts = append(parts, &TerminalRHSRuleSymbol{
-					name: symbolStr,
-				})
-			}
-		} else {
-			rulePartMapSlice, rulePartIsMapSlice := symbol.(yaml.MapSlice)
-			if rulePartIsMapSlice {
-				for _, v := range rulePartMapSlice {
-					vMap, valueIsMapSlice := v.Value.(yaml.MapSlice)
-					if valueIsMapSlice {
-						name, isNameString := v.Key.(string)
-						if isNameString {
-							matched, err := regexp.MatchString("^<(\\w|')+>$", name)
-							if err != nil {
-								return parts, err
-							}
-							if matched {
-								paramIface := get(vMap, "params")
-								paramMap, isParamMapSlice := paramIface.(yaml.MapSlice)
-								if paramIface != nil && isParamMapSlice {
-									params := &NtRHSParams{}
-									conditions := get(paramMap, "conditions")
-									pt := get(paramMap, "passthrough")
-									optional := get(paramMap, "optional")
-									if conditions != nil {
-										condSlice := conditions.([]interface{})
-										for _, condParam := range condSlice {
-											condParamStr := condParam.(string)
-											params.Conditions = append(params.Conditions, condParamStr)
-										}
-									}
-									if pt != nil {
-										ptSlice := pt.([]interface{})
-										for _, ptParam := range ptSlice {
-											ptParamStr := ptParam.(string)
-											params.Passthrough = append(params.Passthrough, ptParamStr)
-										}
-									}
-									if optional != nil {
-										optionalBool := optional.(bool)
-										params.Optional = &optionalBool
-									}
-									parts = append(parts, &NonTerminalRHSRuleSymbol{
-										name:   strings.TrimSuffix(strings.TrimPrefix(name, "<"), ">"),
-										params: params,
-									})
-								}
-							} else {
-								// It is a terminal symbol with parameters.
-								paramIface := get(vMap, "params")
-								paramMap, isParamMapSlice := paramIface.(yaml.MapSlice)
-								if paramIface != nil && isParamMapSlice {
-									params := &TRHSParams{}
-									conditions := get(paramMap, "conditions")
-									if conditions != nil {
-										condSlice := conditions.([]interface{})
-										for _, condParam := range condSlice {
-											condParamStr := condParam.(string)
-											params.Conditions = append(params.Conditions, condParamStr)
-										}
-									}
-									parts = append(parts, &TerminalRHSRuleSymbol{
-										name:   name,
-										params: params,
-									})
-								}
-							}
-						}
-					}
-				}
-			}
+		part, err := extractSymbol(symbol, false)
+		if err != nil {
+			return parts, err
 		}
+		parts = append(parts, part)
 	}
 	return parts, nil
 }
 
 // ExtractLookaheadExclusions Deals with extracting
 // the rules utilised by lookahead exclusions.
-func ExtractLookaheadExclusions(list []interface{}) [][]RHSRuleSymbol {
+func ExtractLookaheadExclusions(list []interface{}) ([][]RHSRuleSymbol, error) {
 	var rules [][]RHSRuleSymbol
-	for _, rhs := range list {
+	for i, rhs := range list {
 		ruleParts := []RHSRuleSymbol{}
-		symbols := rhs.([]interface{})
+		symbols, isList := rhs.([]interface{})
+		if !isList || len(symbols) == 0 {
+			return rules, fmt.Errorf("%w: exclusion %v must be a non-empty list of symbols", ErrMalformedLookahead, i)
+		}
 		for _, s := range symbols {
-			symbolStr := s.(string)
-			if symbolStr == "<!LineTerminator!>" {
-				ruleParts = append(ruleParts, &ExcludeRHSRuleSymbol{
-					name: "LineTerminator",
-				})
-			} else {
-				ruleParts = append(ruleParts, &TerminalRHSRuleSymbol{
-					name: symbolStr,
-				})
+			symbolStr, isStr := s.(string)
+			if !isStr {
+				return rules, fmt.Errorf("%w: exclusion %v must only hold symbol names, found %v", ErrMalformedLookahead, i, s)
+			}
+			symbol, err := extractStringSymbol(symbolStr)
+			if err != nil {
+				return rules, fmt.Errorf("%w: exclusion %v: %v", ErrMalformedLookahead, i, err)
 			}
+			ruleParts = append(ruleParts, symbol)
 		}
 		rules = append(rules, ruleParts)
 	}
-	return rules
+	return rules, nil
 }
 
 // ExpandOptionals deals with expanding all right-hand side rule
-// sets with optional symbols to the multiple rules represented.
+// sets with optional symbols to the multiple rules represented,
+// one for each combination of the optional symbols being present.
+// Rules which would end up empty are replaced with a single epsilon rule
+// for the production, unless it already has one, and rules which are
+// already in the production are not added again.
 func ExpandOptionals(grammar *Grammar) {
 	for _, prod := range grammar.Productions {
 		newRules := [][]RHSRuleSymbol{}
-		epsilon := []RHSRuleSymbol{}
+		newProvenance := []*Provenance{}
+		existing := map[string]bool{}
 		for _, rule := range prod.RHS {
+			existing[sprintRule(rule)] = true
+		}
+		epsilon := []RHSRuleSymbol{}
+		var epsilonProvenance *Provenance
+		for i, rule := range prod.RHS {
 			optionalSymbolPositions := []int{}
 			for k, symbol := range rule {
 				params, isNtParams := symbol.Params().(*NtRHSParams)
@@ -149,35 +74,62 @@ func ExpandOptionals(grammar *Grammar) {
 				}
 			}
 			newRules = append(newRules, rule)
-			// Now for each optional symbol create a new rule
-			// without the given symbol.
-			for _, pos := range optionalSymbolPositions {
+			newProvenance = append(newProvenance, prod.ProvenanceOf(i))
+			// Now for each combination of optional symbols create
+			// a new rule without the symbols in the combination.
+			for _, removed := range optionalCombinations(optionalSymbolPositions) {
 				newRule := []RHSRuleSymbol{}
-				if pos+1 < len(rule) {
-					for k := range rule {
-						if k != pos {
-							newRule = append(newRule, rule[k])
-						}
+				for k := range rule {
+					if !removed[k] {
+						newRule = append(newRule, rule[k])
 					}
 				}
 				// For the empty rule, make it the epsilon rule for the current
 				// production if it doesn't already have the epsilon rule.
-				if len(newRule) == 0 && len(epsilon) == 0 {
-					epsilon = []RHSRuleSymbol{&TerminalRHSRuleSymbol{
-						name: "[empty]",
-					}}
-				} else if len(newRule) > 0 {
+				if len(newRule) == 0 {
+					if len(epsilon) == 0 && !existing["[empty] "] {
+						epsilon = []RHSRuleSymbol{&TerminalRHSRuleSymbol{
+							name: "[empty]",
+						}}
+						epsilonProvenance = deriveProvenance(prod.ProvenanceOf(i), ExpandOptionalsTransform)
+					}
+				} else if key := sprintRule(newRule); !existing[key] {
+					existing[key] = true
 					newRules = append(newRules, newRule)
+					newProvenance = append(newProvenance, deriveProvenance(prod.ProvenanceOf(i), ExpandOptionalsTransform))
 				}
 			}
 		}
 		if len(epsilon) > 0 {
 			newRules = append(newRules, epsilon)
+			newProvenance = append(newProvenance, epsilonProvenance)
 		}
 		prod.RHS = newRules
+		prod.RuleProvenance = newProvenance
 	}
 }
 
+// Provides every non-empty combination of the given positions of
+// optional symbols as sets, ordered by the number of positions in the
+// combination and then by the order of the positions.
+func optionalCombinations(positions []int) []map[int]bool {
+	combinations := []map[int]bool{}
+	for size := 1; size <= len(positions); size++ {
+		for mask := 1; mask < 1<<len(positions); mask++ {
+			if bits.OnesCount(uint(mask)) == size {
+				combination := map[int]bool{}
+				for i, pos := range positions {
+					if mask&(1<<i) != 0 {
+						combination[pos] = true
+					}
+				}
+				combinations = append(combinations, combination)
+			}
+		}
+	}
+	return combinations
+}
+
 // Prints the provided grammar to a string
 // for debugging purposes.
 func sprintGrammar(grammar Grammar) string {
@@ -199,61 +151,72 @@ func sprintProduction(prod Production) string {
 	}
 	params += "]"
 	output += prod.Name + params + ":\n"
-	for _, rule := range prod.RHS {
-		symbolNames := ""
-		for _, symbol := range rule {
-			symbolName := symbol.Name()
-			if len(symbol.Name()) == 0 {
-				symbolConditional, isCond := symbol.(*ConditionalRHSRuleSymbol)
-				if isCond {
-					symbolName = "["
-					for i, condition := range symbolConditional.params.Conditions {
-						if i == 0 {
-							symbolName += condition
-						} else {
-							symbolName += ", " + condition
-						}
+	for i, rule := range prod.RHS {
+		output += "    - " + sprintRule(rule)
+		// Rules created by a transformation cite the source rule they came from.
+		if provenance := prod.ProvenanceOf(i); provenance != nil && provenance.Transform != "" {
+			output += "   # from " + provenance.String()
+		}
+		output += "\n"
+	}
+	output += "\n"
+	return output
+}
+
+// Prints the symbols of the provided right-hand side rule
+// to a string separated by spaces.
+func sprintRule(rule []RHSRuleSymbol) string {
+	symbolNames := ""
+	for _, symbol := range rule {
+		symbolName := symbol.Name()
+		if len(symbol.Name()) == 0 {
+			symbolConditional, isCond := symbol.(*ConditionalRHSRuleSymbol)
+			if isCond {
+				symbolName = "["
+				for i, condition := range symbolConditional.params.Conditions {
+					if i == 0 {
+						symbolName += condition
+					} else {
+						symbolName += ", " + condition
 					}
-					symbolName += "]"
-					for _, part := range symbolConditional.Parts {
-						if _, isExclude := part.(*ExcludeRHSRuleSymbol); isExclude {
-							symbolName += " [no " + part.Name() + " here]"
-						} else {
-							symbolName += " " + part.Name()
-						}
+				}
+				symbolName += "]"
+				for _, part := range symbolConditional.Parts {
+					if _, isExclude := part.(*ExcludeRHSRuleSymbol); isExclude {
+						symbolName += " [no " + part.Name() + " here]"
+					} else {
+						symbolName += " " + part.Name()
 					}
-				} else {
-					symbolLookahead, isLa := symbol.(*LookaheadRHSRuleSymbol)
-					if isLa {
-						symbolName = "[lookahead ∉ 〈 "
-						for i, exclude := range symbolLookahead.params.Exclude {
-							if i > 0 {
-								symbolName += ", "
+				}
+			} else {
+				symbolLookahead, isLa := symbol.(*LookaheadRHSRuleSymbol)
+				if isLa {
+					symbolName = "[lookahead ∉ 〈 "
+					for i, exclude := range symbolLookahead.params.Exclude {
+						if i > 0 {
+							symbolName += ", "
+						}
+						for j, excludeRule := range exclude {
+							if j > 0 {
+								symbolName += " "
 							}
-							for j, excludeRule := range exclude {
-								if j > 0 {
-									symbolName += " "
-								}
-								if _, isExclude := excludeRule.(*ExcludeRHSRuleSymbol); isExclude {
-									symbolName += "[no " + excludeRule.Name() + " here]"
-								} else {
-									symbolName += excludeRule.Name()
-								}
+							if _, isExclude := excludeRule.(*ExcludeRHSRuleSymbol); isExclude {
+								symbolName += "[no " + excludeRule.Name() + " here]"
+							} else {
+								symbolName += excludeRule.Name()
 							}
 						}
-						symbolName += " 〉]"
 					}
+					symbolName += " 〉]"
 				}
 			}
-			if _, isExclude := symbol.(*ExcludeRHSRuleSymbol); isExclude {
-				symbolName = "[no " + symbol.Name() + " here]"
-			}
-			symbolNames += symbolName + " "
 		}
-		output += "    - " + symbolNames + "\n"
+		if _, isExclude := symbol.(*ExcludeRHSRuleSymbol); isExclude {
+			symbolName = "[no " + symbol.Name() + " here]"
+		}
+		symbolNames += symbolName + " "
 	}
-	output += "\n"
-	return output
+	return symbolNames
 }
 
 func printProduction(prod Production) {
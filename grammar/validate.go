@@ -0,0 +1,306 @@
+package grammar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrUndefinedNonTerminal provides the error for the case when a right-hand
+	// side rule refers to a non-terminal which has no production.
+	ErrUndefinedNonTerminal = errors.New("undefined non-terminal")
+	// ErrUnusedProduction provides the error for the case when a production
+	// can not be reached from any of the start symbols.
+	ErrUnusedProduction = errors.New("unused production")
+	// ErrNonProductiveProduction provides the error for the case when
+	// a production can never derive a sequence made up of only terminals.
+	ErrNonProductiveProduction = errors.New("non-productive production")
+	// ErrUnknownParameter provides the error for the case when a condition or
+	// passthrough argument refers to a parameter that has not been declared.
+	ErrUnknownParameter = errors.New("unknown parameter")
+)
+
+// ValidateFile deals with validating the grammar in the given file
+// which is stored in the provided format.
+func ValidateFile(grammarFile string, format string) []*BuildError {
+	data, err := ioutil.ReadFile(grammarFile)
+	if err != nil {
+		return []*BuildError{{File: grammarFile, Err: err}}
+	}
+	return ValidateBytes(data, format, grammarFile)
+}
+
+// ValidateBytes deals with validating a grammar stored in the provided format
+// in the given data, the file name is only used to describe where problems occurred.
+// Every malformed production is reported along with the problems found by Validate
+// in the productions which are well formed, ordered by their position in the data.
+func ValidateBytes(data []byte, format string, fileName string) []*BuildError {
+	grammar, problems, err := decode(data, format)
+	if err != nil {
+		return []*BuildError{locateError(err, data, fileName)}
+	}
+	malformed := map[string]bool{}
+	for _, problem := range problems {
+		malformed["<"+problem.Production+">"] = true
+	}
+	for _, problem := range Validate(grammar) {
+		// Malformed productions have already been reported so references
+		// to them are not reported as undefined as well.
+		if !errors.Is(problem, ErrUndefinedNonTerminal) || !malformed[problem.symbol] {
+			problems = append(problems, problem)
+		}
+	}
+	for _, problem := range problems {
+		locateProblem(problem, data, fileName)
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+	return problems
+}
+
+// Validate deals with checking a well formed grammar for undefined non-terminals,
+// conditions and passthrough arguments that refer to unknown parameters,
+// productions that are unreachable from the start symbols and productions
+// which can never derive a sequence of terminals.
+// When no start symbols are provided the default start symbols of the grammar are used.
+func Validate(grammar *Grammar, starts ...string) []*BuildError {
+	if len(starts) == 0 {
+		starts = StartSymbols(grammar)
+	}
+	productions := map[string]*Production{}
+	for _, prod := range grammar.Productions {
+		productions[prod.Name] = prod
+	}
+	problems := []*BuildError{}
+	for _, prod := range grammar.Productions {
+		for _, cover := range prod.Refines {
+			if _, exists := productions[cover]; !exists {
+				problems = append(problems, &BuildError{
+					Production: prod.Name,
+					Err:        fmt.Errorf("%w: <%v> refines <%v>", ErrUnknownCover, prod.Name, cover),
+				})
+			}
+		}
+		reported := map[string]bool{}
+		for _, rule := range prod.RHS {
+			for _, symbol := range symbolsOf(rule) {
+				problems = append(problems, validateSymbol(prod, symbol, productions, reported)...)
+			}
+		}
+	}
+	reachable := reachableProductions(starts, productions)
+	for _, prod := range grammar.Productions {
+		if !reachable[prod.Name] {
+			problems = append(problems, &BuildError{
+				Production: prod.Name,
+				Err:        fmt.Errorf("%w: <%v> is not reachable from %v", ErrUnusedProduction, prod.Name, strings.Join(starts, ", ")),
+			})
+		}
+	}
+	productive := productiveProductions(grammar, productions)
+	for _, prod := range grammar.Productions {
+		if !productive[prod.Name] {
+			problems = append(problems, &BuildError{
+				Production: prod.Name,
+				Err:        fmt.Errorf("%w: <%v> never derives a sequence of terminals", ErrNonProductiveProduction, prod.Name),
+			})
+		}
+	}
+	return problems
+}
+
+// Deals with checking a single symbol of a rule of the given production,
+// problems which have already been reported for the production are skipped.
+func validateSymbol(
+	prod *Production, symbol RHSRuleSymbol, productions map[string]*Production, reported map[string]bool,
+) []*BuildError {
+	problems := []*BuildError{}
+	report := func(symbolText string, err error) {
+		if !reported[err.Error()] {
+			reported[err.Error()] = true
+			problems = append(problems, &BuildError{Production: prod.Name, symbol: symbolText, Err: err})
+		}
+	}
+	conditions := []string{}
+	switch s := symbol.(type) {
+	case *NonTerminalRHSRuleSymbol:
+		symbolText := "<" + s.name + ">"
+		target, exists := productions[s.name]
+		if !exists {
+			report(symbolText, fmt.Errorf("%w: %v", ErrUndefinedNonTerminal, symbolText))
+		}
+		if s.params != nil {
+			conditions = s.params.Conditions
+			for _, arg := range s.params.Passthrough {
+				param := arg[1:]
+				if strings.HasPrefix(arg, "?") && !contains(prod.Params, param) {
+					report(symbolText, fmt.Errorf("%w: %v passes on %v which <%v> does not declare", ErrUnknownParameter, symbolText, param, prod.Name))
+				}
+				if exists && !contains(target.Params, param) {
+					report(symbolText, fmt.Errorf("%w: %v is passed to %v which does not declare it", ErrUnknownParameter, param, symbolText))
+				}
+			}
+		}
+	case *TerminalRHSRuleSymbol:
+		if s.params != nil {
+			conditions = s.params.Conditions
+		}
+	case *ConditionalRHSRuleSymbol:
+		if s.params != nil {
+			conditions = s.params.Conditions
+		}
+	}
+	for _, condition := range conditions {
+		if !contains(prod.Params, condition[1:]) {
+			report(condition, fmt.Errorf("%w: the condition %v refers to a parameter <%v> does not declare", ErrUnknownParameter, condition, prod.Name))
+		}
+	}
+	return problems
+}
+
+// Provides every symbol of a rule including the symbols that make up
+// conditional parts and the non-terminals referenced by lookahead exclusions.
+func symbolsOf(rule []RHSRuleSymbol) []RHSRuleSymbol {
+	symbols := []RHSRuleSymbol{}
+	for _, symbol := range rule {
+		symbols = append(symbols, symbol)
+		switch s := symbol.(type) {
+		case *ConditionalRHSRuleSymbol:
+			symbols = append(symbols, symbolsOf(s.Parts)...)
+		case *LookaheadRHSRuleSymbol:
+			if s.params != nil {
+				for _, exclusion := range s.params.Exclude {
+					symbols = append(symbols, symbolsOf(exclusion)...)
+				}
+			}
+		}
+	}
+	return symbols
+}
+
+// Provides the set of productions which can be reached from the given start symbols,
+// a production which refines a cover production is reached along with the cover.
+func reachableProductions(starts []string, productions map[string]*Production) map[string]bool {
+	reachable := map[string]bool{}
+	queue := []string{}
+	for _, start := range starts {
+		if _, exists := productions[start]; exists && !reachable[start] {
+			reachable[start] = true
+			queue = append(queue, start)
+		}
+	}
+	for len(queue) > 0 {
+		for len(queue) > 0 {
+			prod := productions[queue[0]]
+			queue = queue[1:]
+			for _, rule := range prod.RHS {
+				for _, symbol := range symbolsOf(rule) {
+					_, isNonTerminal := symbol.(*NonTerminalRHSRuleSymbol)
+					_, exists := productions[symbol.Name()]
+					if isNonTerminal && exists && !reachable[symbol.Name()] {
+						reachable[symbol.Name()] = true
+						queue = append(queue, symbol.Name())
+					}
+				}
+			}
+		}
+		for _, prod := range productions {
+			for _, cover := range prod.Refines {
+				if reachable[cover] && !reachable[prod.Name] {
+					reachable[prod.Name] = true
+					queue = append(queue, prod.Name)
+				}
+			}
+		}
+	}
+	return reachable
+}
+
+// Provides the set of productions which can derive a sequence of terminals,
+// non-terminals without a production are taken to be productive as they
+// are reported as undefined separately.
+func productiveProductions(grammar *Grammar, productions map[string]*Production) map[string]bool {
+	productive := map[string]bool{}
+	changed := true
+	for changed {
+		changed = false
+		for _, prod := range grammar.Productions {
+			if !productive[prod.Name] {
+				found := false
+				i := 0
+				for !found && i < len(prod.RHS) {
+					found = isProductiveRule(prod.RHS[i], productions, productive)
+					i++
+				}
+				if found {
+					productive[prod.Name] = true
+					changed = true
+				}
+			}
+		}
+	}
+	return productive
+}
+
+// Determines whether every symbol of the rule can derive a sequence of terminals.
+func isProductiveRule(rule []RHSRuleSymbol, productions map[string]*Production, productive map[string]bool) bool {
+	isProductive := true
+	i := 0
+	for isProductive && i < len(rule) {
+		switch s := rule[i].(type) {
+		case *NonTerminalRHSRuleSymbol:
+			_, exists := productions[s.name]
+			optional := s.params != nil && s.params.Optional != nil && *s.params.Optional
+			isProductive = !exists || optional || productive[s.name]
+		case *ConditionalRHSRuleSymbol:
+			isProductive = isProductiveRule(s.Parts, productions, productive)
+		}
+		i++
+	}
+	return isProductive
+}
+
+// Deals with filling in the location of a validation problem, problems with
+// a specific symbol are located at the first use of the symbol in the production.
+func locateProblem(problem *BuildError, data []byte, fileName string) {
+	problem.File = fileName
+	if problem.Line == 0 && problem.Production != "" {
+		problem.Line, problem.Column = ProductionPosition(data, problem.Production)
+		if problem.Line > 0 && problem.symbol != "" {
+			line, column := symbolPosition(data, problem.Line, problem.Column, problem.symbol)
+			if line == 0 {
+				// Non-terminals are not wrapped in angle brackets in the specification notation.
+				line, column = symbolPosition(data, problem.Line, problem.Column, strings.Trim(problem.symbol, "<>"))
+			}
+			if line > 0 {
+				problem.Line, problem.Column = line, column
+			}
+		}
+	}
+}
+
+// Provides the 1-based line and column of the first occurrence of the symbol text
+// in the production declared at the given position, or 0 for both when it is not found.
+func symbolPosition(data []byte, prodLine int, prodColumn int, symbolText string) (int, int) {
+	lines := bytes.Split(data, []byte("\n"))
+	line, column := 0, 0
+	i := prodLine
+	for line == 0 && i < len(lines) {
+		text := string(lines[i])
+		indent := len(text) - len(strings.TrimLeft(text, " \t-"))
+		if strings.TrimSpace(text) != "" && indent+1 <= prodColumn {
+			// The next production has been reached.
+			return 0, 0
+		}
+		if index := strings.Index(text, symbolText); index >= 0 {
+			line, column = i+1, index+1
+		}
+		i++
+	}
+	return line, column
+}
@@ -0,0 +1,124 @@
+package grammar
+
+import (
+	"errors"
+	"testing"
+)
+
+// Asserts the problems hold exactly one error of each of the expected
+// kinds at the given lines in the same order.
+func assertProblems(t *testing.T, problems []*BuildError, expected []error, lines []int) {
+	if len(problems) != len(expected) {
+		t.Fatalf("Expected %v problems but got %v: %v", len(expected), len(problems), problems)
+	}
+	for i, problem := range problems {
+		if !errors.Is(problem, expected[i]) || problem.Line != lines[i] {
+			t.Errorf("Expected %v at line %v but got %v", expected[i], lines[i], problem)
+		}
+	}
+}
+
+func TestValidateValidGrammar(t *testing.T) {
+	input := "<S>:\n" +
+		"  params: [In]\n" +
+		"  rhs:\n" +
+		"    - [a, <T>]\n" +
+		"    -\n" +
+		"      - <*Lookahead*>:\n" +
+		"          params:\n" +
+		"            exclude: [[b]]\n" +
+		"      - <*Conditional*>:\n" +
+		"          params:\n" +
+		"            conditions: [+In]\n" +
+		"          parts: [c]\n" +
+		"<T>:\n" +
+		"  rhs:\n" +
+		"    - [t]\n"
+	problems := ValidateBytes([]byte(input), "yaml", "valid.yml")
+	if len(problems) != 0 {
+		t.Errorf("Expected no problems but got %v", problems)
+	}
+}
+
+func TestValidateECMAScriptGrammar(t *testing.T) {
+	if problems := ValidateFile("../parser/grammar.yml", "yaml"); len(problems) != 0 {
+		t.Errorf("Expected no problems in the ECMAScript grammar but got %v", problems)
+	}
+}
+
+func TestValidateMalformedBlocks(t *testing.T) {
+	input := "<S>:\n" +
+		"  rhs:\n" +
+		"    - [<A>, <B>, <C>]\n" +
+		"<A>:\n" +
+		"  rhs:\n" +
+		"    -\n" +
+		"      - <*Lookahead*>:\n" +
+		"          params:\n" +
+		"            exlude: [[b]]\n" +
+		"      - a\n" +
+		"<B>:\n" +
+		"  rhs:\n" +
+		"    -\n" +
+		"      - <*Conditional*>:\n" +
+		"          parts: [b]\n" +
+		"<C>:\n" +
+		"  rhs:\n" +
+		"    -\n" +
+		"      - <T>:\n" +
+		"          params: [+In]\n"
+	problems := ValidateBytes([]byte(input), "yaml", "malformed.yml")
+	assertProblems(
+		t, problems,
+		[]error{ErrMalformedLookahead, ErrMalformedConditional, ErrMalformedSymbol},
+		[]int{4, 11, 16},
+	)
+	if problems[0].File != "malformed.yml" || problems[0].Production != "A" || problems[0].Column != 1 {
+		t.Errorf("Expected the malformed lookahead to be located at <A> but got %v", problems[0])
+	}
+}
+
+func TestValidateUndefinedNonTerminal(t *testing.T) {
+	input := "<S>:\n  rhs:\n    - [a]\n    - [b, <Missing>]\n"
+	problems := ValidateBytes([]byte(input), "yaml", "undefined.yml")
+	assertProblems(t, problems, []error{ErrUndefinedNonTerminal}, []int{4})
+	if problems[0].Column != 11 {
+		t.Errorf("Expected the undefined non-terminal to be located at column 11 but got %v", problems[0].Column)
+	}
+}
+
+func TestValidateUnusedProduction(t *testing.T) {
+	input := "<S>:\n  rhs:\n    - [a]\n<Unused>:\n  rhs:\n    - [b]\n"
+	problems := ValidateBytes([]byte(input), "yaml", "unused.yml")
+	assertProblems(t, problems, []error{ErrUnusedProduction}, []int{4})
+}
+
+func TestValidateNonProductiveProduction(t *testing.T) {
+	input := "<S>:\n  rhs:\n    - [a]\n    - [<L>]\n<L>:\n  rhs:\n    - [b, <L>]\n"
+	problems := ValidateBytes([]byte(input), "yaml", "loop.yml")
+	assertProblems(t, problems, []error{ErrNonProductiveProduction}, []int{5})
+}
+
+func TestValidateUnknownParameter(t *testing.T) {
+	input := "<S>:\n" +
+		"  params: [In]\n" +
+		"  rhs:\n" +
+		"    -\n" +
+		"      - <T>:\n" +
+		"          params:\n" +
+		"            passthrough: ['?Yield', '+In']\n" +
+		"    -\n" +
+		"      - s:\n" +
+		"          params:\n" +
+		"            conditions: [~Await]\n" +
+		"<T>:\n" +
+		"  params: [Yield]\n" +
+		"  rhs:\n" +
+		"    - [t]\n"
+	problems := ValidateBytes([]byte(input), "yaml", "params.yml")
+	assertProblems(
+		t, problems,
+		[]error{ErrUnknownParameter, ErrUnknownParameter, ErrUnknownParameter},
+		[]int{5, 5, 11},
+	)
+}
@@ -0,0 +1,116 @@
+package grammar
+
+import (
+	"io"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// MarshalYAML deals with converting the grammar into the YAML
+// representation described in the parser package so a grammar
+// can be saved after it has been transformed.
+func (g *Grammar) MarshalYAML() (interface{}, error) {
+	productions := yaml.MapSlice{}
+	for _, prod := range g.Productions {
+		pMap := yaml.MapSlice{}
+		if len(prod.Params) > 0 {
+			pMap = append(pMap, yaml.MapItem{Key: "params", Value: prod.Params})
+		}
+		if len(prod.Refines) > 0 {
+			covers := []string{}
+			for _, cover := range prod.Refines {
+				covers = append(covers, "<"+cover+">")
+			}
+			pMap = append(pMap, yaml.MapItem{Key: "refines", Value: covers})
+		}
+		rhs := []interface{}{}
+		for _, rule := range prod.RHS {
+			rhs = append(rhs, marshalRule(rule))
+		}
+		pMap = append(pMap, yaml.MapItem{Key: "rhs", Value: rhs})
+		if len(prod.AST) > 0 {
+			pMap = append(pMap, yaml.MapItem{Key: "ast", Value: marshalASTMappings(prod.AST)})
+		}
+		productions = append(productions, yaml.MapItem{Key: "<" + prod.Name + ">", Value: pMap})
+	}
+	return productions, nil
+}
+
+// WriteYAML deals with writing the grammar to the given writer
+// in the YAML representation it can be loaded from.
+func WriteYAML(w io.Writer, grammar *Grammar) error {
+	data, err := yaml.Marshal(grammar)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Provides the YAML representation of a sequence of right-hand side symbols.
+func marshalRule(rule []RHSRuleSymbol) []interface{} {
+	symbols := []interface{}{}
+	for _, symbol := range rule {
+		symbols = append(symbols, marshalSymbol(symbol))
+	}
+	return symbols
+}
+
+// Provides the YAML representation of a single right-hand side symbol,
+// symbols without parameters are written as plain strings.
+func marshalSymbol(symbol RHSRuleSymbol) interface{} {
+	switch s := symbol.(type) {
+	case *NonTerminalRHSRuleSymbol:
+		name := "<" + s.name + ">"
+		if s.params == nil {
+			return name
+		}
+		params := yaml.MapSlice{}
+		if len(s.params.Passthrough) > 0 {
+			params = append(params, yaml.MapItem{Key: "passthrough", Value: s.params.Passthrough})
+		}
+		if len(s.params.Conditions) > 0 {
+			params = append(params, yaml.MapItem{Key: "conditions", Value: s.params.Conditions})
+		}
+		if s.params.Optional != nil {
+			params = append(params, yaml.MapItem{Key: "optional", Value: *s.params.Optional})
+		}
+		return withParams(name, params)
+	case *TerminalRHSRuleSymbol:
+		if s.params == nil || len(s.params.Conditions) == 0 {
+			return s.name
+		}
+		return withParams(s.name, yaml.MapSlice{{Key: "conditions", Value: s.params.Conditions}})
+	case *ExcludeRHSRuleSymbol:
+		return "<!" + s.name + "!>"
+	case *LookaheadRHSRuleSymbol:
+		exclude := []interface{}{}
+		if s.params != nil {
+			for _, exclusion := range s.params.Exclude {
+				exclude = append(exclude, marshalRule(exclusion))
+			}
+		}
+		return withParams("<*Lookahead*>", yaml.MapSlice{{Key: "exclude", Value: exclude}})
+	case *ConditionalRHSRuleSymbol:
+		conditional := yaml.MapSlice{}
+		if s.params != nil {
+			conditional = append(conditional, yaml.MapItem{
+				Key:   "params",
+				Value: yaml.MapSlice{{Key: "conditions", Value: s.params.Conditions}},
+			})
+		}
+		conditional = append(conditional, yaml.MapItem{Key: "parts", Value: marshalRule(s.Parts)})
+		return yaml.MapSlice{{Key: "<*Conditional*>", Value: conditional}}
+	default:
+		return symbol.Name()
+	}
+}
+
+// Provides the map of a symbol to its params, symbols
+// without any params are written as plain strings.
+func withParams(name string, params yaml.MapSlice) interface{} {
+	if len(params) == 0 {
+		return name
+	}
+	return yaml.MapSlice{{Key: name, Value: yaml.MapSlice{{Key: "params", Value: params}}}}
+}
@@ -0,0 +1,87 @@
+package grammar
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+// Asserts the grammar is the same once it has been written
+// as YAML and loaded again, along with the YAML written for the
+// loaded grammar being identical.
+func assertRoundTrip(t *testing.T, grammar *Grammar) {
+	written := &bytes.Buffer{}
+	err := WriteYAML(written, grammar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBytes(written.Bytes(), "yaml", "written.yml")
+	if err != nil {
+		t.Fatalf("Failed to load the written grammar: %v\n%s", err, written.Bytes())
+	}
+	assertSame(t, *loaded, *grammar)
+	rewritten := &bytes.Buffer{}
+	err = WriteYAML(rewritten, loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewritten.String() != written.String() {
+		t.Errorf("Expected the written grammar to be stable but got \n%v\nthen\n%v", written, rewritten)
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	input := "<A>:\n" +
+		"  params: [In, Yield]\n" +
+		"  rhs:\n" +
+		"    -\n" +
+		"      - <*Lookahead*>:\n" +
+		"          params:\n" +
+		"            exclude: [['{'], [async, <!LineTerminator!>, function]]\n" +
+		"      - <B>:\n" +
+		"          params:\n" +
+		"            passthrough: ['?In', +Yield]\n" +
+		"            conditions: [~Yield]\n" +
+		"            optional: true\n" +
+		"      - 'y'\n" +
+		"    -\n" +
+		"      - <*Conditional*>:\n" +
+		"          params:\n" +
+		"            conditions: [+In]\n" +
+		"          parts: [in, <!LineTerminator!>, <B>]\n" +
+		"      - yield:\n" +
+		"          params:\n" +
+		"            conditions: [+Yield]\n" +
+		"    - ['[empty]']\n" +
+		"<B>:\n" +
+		"  params: [In, Yield]\n" +
+		"  rhs:\n" +
+		"    - [b]\n"
+	grammar, err := LoadBytes([]byte(input), "yaml", "input.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertRoundTrip(t, grammar)
+}
+
+func TestWriteYAMLFixtures(t *testing.T) {
+	for _, name := range []string{"elr1", "elr2", "eo1", "ep1", "lf1", "lf2", "lf3", "lf4", "lf5"} {
+		input, expected := loadGrammarFixture(name)
+		assertRoundTrip(t, input)
+		assertRoundTrip(t, expected)
+	}
+}
+
+func TestWriteYAMLTransformed(t *testing.T) {
+	data, err := ioutil.ReadFile("../parser/grammar.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grammar, err := LoadBytes(data, "yaml", "grammar.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertRoundTrip(t, grammar)
+	Transform(grammar)
+	assertRoundTrip(t, grammar)
+}
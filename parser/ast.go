@@ -0,0 +1,117 @@
+package parser
+
+// Node provides a node of the abstract syntax tree built from a parse tree,
+// either a node type generated from the AST annotations of the grammar
+// or the *Token of a terminal which has been kept.
+type Node interface{}
+
+// HelperKind provides a type alias to distinguish between the helper
+// productions introduced to make the grammar LL(1).
+type HelperKind int
+
+const (
+	_ HelperKind = iota
+	// FactorHelper marks a production introduced by left-factoring which
+	// parses the rest of the alternatives that share a common prefix.
+	FactorHelper
+	// RecursionHelper marks a production introduced by eliminating left recursion
+	// which parses each repetition of a left recursive alternative.
+	RecursionHelper
+)
+
+// ASTRule provides how the symbols parsed for an annotated alternative of the
+// source grammar map to the symbols of the alternative, the symbols can be spread
+// across the rule and the rules of the factor helpers it is reached through.
+type ASTRule struct {
+	// Builder holds the index of the function in ASTBuilders which builds the alternative.
+	Builder int
+	// Length holds the number of symbols of the annotated alternative.
+	Length int
+	// Positions holds the position in the annotated alternative of each symbol parsed,
+	// an alternative which repeats a left recursive production holds the values built
+	// so far at position 0.
+	Positions []int
+}
+
+// BuildAST deals with building the values of the abstract syntax tree for a node
+// of the parse tree. The helper productions are skipped over so each node is built
+// from the alternative of the source grammar that was parsed, alternatives which
+// are not annotated provide the values built for the non-terminals they contain.
+func (t *ParseTables) BuildAST(node *ParseNode) []Node {
+	if node.Terminal {
+		return []Node{node.Tokens[0]}
+	}
+	children, rule, repetition := t.unfold(node)
+	values := t.buildAlternative(children, rule, nil)
+	for repetition != nil {
+		children, rule, repetition = t.unfold(repetition)
+		values = t.buildAlternative(children, rule, values)
+	}
+	return values
+}
+
+// Provides the children of the node with those of the factor helpers it continues in
+// replaced by their own children, along with the rule which completes the alternative
+// and the recursion helper which parses the next repetition, nil when there is none.
+func (t *ParseTables) unfold(node *ParseNode) ([]*ParseNode, int, *ParseNode) {
+	children := []*ParseNode{}
+	rule := node.Rule
+	var repetition *ParseNode
+	current := node
+	for current != nil {
+		rule = current.Rule
+		var next *ParseNode
+		for _, child := range current.Children {
+			switch {
+			case !child.Terminal && t.Helpers[child.Symbol] == FactorHelper:
+				next = child
+			case !child.Terminal && t.Helpers[child.Symbol] == RecursionHelper:
+				repetition = child
+			default:
+				children = append(children, child)
+			}
+		}
+		current = next
+	}
+	return children, rule, repetition
+}
+
+// Provides the values of an alternative from its parsed symbols, the values
+// built so far are the first symbol of a repetition of a left recursive alternative.
+func (t *ParseTables) buildAlternative(children []*ParseNode, rule int, leading []Node) []Node {
+	astRule, annotated := t.ASTRules[rule]
+	if !annotated {
+		values := append([]Node{}, leading...)
+		for _, child := range children {
+			if !child.Terminal {
+				values = append(values, t.BuildAST(child)...)
+			}
+		}
+		return values
+	}
+	values := make([][]Node, astRule.Length)
+	if leading != nil && astRule.Length > 0 {
+		values[0] = leading
+	}
+	for i, child := range children {
+		values[astRule.Positions[i]] = t.BuildAST(child)
+	}
+	return t.ASTBuilders[astRule.Builder](values)
+}
+
+// Provides the first of the values built for a symbol, nil when there are none.
+func firstNode(values []Node) Node {
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+// Provides the values built for each of the given symbols in order.
+func concatNodes(values ...[]Node) []Node {
+	concatenated := []Node{}
+	for _, symbolValues := range values {
+		concatenated = append(concatenated, symbolValues...)
+	}
+	return concatenated
+}
@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type astTestBinary struct {
+	Left  Node
+	Op    Node
+	Right Node
+}
+
+type astTestCall struct {
+	Callee    Node
+	Arguments []Node
+}
+
+type astTestIdentifier struct {
+	Name Node
+}
+
+// Holds the tables generated for an annotated expression grammar where the left
+// recursion of <Expr> and <Args> has been eliminated and <Term> has been left-factored:
+// Expr: Expr + Term | Term, Term: IdentifierName ( Args? ) | IdentifierName
+// and Args: Args , IdentifierName | IdentifierName where both IdentifierName
+// alternatives build an identifier.
+func astTestTables() *ParseTables {
+	return &ParseTables{
+		SymbolNames: []string{
+			"", "Script", "Expr", "Expr'", "Term", "TermA0", "TermA0A0", "Args", "Args'",
+			"+", "IdentifierName", "(", ")", ",", "[eoi]",
+		},
+		NonTerminalCount: 8,
+		Starts:           map[string]Symbol{"Script": 1},
+		EndOfInput:       14,
+		Rules: []*ParseRule{
+			{Production: 1, Symbols: []Symbol{2}},
+			{Production: 2, Symbols: []Symbol{4, 3}},
+			{Production: 3, Symbols: []Symbol{9, 4, 3}},
+			{Production: 3, Symbols: []Symbol{}},
+			{Production: 4, Symbols: []Symbol{10, 5}},
+			{Production: 5, Symbols: []Symbol{11, 6}},
+			{Production: 5, Symbols: []Symbol{}},
+			{Production: 6, Symbols: []Symbol{7, 12}},
+			{Production: 6, Symbols: []Symbol{12}},
+			{Production: 7, Symbols: []Symbol{10, 8}},
+			{Production: 8, Symbols: []Symbol{13, 10, 8}},
+			{Production: 8, Symbols: []Symbol{}},
+		},
+		ParseTable: map[Symbol]map[Symbol]int{
+			1: {10: 0},
+			2: {10: 1},
+			3: {9: 2, 14: 3},
+			4: {10: 4},
+			5: {9: 6, 11: 5, 14: 6},
+			6: {10: 7, 12: 8},
+			7: {10: 9},
+			8: {12: 11, 13: 10},
+		},
+		Helpers: map[Symbol]HelperKind{3: RecursionHelper, 5: FactorHelper, 6: FactorHelper, 8: RecursionHelper},
+		ASTRules: map[int]*ASTRule{
+			1:  {Builder: 1, Length: 1, Positions: []int{0}},
+			2:  {Builder: 0, Length: 3, Positions: []int{1, 2}},
+			6:  {Builder: 3, Length: 1, Positions: []int{0}},
+			7:  {Builder: 2, Length: 4, Positions: []int{0, 1, 2, 3}},
+			8:  {Builder: 2, Length: 4, Positions: []int{0, 1, 3}},
+			9:  {Builder: 3, Length: 1, Positions: []int{0}},
+			10: {Builder: 4, Length: 3, Positions: []int{1, 2}},
+		},
+		ASTBuilders: []func([][]Node) []Node{
+			func(values [][]Node) []Node {
+				return []Node{&astTestBinary{Left: firstNode(values[0]), Op: firstNode(values[1]), Right: firstNode(values[2])}}
+			},
+			func(values [][]Node) []Node {
+				return concatNodes(values[0])
+			},
+			func(values [][]Node) []Node {
+				return []Node{&astTestCall{Callee: firstNode(values[0]), Arguments: concatNodes(values[2])}}
+			},
+			func(values [][]Node) []Node {
+				return []Node{&astTestIdentifier{Name: firstNode(values[0])}}
+			},
+			func(values [][]Node) []Node {
+				return concatNodes(values[0], values[2])
+			},
+		},
+	}
+}
+
+// Provides a description of the nodes of an abstract syntax tree.
+func describeNodes(nodes []Node) string {
+	descriptions := []string{}
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *Token:
+			descriptions = append(descriptions, n.Value)
+		case *astTestBinary:
+			descriptions = append(descriptions, fmt.Sprintf(
+				"Binary(%v %v %v)", describeNodes([]Node{n.Left}), describeNodes([]Node{n.Op}), describeNodes([]Node{n.Right}),
+			))
+		case *astTestCall:
+			descriptions = append(descriptions, fmt.Sprintf(
+				"Call(%v [%v])", describeNodes([]Node{n.Callee}), describeNodes(n.Arguments),
+			))
+		case *astTestIdentifier:
+			descriptions = append(descriptions, fmt.Sprintf("Identifier(%v)", describeNodes([]Node{n.Name})))
+		default:
+			descriptions = append(descriptions, fmt.Sprintf("%T", node))
+		}
+	}
+	return strings.Join(descriptions, " ")
+}
+
+func TestParseTablesBuildAST(t *testing.T) {
+	tables := astTestTables()
+	for _, test := range []struct {
+		input    string
+		expected string
+	}{
+		{"x", "Identifier(x)"},
+		{"f ( )", "Call(f [])"},
+		{"f ( a , b )", "Call(f [Identifier(a) b])"},
+		{"a + b + c", "Binary(Binary(Identifier(a) + Identifier(b)) + Identifier(c))"},
+		{"f ( a ) + \n g ( )", "Binary(Call(f [Identifier(a)]) + Call(g []))"},
+	} {
+		tree, err := tables.Parse(1, tokensOf(test.input))
+		if err != nil {
+			t.Fatalf("Expected %q to be parsed but got %v", test.input, err)
+		}
+		if actual := describeNodes(tables.BuildAST(tree)); actual != test.expected {
+			t.Errorf("Expected %q to build %v but got %v", test.input, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+//go:build ignore
+
+// Generates idtables.go, which holds the code points of the ID_Start and ID_Continue
+// properties as range tables so identifiers can be lexed with a single binary search
+// per code point rather than checking each of the categories they are made up of.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"unicode"
+)
+
+func isIDStart(c rune) bool {
+	return (unicode.IsLetter(c) || unicode.In(c, unicode.Nl, unicode.Other_ID_Start)) &&
+		!unicode.In(c, unicode.Pattern_Syntax, unicode.Pattern_White_Space)
+}
+
+func isIDContinue(c rune) bool {
+	return (isIDStart(c) || unicode.In(c, unicode.Other_ID_Continue, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc)) &&
+		!unicode.In(c, unicode.Pattern_Syntax, unicode.Pattern_White_Space)
+}
+
+func main() {
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// Code generated by gen_idtables.go from Unicode %v. DO NOT EDIT.\n\n", unicode.Version)
+	fmt.Fprintf(&source, "package parser\n\nimport \"unicode\"\n\n")
+	fmt.Fprintf(&source, "// Holds the version of Unicode the tables are generated from.\n")
+	fmt.Fprintf(&source, "const idTablesUnicodeVersion = %q\n\n", unicode.Version)
+	writeTable(&source, "idStartTable", "ID_Start", isIDStart)
+	writeTable(&source, "idContinueTable", "ID_Continue", isIDContinue)
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("idtables.go", formatted, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Writes the range table of the code points the given function is true for.
+func writeTable(source *bytes.Buffer, name string, property string, is func(rune) bool) {
+	r16, r32 := []unicode.Range16{}, []unicode.Range32{}
+	latinOffset := 0
+	for c := rune(0); c <= unicode.MaxRune; c++ {
+		if !is(c) {
+			continue
+		}
+		lo := c
+		for c+1 <= unicode.MaxRune && is(c+1) {
+			c++
+		}
+		if c <= 0xFFFF {
+			r16 = append(r16, unicode.Range16{Lo: uint16(lo), Hi: uint16(c), Stride: 1})
+			if c <= unicode.MaxLatin1 {
+				latinOffset++
+			}
+		} else if lo <= 0xFFFF {
+			// Ranges which cross into the supplementary planes are split.
+			r16 = append(r16, unicode.Range16{Lo: uint16(lo), Hi: 0xFFFF, Stride: 1})
+			r32 = append(r32, unicode.Range32{Lo: 0x10000, Hi: uint32(c), Stride: 1})
+		} else {
+			r32 = append(r32, unicode.Range32{Lo: uint32(lo), Hi: uint32(c), Stride: 1})
+		}
+	}
+	fmt.Fprintf(source, "// Holds the code points with the %v property.\n", property)
+	fmt.Fprintf(source, "var %v = &unicode.RangeTable{\n\tR16: []unicode.Range16{\n", name)
+	for _, r := range r16 {
+		fmt.Fprintf(source, "\t\t{0x%04x, 0x%04x, 1},\n", r.Lo, r.Hi)
+	}
+	fmt.Fprintf(source, "\t},\n\tR32: []unicode.Range32{\n")
+	for _, r := range r32 {
+		fmt.Fprintf(source, "\t\t{0x%x, 0x%x, 1},\n", r.Lo, r.Hi)
+	}
+	fmt.Fprintf(source, "\t},\n\tLatinOffset: %v,\n}\n\n", latinOffset)
+}
@@ -0,0 +1,287 @@
+package parser
+
+import "strconv"
+
+// TokenKind provides a type alias to distinguish between the kinds of token
+// produced by the lexer, each punctuator and reserved word is a kind of its own
+// so tokens can be matched to the terminals of the syntactic grammar without
+// comparing their values.
+type TokenKind int
+
+const (
+	_ TokenKind = iota
+	LineTerminatorToken
+	IdentifierNameToken
+	NullLiteralToken
+	BooleanLiteralToken
+	DecimalLiteralToken
+	BinaryIntegerLiteralToken
+	OctalIntegerLiteralToken
+	HexIntegerLiteralToken
+	StringLiteralToken
+	RegularExpressionLiteralToken
+	NoSubstitutionTemplateToken
+	TemplateHeadToken
+	TemplateMiddleToken
+	TemplateTailToken
+	// Punctuators, the right brace and div punctuators come last
+	// as they are only produced for some lexical goals.
+	LeftBraceToken
+	LeftParenToken
+	RightParenToken
+	LeftBracketToken
+	RightBracketToken
+	DotToken
+	EllipsisToken
+	SemicolonToken
+	CommaToken
+	LessThanToken
+	GreaterThanToken
+	LessThanEqualsToken
+	GreaterThanEqualsToken
+	EqualsEqualsToken
+	ExclamationEqualsToken
+	EqualsEqualsEqualsToken
+	ExclamationEqualsEqualsToken
+	PlusToken
+	MinusToken
+	AsteriskToken
+	PercentToken
+	AsteriskAsteriskToken
+	PlusPlusToken
+	MinusMinusToken
+	LessThanLessThanToken
+	GreaterThanGreaterThanToken
+	GreaterThanGreaterThanGreaterThanToken
+	AmpersandToken
+	BarToken
+	CaretToken
+	ExclamationToken
+	TildeToken
+	AmpersandAmpersandToken
+	BarBarToken
+	QuestionToken
+	ColonToken
+	EqualsToken
+	PlusEqualsToken
+	MinusEqualsToken
+	AsteriskEqualsToken
+	PercentEqualsToken
+	AsteriskAsteriskEqualsToken
+	LessThanLessThanEqualsToken
+	GreaterThanGreaterThanEqualsToken
+	GreaterThanGreaterThanGreaterThanEqualsToken
+	AmpersandEqualsToken
+	BarEqualsToken
+	CaretEqualsToken
+	EqualsGreaterThanToken
+	RightBraceToken
+	SlashToken
+	SlashEqualsToken
+	// Keywords followed by the future reserved words.
+	AwaitToken
+	BreakToken
+	CaseToken
+	CatchToken
+	ClassToken
+	ConstToken
+	ContinueToken
+	DebuggerToken
+	DefaultToken
+	DeleteToken
+	DoToken
+	ElseToken
+	ExportToken
+	ExtendsToken
+	FinallyToken
+	ForToken
+	FunctionToken
+	IfToken
+	ImportToken
+	InToken
+	InstanceofToken
+	NewToken
+	ReturnToken
+	SuperToken
+	SwitchToken
+	ThisToken
+	ThrowToken
+	TryToken
+	TypeofToken
+	VarToken
+	VoidToken
+	WhileToken
+	WithToken
+	YieldToken
+	EnumToken
+	// Holds the number of token kinds.
+	tokenKindCount
+)
+
+// Holds the name and constant of each token kind, the name of a punctuator
+// or reserved word is its source text.
+var tokenKinds = [tokenKindCount]struct{ name, constant string }{
+	LineTerminatorToken:                    {"LineTerminator", "LineTerminatorToken"},
+	IdentifierNameToken:                    {"IdentifierName", "IdentifierNameToken"},
+	NullLiteralToken:                       {"NullLiteral", "NullLiteralToken"},
+	BooleanLiteralToken:                    {"BooleanLiteral", "BooleanLiteralToken"},
+	DecimalLiteralToken:                    {"DecimalLiteral", "DecimalLiteralToken"},
+	BinaryIntegerLiteralToken:              {"BinaryIntegerLiteral", "BinaryIntegerLiteralToken"},
+	OctalIntegerLiteralToken:               {"OctalIntegerLiteral", "OctalIntegerLiteralToken"},
+	HexIntegerLiteralToken:                 {"HexIntegerLiteral", "HexIntegerLiteralToken"},
+	StringLiteralToken:                     {"StringLiteral", "StringLiteralToken"},
+	RegularExpressionLiteralToken:          {"RegularExpressionLiteral", "RegularExpressionLiteralToken"},
+	NoSubstitutionTemplateToken:            {"NoSubstitutionTemplate", "NoSubstitutionTemplateToken"},
+	TemplateHeadToken:                      {"TemplateHead", "TemplateHeadToken"},
+	TemplateMiddleToken:                    {"TemplateMiddle", "TemplateMiddleToken"},
+	TemplateTailToken:                      {"TemplateTail", "TemplateTailToken"},
+	LeftBraceToken:                         {"{", "LeftBraceToken"},
+	LeftParenToken:                         {"(", "LeftParenToken"},
+	RightParenToken:                        {")", "RightParenToken"},
+	LeftBracketToken:                       {"[", "LeftBracketToken"},
+	RightBracketToken:                      {"]", "RightBracketToken"},
+	DotToken:                               {".", "DotToken"},
+	EllipsisToken:                          {"...", "EllipsisToken"},
+	SemicolonToken:                         {";", "SemicolonToken"},
+	CommaToken:                             {",", "CommaToken"},
+	LessThanToken:                          {"<", "LessThanToken"},
+	GreaterThanToken:                       {">", "GreaterThanToken"},
+	LessThanEqualsToken:                    {"<=", "LessThanEqualsToken"},
+	GreaterThanEqualsToken:                 {">=", "GreaterThanEqualsToken"},
+	EqualsEqualsToken:                      {"==", "EqualsEqualsToken"},
+	ExclamationEqualsToken:                 {"!=", "ExclamationEqualsToken"},
+	EqualsEqualsEqualsToken:                {"===", "EqualsEqualsEqualsToken"},
+	ExclamationEqualsEqualsToken:           {"!==", "ExclamationEqualsEqualsToken"},
+	PlusToken:                              {"+", "PlusToken"},
+	MinusToken:                             {"-", "MinusToken"},
+	AsteriskToken:                          {"*", "AsteriskToken"},
+	PercentToken:                           {"%", "PercentToken"},
+	AsteriskAsteriskToken:                  {"**", "AsteriskAsteriskToken"},
+	PlusPlusToken:                          {"++", "PlusPlusToken"},
+	MinusMinusToken:                        {"--", "MinusMinusToken"},
+	LessThanLessThanToken:                  {"<<", "LessThanLessThanToken"},
+	GreaterThanGreaterThanToken:            {">>", "GreaterThanGreaterThanToken"},
+	GreaterThanGreaterThanGreaterThanToken: {">>>", "GreaterThanGreaterThanGreaterThanToken"},
+	AmpersandToken:                         {"&", "AmpersandToken"},
+	BarToken:                               {"|", "BarToken"},
+	CaretToken:                             {"^", "CaretToken"},
+	ExclamationToken:                       {"!", "ExclamationToken"},
+	TildeToken:                             {"~", "TildeToken"},
+	AmpersandAmpersandToken:                {"&&", "AmpersandAmpersandToken"},
+	BarBarToken:                            {"||", "BarBarToken"},
+	QuestionToken:                          {"?", "QuestionToken"},
+	ColonToken:                             {":", "ColonToken"},
+	EqualsToken:                            {"=", "EqualsToken"},
+	PlusEqualsToken:                        {"+=", "PlusEqualsToken"},
+	MinusEqualsToken:                       {"-=", "MinusEqualsToken"},
+	AsteriskEqualsToken:                    {"*=", "AsteriskEqualsToken"},
+	PercentEqualsToken:                     {"%=", "PercentEqualsToken"},
+	AsteriskAsteriskEqualsToken:            {"**=", "AsteriskAsteriskEqualsToken"},
+	LessThanLessThanEqualsToken:            {"<<=", "LessThanLessThanEqualsToken"},
+	GreaterThanGreaterThanEqualsToken:      {">>=", "GreaterThanGreaterThanEqualsToken"},
+	GreaterThanGreaterThanGreaterThanEqualsToken: {">>>=", "GreaterThanGreaterThanGreaterThanEqualsToken"},
+	AmpersandEqualsToken:                         {"&=", "AmpersandEqualsToken"},
+	BarEqualsToken:                               {"|=", "BarEqualsToken"},
+	CaretEqualsToken:                             {"^=", "CaretEqualsToken"},
+	EqualsGreaterThanToken:                       {"=>", "EqualsGreaterThanToken"},
+	RightBraceToken:                              {"}", "RightBraceToken"},
+	SlashToken:                                   {"/", "SlashToken"},
+	SlashEqualsToken:                             {"/=", "SlashEqualsToken"},
+	AwaitToken:                                   {"await", "AwaitToken"},
+	BreakToken:                                   {"break", "BreakToken"},
+	CaseToken:                                    {"case", "CaseToken"},
+	CatchToken:                                   {"catch", "CatchToken"},
+	ClassToken:                                   {"class", "ClassToken"},
+	ConstToken:                                   {"const", "ConstToken"},
+	ContinueToken:                                {"continue", "ContinueToken"},
+	DebuggerToken:                                {"debugger", "DebuggerToken"},
+	DefaultToken:                                 {"default", "DefaultToken"},
+	DeleteToken:                                  {"delete", "DeleteToken"},
+	DoToken:                                      {"do", "DoToken"},
+	ElseToken:                                    {"else", "ElseToken"},
+	ExportToken:                                  {"export", "ExportToken"},
+	ExtendsToken:                                 {"extends", "ExtendsToken"},
+	FinallyToken:                                 {"finally", "FinallyToken"},
+	ForToken:                                     {"for", "ForToken"},
+	FunctionToken:                                {"function", "FunctionToken"},
+	IfToken:                                      {"if", "IfToken"},
+	ImportToken:                                  {"import", "ImportToken"},
+	InToken:                                      {"in", "InToken"},
+	InstanceofToken:                              {"instanceof", "InstanceofToken"},
+	NewToken:                                     {"new", "NewToken"},
+	ReturnToken:                                  {"return", "ReturnToken"},
+	SuperToken:                                   {"super", "SuperToken"},
+	SwitchToken:                                  {"switch", "SwitchToken"},
+	ThisToken:                                    {"this", "ThisToken"},
+	ThrowToken:                                   {"throw", "ThrowToken"},
+	TryToken:                                     {"try", "TryToken"},
+	TypeofToken:                                  {"typeof", "TypeofToken"},
+	VarToken:                                     {"var", "VarToken"},
+	VoidToken:                                    {"void", "VoidToken"},
+	WhileToken:                                   {"while", "WhileToken"},
+	WithToken:                                    {"with", "WithToken"},
+	YieldToken:                                   {"yield", "YieldToken"},
+	EnumToken:                                    {"enum", "EnumToken"},
+}
+
+// String provides the name of the token kind as it appears in the lexical
+// grammar, punctuators and reserved words are named by their source text.
+func (k TokenKind) String() string {
+	if k <= 0 || k >= tokenKindCount {
+		return ""
+	}
+	return tokenKinds[k].name
+}
+
+// GoString provides the name of the constant of the token kind,
+// used when the kind is written to generated source.
+func (k TokenKind) GoString() string {
+	if k <= 0 || k >= tokenKindCount {
+		return "TokenKind(" + strconv.Itoa(int(k)) + ")"
+	}
+	return tokenKinds[k].constant
+}
+
+// Terminal provides the name of the terminal of the syntactic grammar tokens
+// of the kind are an instance of, which is the name of the kind apart from the
+// kinds of numeric literal that are all instances of NumericLiteral.
+func (k TokenKind) Terminal() string {
+	switch k {
+	case DecimalLiteralToken, BinaryIntegerLiteralToken, OctalIntegerLiteralToken, HexIntegerLiteralToken:
+		return "NumericLiteral"
+	}
+	return k.String()
+}
+
+// IsPunctuator determines whether the token kind is a punctuator.
+func (k TokenKind) IsPunctuator() bool {
+	return k >= LeftBraceToken && k <= SlashEqualsToken
+}
+
+// IsReservedWord determines whether the token kind is a keyword or future reserved word,
+// null and true or false are reserved words which are kinds of literal instead.
+func (k TokenKind) IsReservedWord() bool {
+	return k >= AwaitToken && k <= EnumToken
+}
+
+// TerminalKinds provides the kinds of token which are an instance of the named
+// terminal of the syntactic grammar, empty for terminals such as contextual keywords
+// which are only matched by the value of an IdentifierName token.
+func TerminalKinds(terminal string) []TokenKind {
+	kinds := []TokenKind{}
+	for k := LineTerminatorToken; k < tokenKindCount; k++ {
+		if k.Terminal() == terminal {
+			kinds = append(kinds, k)
+		}
+	}
+	return kinds
+}
+
+// Provides the token kinds in the given range keyed by their source text.
+func tokenKindsBetween(first TokenKind, last TokenKind) map[string]TokenKind {
+	kinds := map[string]TokenKind{}
+	for k := first; k <= last; k++ {
+		kinds[k.String()] = k
+	}
+	return kinds
+}
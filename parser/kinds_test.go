@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestTokenKinds(t *testing.T) {
+	for kind := LineTerminatorToken; kind < tokenKindCount; kind++ {
+		if kind.String() == "" || fmt.Sprintf("%#v", kind) == "" {
+			t.Errorf("Expected token kind %d to have a name and constant", int(kind))
+		}
+	}
+	if kind := Keywords()["function"]; kind != FunctionToken || !kind.IsReservedWord() {
+		t.Errorf("Expected function to be the FunctionToken reserved word but got %#v", kind)
+	}
+	if kind := Punctuators()[">>>="]; kind != GreaterThanGreaterThanGreaterThanEqualsToken || !kind.IsPunctuator() {
+		t.Errorf("Expected >>>= to be a punctuator but got %#v", kind)
+	}
+	for _, value := range []string{"}", "/", "/="} {
+		if _, exists := Punctuators()[value]; exists {
+			t.Errorf("Expected %v not to be a common punctuator", value)
+		}
+	}
+	expected := []TokenKind{DecimalLiteralToken, BinaryIntegerLiteralToken, OctalIntegerLiteralToken, HexIntegerLiteralToken}
+	if actual := TerminalKinds("NumericLiteral"); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected the kinds of NumericLiteral to be %v but got %v", expected, actual)
+	}
+	if actual := TerminalKinds("let"); len(actual) > 0 {
+		t.Errorf("Expected let to only be matched by value but got %v", actual)
+	}
+}
+
+func TestParseTablesMatchesKinds(t *testing.T) {
+	tables := predicateTestTables()
+	tokens, err := NewLexer().Tokenise([]rune("let function x 0x1F"), InputElementDiv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range [][]Symbol{{7, 12}, {10}, {12}, {}} {
+		if actual := tables.TerminalsOf(tokens[i]); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %q to be an instance of %v but got %v", tokens[i].Value, expected, actual)
+		}
+	}
+	if tables.Matches(tokens[1], 12) || !tables.Matches(tokens[2], 12) {
+		t.Errorf("Expected only the identifier to match IdentifierName")
+	}
+	expected := []string{"HexIntegerLiteral", "NumericLiteral"}
+	if actual := TerminalNames(tokens[3]); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected the terminal names %v but got %v", expected, actual)
+	}
+}
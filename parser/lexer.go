@@ -5,8 +5,8 @@ package parser
 // an input slice of code points.
 type Lexer interface {
 	Tokenise(input []rune, goal LexicalGoalSymbol) ([]*Token, error)
-	TokeniseUpToType(input []rune, tokenType string, goal LexicalGoalSymbol) ([]*Token, error, int)
-	TokeniseUpToToken(input []rune, tokenType string, tokenValue string, goal LexicalGoalSymbol) ([]*Token, error, int)
+	TokeniseUpToType(input []rune, kind TokenKind, goal LexicalGoalSymbol) ([]*Token, error, int)
+	TokeniseUpToToken(input []rune, kind TokenKind, tokenValue string, goal LexicalGoalSymbol) ([]*Token, error, int)
 	Reset()
 }
 
@@ -18,90 +18,58 @@ func NewLexer() Lexer {
 		"lineTerminators": LineTerminators(),
 	}
 	return &lexerImpl{
-		charMap,
-		Punctuators(),
-		Keywords(),
-		FutureReservedWords(),
+		newScanner(charMap, Punctuators(), Keywords(), FutureReservedWords()),
 		[]rune{},
 		[]*Token{},
 	}
 }
 
 type lexerImpl struct {
-	charMap       map[string]map[rune]rune
-	pMap          map[string]rune
-	kwMap         map[string]rune
-	frwMap        map[string]rune
+	scanner       *scanner
 	currentInput  []rune
 	currentTokens []*Token
 }
 
 // Tokenise deals with generating a list of tokens for the given input
-// data.
+// data, errors are returned as a *LexError holding the position they occurred at.
 func (l *lexerImpl) Tokenise(input []rune, goal LexicalGoalSymbol) ([]*Token, error) {
 	l.currentInput = input
-	var err error
-	i := 0
-	for err == nil && i < len(input) {
-		var tkn *Token
-		var nextPos int
-		tkn, nextPos, err = NextToken(i, input, l.charMap, l.pMap, l.kwMap, l.frwMap, goal)
-		if err == nil {
-			if tkn != nil {
-				l.currentTokens = append(l.currentTokens, tkn)
-			}
-			i = nextPos
-		}
-	}
+	_, err := l.tokeniseUpTo(input, goal, func(*Token) bool { return false })
 	return l.currentTokens, err
 }
 
-func (l *lexerImpl) TokeniseUpToType(input []rune, tType string, goal LexicalGoalSymbol) ([]*Token, error, int) {
+func (l *lexerImpl) TokeniseUpToType(input []rune, kind TokenKind, goal LexicalGoalSymbol) ([]*Token, error, int) {
 	l.currentInput = input
 	l.currentTokens = []*Token{}
-	var err error
-	i := 0
-	reachedType := false
-	for err == nil && i < len(input) && !reachedType {
-		var tkn *Token
-		var nextPos int
-		tkn, nextPos, err = NextToken(i, input, l.charMap, l.pMap, l.kwMap, l.frwMap, goal)
-		if err == nil {
-			if tkn != nil {
-				l.currentTokens = append(l.currentTokens, tkn)
-				if tkn.Name == tType {
-					reachedType = true
-				}
-			} else {
-				i = nextPos
-			}
-		}
-	}
-	return l.currentTokens, err, i
+	end, err := l.tokeniseUpTo(input, goal, func(tkn *Token) bool { return tkn.Kind == kind })
+	return l.currentTokens, err, end
 }
 
-func (l *lexerImpl) TokeniseUpToToken(input []rune, tType string, value string, goal LexicalGoalSymbol) ([]*Token, error, int) {
+func (l *lexerImpl) TokeniseUpToToken(input []rune, kind TokenKind, value string, goal LexicalGoalSymbol) ([]*Token, error, int) {
 	l.currentInput = input
 	l.currentTokens = []*Token{}
-	var err error
-	i := 0
-	reachedTypeandValue := false
-	for err == nil && i < len(input) && !reachedTypeandValue {
-		var tkn *Token
-		var nextPos int
-		tkn, nextPos, err = NextToken(i, input, l.charMap, l.pMap, l.kwMap, l.frwMap, goal)
-		if err == nil {
-			if tkn != nil {
-				l.currentTokens = append(l.currentTokens, tkn)
-				if tkn.Name == tType && tkn.Value == value {
-					reachedTypeandValue = true
-				}
-			} else {
-				i = nextPos
-			}
+	end, err := l.tokeniseUpTo(input, goal, func(tkn *Token) bool {
+		return tkn.Kind == kind && tkn.Value == value
+	})
+	return l.currentTokens, err, end
+}
+
+// Deals with adding the tokens of the input to the current tokens up to and including
+// the first token the given function reached returns true for, providing the index
+// of the code point just after the last token.
+func (l *lexerImpl) tokeniseUpTo(input []rune, goal LexicalGoalSymbol, reached func(*Token) bool) (int, error) {
+	stream := newTokenStream(newRuneWindow(input), l)
+	tkn, err := stream.Next(goal)
+	for tkn != nil {
+		l.currentTokens = append(l.currentTokens, tkn)
+		if reached(tkn) {
+			tkn = nil
+		} else {
+			tkn, err = stream.Next(goal)
 		}
 	}
-	return l.currentTokens, err, i
+	end, _ := stream.after(stream.next)
+	return end, err
 }
 
 // Reset deals with resetting the lexer and clearing the token
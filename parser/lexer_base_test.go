@@ -26,7 +26,7 @@ func tokeniseTest(t *testing.T, lexer Lexer, testData []*tokeniseTestData) {
 				t.Error("Exceeded the expected amount of tokens")
 			}
 			expected := testItem.expected[i]
-			if res.Name != expected.Name || res.Value != expected.Value || res.Pos != expected.Pos {
+			if res.Kind != expected.Kind || res.Value != expected.Value || res.Pos != expected.Pos {
 				preprocessTokensForOutput(expected, res)
 				t.Errorf("Did not get the expected token %+v but got the token %+v", expected, res)
 			}
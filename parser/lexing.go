@@ -2,9 +2,9 @@ package parser
 
 import (
 	"fmt"
-	"math"
 	"strconv"
 	"unicode"
+	"unicode/utf8"
 )
 
 // CommentType provides a type alias
@@ -63,6 +63,9 @@ func IsStartOfIdentifier(c rune, pos int, buf []rune) (bool, int) {
 }
 
 func IsIdentifierPart(pos int, buf []rune) (bool, int) {
+	if pos >= len(buf) {
+		return false, pos
+	}
 	isEscapeSeq := false
 	end := pos + 1
 	c := buf[pos]
@@ -73,22 +76,29 @@ func IsIdentifierPart(pos int, buf []rune) (bool, int) {
 		c == '\u200C' || c == '\u200D' || isEscapeSeq, end
 }
 
+//go:generate go run gen_idtables.go
+
+// IsIDStart determines whether the given code point has the
+// ID_Start property, using the tables generated into idtables.go.
 func IsIDStart(c rune) bool {
-	return (unicode.IsLetter(c) || unicode.In(c, unicode.Nl) ||
-		unicode.In(c, unicode.Other_ID_Start)) &&
-		!(unicode.In(c, unicode.Pattern_Syntax) || unicode.In(c, unicode.Pattern_White_Space))
+	if c < utf8.RuneSelf {
+		return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+	}
+	return unicode.Is(idStartTable, c)
 }
 
+// IsIDContinue determines whether the given code point has the
+// ID_Continue property, using the tables generated into idtables.go.
 func IsIDContinue(c rune) bool {
-	return (IsIDStart(c) || unicode.In(c, unicode.Other_ID_Continue) ||
-		unicode.In(c, unicode.Mn) || unicode.In(c, unicode.Mc) ||
-		unicode.In(c, unicode.Nd) || unicode.In(c, unicode.Pc)) &&
-		!(unicode.In(c, unicode.Pattern_Syntax) || unicode.In(c, unicode.Pattern_White_Space))
+	if c < utf8.RuneSelf {
+		return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_'
+	}
+	return unicode.Is(idContinueTable, c)
 }
 
 func IsUnicodeEspaceSequence(pos int, buf []rune) (bool, int) {
 	// First code point should be a u.
-	if buf[pos] != 'u' {
+	if pos >= len(buf) || buf[pos] != 'u' {
 		return false, -1
 	}
 	// Make sure that there is a next code point.
@@ -147,7 +157,7 @@ func IsHexDigit(c rune) bool {
 // NextToken attempts to parse the next set of code points as a valid
 // token in the ECMAScript lexical grammar.
 func NextToken(pos int, buf []rune, charMap map[string]map[rune]rune,
-	pMap map[string]rune, kwMap map[string]rune, frwMap map[string]rune, goal LexicalGoalSymbol) (*Token, int, error) {
+	pMap map[string]TokenKind, kwMap map[string]TokenKind, frwMap map[string]TokenKind, goal LexicalGoalSymbol) (*Token, int, error) {
 	if pos >= len(buf) {
 		// In the case there are no tokens then simply return nil
 		// for the token as well as the error as no tokens doesn't mean
@@ -199,7 +209,7 @@ func NextToken(pos int, buf []rune, charMap map[string]map[rune]rune,
 		if tkn, endPos, err := ProcessRegExpLiteral(pos, buf, charMap); tkn != nil {
 			return tkn, endPos, err
 		} else if tkn, endPos, err := ProcessTemplateLiteral(pos, buf, charMap); tkn != nil {
-			if tkn.Name == "TemplateMiddle" || tkn.Name == "TemplateTail" {
+			if tkn.Kind == TemplateMiddleToken || tkn.Kind == TemplateTailToken {
 				return tkn, endPos, err
 			}
 		}
@@ -208,23 +218,25 @@ func NextToken(pos int, buf []rune, charMap map[string]map[rune]rune,
 		if tkn, endPos, err := ProcessDivPunctuator(pos, buf); tkn != nil {
 			return tkn, endPos, err
 		} else if tkn, endPos, err := ProcessTemplateLiteral(pos, buf, charMap); tkn != nil {
-			if tkn.Name == "TemplateMiddle" || tkn.Name == "TemplateTail" {
+			if tkn.Kind == TemplateMiddleToken || tkn.Kind == TemplateTailToken {
 				return tkn, endPos, err
 			}
 		}
 		break
 	}
-	return nil, pos, fmt.Errorf("token error at %v for char %v for goal %v", pos, strconv.QuoteRune(c), goalName(goal))
+	return nil, pos, fmt.Errorf("token error for char %v for goal %v", strconv.QuoteRune(c), goalName(goal))
 }
 
 // ProcessCommonToken deals with attempting to parse the next set of sequence points as a
 // common token.
 func ProcessCommonToken(pos int, buf []rune, charMap map[string]map[rune]rune,
-	kwMap map[string]rune, frwMap map[string]rune, pMap map[string]rune) (*Token, int, error) {
+	kwMap map[string]TokenKind, frwMap map[string]TokenKind, pMap map[string]TokenKind) (*Token, int, error) {
 	c := buf[pos]
+	// A decimal point followed by a digit starts a numeric literal rather than being a punctuator.
+	isFraction := c == '.' && pos+1 < len(buf) && IsDecimalDigit(buf[pos+1], false)
 	if isIDStart, endofStart := IsStartOfIdentifier(c, pos, buf); isIDStart {
 		return ProcessIdentifier(buf[pos:endofStart], pos, endofStart, buf, kwMap, frwMap)
-	} else if tkn, endPos, err := ProcessPunctuator(pos, buf, pMap); tkn != nil {
+	} else if tkn, endPos, err := ProcessPunctuator(pos, buf, pMap); tkn != nil && !isFraction {
 		// Since checking for a punctuator requires a bit more complexity than say,
 		// a comment we are doing the checking and processing to generate a token in one.
 		return tkn, endPos, err
@@ -232,12 +244,13 @@ func ProcessCommonToken(pos int, buf []rune, charMap map[string]map[rune]rune,
 		return tkn, endPos, err
 	} else if tkn, endPos, err := ProcessStringLiteral(pos, buf, charMap); tkn != nil {
 		return tkn, endPos, err
-	} else if tkn, endPos, err := ProcessTemplateLiteral(pos, buf, charMap); tkn != nil {
-		if tkn.Name == "TemplateHead" || tkn.Name == "NoSubstitutionTemplate" {
+	} else if c == '`' {
+		// Only the template literals which start with a ` are common tokens.
+		if tkn, endPos, err := ProcessTemplateLiteral(pos, buf, charMap); tkn != nil {
 			return tkn, endPos, err
 		}
 	}
-	return nil, pos, fmt.Errorf("token error at %v for char %v", pos, strconv.QuoteRune(c))
+	return nil, pos, fmt.Errorf("token error for char %v", strconv.QuoteRune(c))
 }
 
 // Gets the string representation of a lexical symbol goal.
@@ -294,44 +307,22 @@ func LineTerminators() map[rune]rune {
 	}
 }
 
-func Keywords() map[string]rune {
-	return map[string]rune{
-		"await": 0, "break": 0, "case": 0,
-		"catch": 0, "class": 0, "const": 0, "continue": 0,
-		"debugger": 0, "default": 0, "delete": 0, "do": 0,
-		"else": 0, "export": 0, "extends": 0,
-		"finally": 0, "for": 0, "function": 0,
-		"if": 0, "import": 0, "in": 0, "instanceof": 0,
-		"new": 0, "return": 0, "super": 0, "switch": 0,
-		"this": 0, "throw": 0, "try": 0, "typeof": 0,
-		"var": 0, "void": 0, "while": 0, "with": 0, "yield": 0,
-	}
+// Keywords provides the kind of each keyword keyed by its source text.
+func Keywords() map[string]TokenKind {
+	return tokenKindsBetween(AwaitToken, YieldToken)
 }
 
-func FutureReservedWords() map[string]rune {
-	return map[string]rune{
-		"enum": 0,
-	}
+// FutureReservedWords provides the kind of each future reserved
+// word keyed by its source text.
+func FutureReservedWords() map[string]TokenKind {
+	return tokenKindsBetween(EnumToken, EnumToken)
 }
 
-func Punctuators() map[string]rune {
+// Punctuators provides the kind of each common punctuator keyed by its source text.
+func Punctuators() map[string]TokenKind {
 	// Right brace punctuator and div punctuators are treated
 	// differently and are not common tokens.
-	return map[string]rune{
-		"{": 0, "(": 0, ")": 0, "[": 0,
-		"]": 0, ".": 0, "...": 0, ";": 0,
-		",": 0, "<": 0, ">": 0, "<=": 0,
-		">=": 0, "==": 0, "!=": 0, "===": 0,
-		"!==": 0, "+": 0, "-": 0, "*": 0,
-		"%": 0, "**": 0, "++": 0, "--": 0,
-		"<<": 0, ">>": 0, ">>>": 0, "&": 0,
-		"|": 0, "^": 0, "!": 0, "~": 0,
-		"&&": 0, "||": 0, "?": 0, ":": 0,
-		"=": 0, "+=": 0, "-=": 0, "*=": 0,
-		"%=": 0, "**=": 0, "<<=": 0, ">>=": 0,
-		">>>=": 0, "&=": 0, "|=": 0, "^=": 0,
-		"=>": 0,
-	}
+	return tokenKindsBetween(LeftBraceToken, EqualsGreaterThanToken)
 }
 
 // ProcessWhiteSpace attempts to read the current code point
@@ -355,7 +346,7 @@ func ProcessLineTerminator(pos int, buf []rune) (*Token, int, error) {
 		endPos++
 	}
 	tkn := &Token{
-		Name:  "LineTerminator",
+		Kind:  LineTerminatorToken,
 		Value: lineTerminator,
 		Pos:   pos,
 	}
@@ -399,7 +390,7 @@ func ProcessComment(pos int, buf []rune, charMap map[string]map[rune]rune, comme
 			// Add to 2 to account for the comment block terminals
 			// and that the end of the position is the start of the next token (or non-token).
 			return &Token{
-				Name:  "LineTerminator",
+				Kind:  LineTerminatorToken,
 				Value: comment + "*/",
 				Pos:   startPos,
 			}, pos + 2, nil
@@ -409,19 +400,19 @@ func ProcessComment(pos int, buf []rune, charMap map[string]map[rune]rune, comme
 	return nil, -1, fmt.Errorf("reached end of buffer and comment token was not closed")
 }
 
-func ProcessPunctuator(pos int, buf []rune, pMap map[string]rune) (*Token, int, error) {
-	liFloat := math.Min(float64(pos+MaxPunctuatorLength), float64(len(buf)-1))
-	lastIndex := int(liFloat)
-	candidate := string(buf[pos])
-	if lastIndex > pos {
-		candidate = string(buf[pos:lastIndex])
+func ProcessPunctuator(pos int, buf []rune, pMap map[string]TokenKind) (*Token, int, error) {
+	lastIndex := pos + MaxPunctuatorLength
+	if lastIndex > len(buf) {
+		lastIndex = len(buf)
 	}
+	candidate := string(buf[pos:lastIndex])
 	punctuator := candidate
+	var kind TokenKind
 	match := false
 	i := len(candidate)
 	for i > 0 && !match {
 		punctuator = candidate[0:i]
-		_, match = pMap[punctuator]
+		kind, match = pMap[punctuator]
 		if !match {
 			i--
 		}
@@ -429,7 +420,7 @@ func ProcessPunctuator(pos int, buf []rune, pMap map[string]rune) (*Token, int,
 	if match {
 		endPos := pos + len(punctuator)
 		return &Token{
-			Name:  "Punctuator",
+			Kind:  kind,
 			Value: punctuator,
 			Pos:   pos,
 		}, endPos, nil
@@ -445,14 +436,14 @@ func ProcessDivPunctuator(pos int, buf []rune) (*Token, int, error) {
 			next := buf[pos+1]
 			if next == '=' {
 				return &Token{
-					Name:  "DivPunctuator",
+					Kind:  SlashEqualsToken,
 					Value: "/=",
 					Pos:   pos,
 				}, pos + 2, nil
 			}
 		}
 		return &Token{
-			Name:  "DivPunctuator",
+			Kind:  SlashToken,
 			Value: "/",
 			Pos:   pos,
 		}, pos + 1, nil
@@ -463,7 +454,7 @@ func ProcessDivPunctuator(pos int, buf []rune) (*Token, int, error) {
 func ProcessRightBracePunctuator(pos int, buf []rune) (*Token, int, error) {
 	if buf[pos] == '}' {
 		return &Token{
-			Name:  "RightBracePunctuator",
+			Kind:  RightBraceToken,
 			Value: "}",
 			Pos:   pos,
 		}, pos + 1, nil
@@ -473,26 +464,26 @@ func ProcessRightBracePunctuator(pos int, buf []rune) (*Token, int, error) {
 
 // ProcessReservedWord takes the value of an identifier token and transforms
 // it into a reserved word token if there is a reserved word match.
-func ProcessReservedWord(identTkn *Token, kwMap map[string]rune, frwMap map[string]rune) {
+func ProcessReservedWord(identTkn *Token, kwMap map[string]TokenKind, frwMap map[string]TokenKind) {
 	reservedWord := identTkn.Value
-	_, isKeyword := kwMap[reservedWord]
+	keyword, isKeyword := kwMap[reservedWord]
 	if !isKeyword {
-		_, isFutureReservedWord := frwMap[reservedWord]
+		futureReservedWord, isFutureReservedWord := frwMap[reservedWord]
 		if !isFutureReservedWord {
 			isBooleanLiteral := reservedWord == "false" || reservedWord == "true"
 			if !isBooleanLiteral {
 				isNullLiteral := reservedWord == "null"
 				if isNullLiteral {
-					identTkn.Name = "NullLiteral"
+					identTkn.Kind = NullLiteralToken
 				}
 			} else {
-				identTkn.Name = "BooleanLiteral"
+				identTkn.Kind = BooleanLiteralToken
 			}
 		} else {
-			identTkn.Name = "FutureReservedWord"
+			identTkn.Kind = futureReservedWord
 		}
 	} else {
-		identTkn.Name = "Keyword"
+		identTkn.Kind = keyword
 	}
 }
 
@@ -500,13 +491,16 @@ func ProcessReservedWord(identTkn *Token, kwMap map[string]rune, frwMap map[stri
 // sequence of code points as an identifier token.
 func ProcessIdentifier(
 	identifier []rune, startPos int, fromPos int, buf []rune,
-	kwMap map[string]rune, frwMap map[string]rune,
+	kwMap map[string]TokenKind, frwMap map[string]TokenKind,
 ) (*Token, int, error) {
 	// First ensure that what we have of an identifier so far is a valid
 	// code point in the case it is a unicode escape sequence.
 	if len(identifier) > 1 {
 		codePoints := DecodeUnicodeEscSeq(identifier)
-		isCodePointPart, _ := IsStartOfIdentifier(codePoints[0], 0, codePoints)
+		isCodePointPart := false
+		if len(codePoints) > 0 {
+			isCodePointPart, _ = IsStartOfIdentifier(codePoints[0], 0, codePoints)
+		}
 		if isCodePointPart {
 			identifier = []rune{}
 			identifier = append(identifier, codePoints...)
@@ -515,9 +509,12 @@ func ProcessIdentifier(
 				"unicode escape sequence must represent a valid IdentifierStart")
 		}
 	}
+	// The identifier is copied as it is built up from the code points which
+	// follow it, appending to the slice of the input would overwrite them.
+	identifier = append([]rune{}, identifier...)
 	reachedEnd := false
 	i := fromPos
-	idEndPos := fromPos + 1
+	idEndPos := fromPos
 	for !reachedEnd && i < len(buf) {
 		if isPart, endPos := IsIdentifierPart(i, buf); isPart {
 			if endPos > i+1 {
@@ -538,22 +535,21 @@ func ProcessIdentifier(
 			} else {
 				identifier = append(identifier, buf[i])
 			}
-			i++
+			i = endPos
 			idEndPos = endPos
 		} else {
 			reachedEnd = true
 		}
 	}
-	if reachedEnd {
-		tkn := &Token{
-			Name:  "IdentifierName",
-			Value: string(identifier),
-			Pos:   startPos,
-		}
-		ProcessReservedWord(tkn, kwMap, frwMap)
-		return tkn, idEndPos, nil
+	// The identifier ends at the first code point which is not an
+	// identifier part or at the end of the input.
+	tkn := &Token{
+		Kind:  IdentifierNameToken,
+		Value: string(identifier),
+		Pos:   startPos,
 	}
-	return nil, -1, fmt.Errorf("identifier should terminate")
+	ProcessReservedWord(tkn, kwMap, frwMap)
+	return tkn, idEndPos, nil
 }
 
 // ProcessNumericLiteral attempts to process the next set of
@@ -583,13 +579,15 @@ func ProcessDecimalLiteral(pos int, buf []rune) (*Token, int, error) {
 	currentProd := 1
 	for !reachedEnd && i < len(buf) {
 		if i == pos {
-			// Allow for zero only when followed directly by
-			// an exponent or decimal point.
-			next := '0'
+			// Allow for zero only when it is not followed by another digit
+			// or the prefix of a binary, octal or hexadecimal integer literal.
+			next := ' '
 			if i+1 < len(buf) {
 				next = buf[i+1]
 			}
-			if IsDecimalDigit(buf[i], true) || buf[i] == '0' && next == '.' {
+			isZero := buf[i] == '0' && !IsDecimalDigit(next, false) &&
+				next != 'b' && next != 'B' && next != 'o' && next != 'O' && next != 'x' && next != 'X'
+			if IsDecimalDigit(buf[i], true) || isZero {
 				intLiteral += string(buf[i])
 			} else if buf[i] == '.' {
 				currentProd = 2
@@ -610,7 +608,7 @@ func ProcessDecimalLiteral(pos int, buf []rune) (*Token, int, error) {
 			((intLiteral != "" && currentProd == 1) ||
 				(decimalDigits != "" && currentProd == 2)) {
 			currentProd = 3
-		} else if (buf[i] == '-' || buf[i] == '+') && exponentPart == "" {
+		} else if (buf[i] == '-' || buf[i] == '+') && currentProd == 3 && exponentPart == "" {
 			exponentPart += string(buf[i])
 		} else {
 			reachedEnd = true
@@ -631,7 +629,7 @@ func ProcessDecimalLiteral(pos int, buf []rune) (*Token, int, error) {
 		value += "e" + exponentPart
 	}
 	tkn := &Token{
-		Name:  "DecimalLiteral",
+		Kind:  DecimalLiteralToken,
 		Value: value,
 		Pos:   pos,
 	}
@@ -684,7 +682,7 @@ func ProcessBinaryIntegerLiteral(pos int, buf []rune) (*Token, int, error) {
 	}
 	if binaryValue != "" {
 		return &Token{
-			Name:  "BinaryIntegerLiteral",
+			Kind:  BinaryIntegerLiteralToken,
 			Value: binaryValue,
 			Pos:   pos,
 		}, i, nil
@@ -723,7 +721,7 @@ func ProcessOctalIntegerLiteral(pos int, buf []rune) (*Token, int, error) {
 	}
 	if octalValue != "" {
 		return &Token{
-			Name:  "OctalIntegerLiteral",
+			Kind:  OctalIntegerLiteralToken,
 			Value: octalValue,
 			Pos:   pos,
 		}, i, nil
@@ -769,7 +767,7 @@ func ProcessHexIntegerLiteral(pos int, buf []rune) (*Token, int, error) {
 	}
 	if hexValue != "" {
 		return &Token{
-			Name:  "HexIntegerLiteral",
+			Kind:  HexIntegerLiteralToken,
 			Value: hexValue,
 			Pos:   pos,
 		}, i, nil
@@ -817,7 +815,7 @@ func ProcessStringLiteral(pos int, buf []rune, charMap map[string]map[rune]rune)
 		return nil, -1, fmt.Errorf("string literals must have a terminating quote")
 	}
 	return &Token{
-		Name:  "StringLiteral",
+		Kind:  StringLiteralToken,
 		Value: stringVal,
 		Pos:   pos,
 	}, i, nil
@@ -913,6 +911,9 @@ func IsLineContinuation(pos int, buf []rune, charMap map[string]map[rune]rune) (
 // IsLineTerminatorSequence determines whether the next set of
 // characters form a line terminator sequence.
 func IsLineTerminatorSequence(pos int, buf []rune, charMap map[string]map[rune]rune) (int, bool) {
+	if pos >= len(buf) {
+		return pos, false
+	}
 	endPos := pos + 1
 	isLTSeq := false
 	if pos+1 < len(buf) && (buf[pos] == '\u000D' && buf[pos+1] == '\u000A') {
@@ -954,7 +955,7 @@ func ProcessRegExpLiteral(pos int, buf []rune, charMap map[string]map[rune]rune)
 	if !isREBody {
 		return nil, pos, nil
 	}
-	if buf[nextPos] != '/' {
+	if nextPos >= len(buf) || buf[nextPos] != '/' {
 		return nil, pos, fmt.Errorf("Invalid regular expression literal missing closing /")
 	}
 	reBody := string(buf[(pos + 1):nextPos])
@@ -964,7 +965,7 @@ func ProcessRegExpLiteral(pos int, buf []rune, charMap map[string]map[rune]rune)
 	// is not a valid flag then we finish the regexp literal before then.
 	reFlags := string(buf[prevPos:nextPos])
 	return &Token{
-		Name:  "RegularExpressionLiteral",
+		Kind:  RegularExpressionLiteralToken,
 		Value: "/" + reBody + "/" + reFlags,
 		Pos:   pos,
 	}, nextPos, nil
@@ -973,6 +974,9 @@ func ProcessRegExpLiteral(pos int, buf []rune, charMap map[string]map[rune]rune)
 // IsRegExpBody determines whether the next sequence of
 // code points make up a regular expression literal body.
 func IsRegExpBody(pos int, buf []rune, charMap map[string]map[rune]rune) (bool, int) {
+	if pos >= len(buf) {
+		return false, pos
+	}
 	// First ensure the first character is valid.
 	firstIsValid, nextPos := IsFirstRegExpChar(pos, buf, charMap)
 	if !firstIsValid {
@@ -1086,7 +1090,7 @@ func IsRegExpClass(pos int, buf []rune, charMap map[string]map[rune]rune) (bool,
 			return true, pos + 2
 		}
 		isRegExpClassChars, nextPos := IsRegExpClassChars(pos+1, buf, charMap)
-		if isRegExpClassChars && buf[nextPos] == ']' {
+		if isRegExpClassChars && nextPos < len(buf) && buf[nextPos] == ']' {
 			return true, nextPos + 1
 		}
 	}
@@ -1131,7 +1135,7 @@ func ProcessTemplateLiteral(pos int, buf []rune, charMap map[string]map[rune]run
 		nextPos := ReadTemplateChars(pos+1, buf, charMap)
 		if nextPos < len(buf) && buf[nextPos] == '`' {
 			return &Token{
-				Name:  "NoSubstitionTemplate",
+				Kind:  NoSubstitutionTemplateToken,
 				Value: string(buf[pos+1 : nextPos]),
 				Pos:   pos,
 			}, nextPos + 1, nil
@@ -1142,7 +1146,7 @@ func ProcessTemplateLiteral(pos int, buf []rune, charMap map[string]map[rune]run
 				value = string(buf[pos+1 : nextPos-1])
 			}
 			return &Token{
-				Name:  "TemplateHead",
+				Kind:  TemplateHeadToken,
 				Value: value,
 				Pos:   pos,
 			}, nextPos + 2, nil
@@ -1151,7 +1155,7 @@ func ProcessTemplateLiteral(pos int, buf []rune, charMap map[string]map[rune]run
 		nextPos := ReadTemplateChars(pos+1, buf, charMap)
 		if nextPos < len(buf) && buf[nextPos] == '`' {
 			return &Token{
-				Name:  "TemplateTail",
+				Kind:  TemplateTailToken,
 				Value: string(buf[pos+1 : nextPos]),
 				Pos:   pos,
 			}, nextPos + 1, nil
@@ -1162,7 +1166,7 @@ func ProcessTemplateLiteral(pos int, buf []rune, charMap map[string]map[rune]run
 				value = string(buf[pos+1 : nextPos])
 			}
 			return &Token{
-				Name:  "TemplateMiddle",
+				Kind:  TemplateMiddleToken,
 				Value: value,
 				Pos:   pos,
 			}, nextPos + 1, nil
@@ -10,7 +10,7 @@ type testData struct {
 	extra         map[string]interface{}
 }
 
-func processTest(t *testing.T, data []testData, tokenType string, process func(int, []rune) (*Token, int, error)) {
+func processTest(t *testing.T, data []testData, kind TokenKind, process func(int, []rune) (*Token, int, error)) {
 	for i := 0; i < len(data); i++ {
 		tkn, endPos, err := process(0, data[i].buf)
 		if data[i].shouldSucceed && err != nil {
@@ -18,7 +18,7 @@ func processTest(t *testing.T, data []testData, tokenType string, process func(i
 		}
 		if data[i].shouldSucceed {
 			if tkn == nil {
-				t.Errorf("Expected valid %v token but got nil", tokenType)
+				t.Errorf("Expected valid %v token but got nil", kind)
 			}
 			if endPos != data[i].endPos {
 				t.Errorf(
@@ -34,8 +34,8 @@ func processTest(t *testing.T, data []testData, tokenType string, process func(i
 					tkn.Value,
 				)
 			}
-			if tkn.Name != tokenType {
-				t.Errorf("Expected token to be %v but got %v", tokenType, tkn.Name)
+			if tkn.Kind != kind {
+				t.Errorf("Expected token to be %v but got %v", kind, tkn.Kind)
 			}
 		} else {
 			if tkn != nil {
@@ -47,8 +47,8 @@ func processTest(t *testing.T, data []testData, tokenType string, process func(i
 
 func processTestWithCharMap(
 	t *testing.T, data []testData, tokenType string,
-	charMap map[string]rune,
-	process func(int, []rune, map[string]rune) (*Token, int, error),
+	charMap map[string]TokenKind,
+	process func(int, []rune, map[string]TokenKind) (*Token, int, error),
 ) {
 	for i := 0; i < len(data); i++ {
 		tkn, endPos, err := process(0, data[i].buf, charMap)
@@ -73,8 +73,8 @@ func processTestWithCharMap(
 					tkn.Value,
 				)
 			}
-			if tkn.Name != tokenType {
-				t.Errorf("Expected token to be %v but got %v", tokenType, tkn.Name)
+			if tkn.Kind != charMap[data[i].expected] {
+				t.Errorf("Expected token to be %v but got %v", charMap[data[i].expected], tkn.Kind)
 			}
 		} else {
 			if tkn != nil {
@@ -85,7 +85,7 @@ func processTestWithCharMap(
 }
 
 func processTestWithCharMaps(
-	t *testing.T, data []testData, tokenType string,
+	t *testing.T, data []testData, kind TokenKind,
 	charMap map[string]map[rune]rune,
 	process func(int, []rune, map[string]map[rune]rune) (*Token, int, error),
 ) {
@@ -96,7 +96,7 @@ func processTestWithCharMaps(
 		}
 		if data[i].shouldSucceed {
 			if tkn == nil {
-				t.Errorf("Expected valid %v token but got nil", tokenType)
+				t.Errorf("Expected valid %v token but got nil", kind)
 			}
 			if endPos != data[i].endPos {
 				t.Errorf(
@@ -112,8 +112,8 @@ func processTestWithCharMaps(
 					tkn.Value,
 				)
 			}
-			if tkn.Name != tokenType {
-				t.Errorf("Expected token to be %v but got %v", tokenType, tkn.Name)
+			if tkn.Kind != kind {
+				t.Errorf("Expected token to be %v but got %v", kind, tkn.Kind)
 			}
 		} else {
 			if tkn != nil {
@@ -137,10 +137,10 @@ func processTestForComments(
 		}
 		if data[i].shouldSucceed {
 			if tkn != nil && data[i].extra["commentType"].(CommentType) != MultiLineComment {
-				t.Errorf("Expected a nil token but got a %v token", tkn.Name)
-			} else if tkn != nil && tkn.Name != "LineTerminator" &&
+				t.Errorf("Expected a nil token but got a %v token", tkn.Kind)
+			} else if tkn != nil && tkn.Kind != LineTerminatorToken &&
 				data[i].extra["commentType"].(CommentType) == MultiLineComment {
-				t.Errorf("Expected LineTerminator token but got %v token", tkn.Name)
+				t.Errorf("Expected LineTerminator token but got %v token", tkn.Kind)
 			}
 			if endPos != data[i].endPos {
 				t.Errorf(
@@ -156,8 +156,8 @@ func processTestForComments(
 					tkn.Value,
 				)
 			}
-			if tkn != nil && tkn.Name != "LineTerminator" {
-				t.Errorf("Expected token to be LineTerminator but got %v", tkn.Name)
+			if tkn != nil && tkn.Kind != LineTerminatorToken {
+				t.Errorf("Expected token to be LineTerminator but got %v", tkn.Kind)
 			}
 		} else {
 			if tkn != nil {
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"testing"
+	"unicode"
 )
 
 func TestProcessLineTerminator(t *testing.T) {
@@ -9,7 +10,7 @@ func TestProcessLineTerminator(t *testing.T) {
 		{true, []rune{'\u000D', '\u000A'}, 2, "\u000D\u000A", nil},
 		{true, append([]rune{'\u000D'}, []rune("Next line")...), 1, "\u000D", nil},
 	}
-	processTest(t, lineTerminatorData, "LineTerminator", ProcessLineTerminator)
+	processTest(t, lineTerminatorData, LineTerminatorToken, ProcessLineTerminator)
 }
 
 func TestProcessComment(t *testing.T) {
@@ -44,6 +45,7 @@ func TestProcessPunctuator(t *testing.T) {
 		{true, []rune("=== thorough 3"), 3, "===", nil},
 		{true, []rune("<< terrible 2"), 2, "<<", nil},
 		{true, []rune("% the one"), 1, "%", nil},
+		{true, []rune(">>="), 3, ">>=", nil},
 		{false, []rune("The one without a punctuator"), 0, "", nil},
 	}
 	pMap := Punctuators()
@@ -57,6 +59,70 @@ func TestIsStartOfIdentifier(t *testing.T) {
 }
 
 func TestProcessIdentifier(t *testing.T) {
+	// An identifier ends at the end of the input as well as
+	// at the first code point which is not an identifier part.
+	for input, expected := range map[string]*Token{
+		"abc":  {Kind: IdentifierNameToken, Value: "abc"},
+		"ab c": {Kind: IdentifierNameToken, Value: "ab"},
+		"a":    {Kind: IdentifierNameToken, Value: "a"},
+		"a+b":  {Kind: IdentifierNameToken, Value: "a"},
+		"if":   {Kind: IfToken, Value: "if"},
+	} {
+		buf := []rune(input)
+		tkn, endPos, err := ProcessIdentifier(buf[:1], 0, 1, buf, Keywords(), FutureReservedWords())
+		if err != nil || tkn == nil || tkn.Kind != expected.Kind || tkn.Value != expected.Value ||
+			endPos != len([]rune(expected.Value)) {
+			t.Errorf("Expected %q to be lexed as %+v but got %+v, %v, %v", input, expected, tkn, endPos, err)
+		}
+	}
+}
+
+func TestProcessIdentifierEscapes(t *testing.T) {
+	// The escape sequences are replaced by the code points they represent
+	// without overwriting the input the identifier is lexed from.
+	for input, expected := range map[string]string{
+		"a\\u0062c":         "abc",
+		"a\\u{62}c":         "abc",
+		"\\u{61}bc":         "abc",
+		"\\u0061\\u{1F600}": "",
+	} {
+		buf := []rune(input)
+		_, endOfStart := IsStartOfIdentifier(buf[0], 0, buf)
+		tkn, endPos, err := ProcessIdentifier(buf[:endOfStart], 0, endOfStart, buf, Keywords(), FutureReservedWords())
+		if expected == "" && err == nil {
+			t.Errorf("Expected lexing %q to fail as an identifier can't contain the code point", input)
+		} else if expected != "" && (err != nil || tkn.Value != expected || endPos != len(buf)) {
+			t.Errorf("Expected %q to be lexed as the identifier %q but got %+v, %v, %v", input, expected, tkn, endPos, err)
+		}
+		if string(buf) != input {
+			t.Errorf("Expected lexing %q to leave the input unchanged but it is now %q", input, string(buf))
+		}
+	}
+}
+
+func TestNextTokenCutOff(t *testing.T) {
+	// A token which is cut off at any point fails to be lexed or
+	// is lexed as a shorter token, rather than lexing out of range.
+	charMap := map[string]map[rune]rune{"whiteSpace": WhiteSpaceChars(), "lineTerminators": LineTerminators()}
+	inputs := []string{
+		"'a\\u{12}b\\x41\\\n\\u0041'", "\"a\\\r\nb\"", "/a[b\\]c]\\/d/gi", "`a${b}c\\u0041\\\n`",
+		"}a\\u{41}`", "a\\u0062\\u{63}", "/* a\n */ // b\n", "0x1F 0b1 0o7 1.5e-3",
+	}
+	goals := []LexicalGoalSymbol{InputElementDiv, InputElementRegExp, InputElementRegExpOrTemplateTail, InputElementTemplateTail}
+	for _, input := range inputs {
+		buf := []rune(input)
+		for end := 1; end <= len(buf); end++ {
+			for _, goal := range goals {
+				for pos := 0; pos < end; {
+					_, nextPos, err := NextToken(pos, buf[:end:end], charMap, Punctuators(), Keywords(), FutureReservedWords(), goal)
+					if err != nil || nextPos <= pos {
+						break
+					}
+					pos = nextPos
+				}
+			}
+		}
+	}
 }
 
 func TestProcessDecimalLiteral(t *testing.T) {
@@ -68,8 +134,13 @@ func TestProcessDecimalLiteral(t *testing.T) {
 		{true, []rune("35e+32"), 6, "35e+32", nil},
 		{true, []rune(".75e-6021"), 9, ".75e-6021", nil},
 		{true, []rune("0.49"), 4, "0.49", nil},
+		{true, []rune("0"), 1, "0", nil},
+		{true, []rune("0;"), 1, "0", nil},
+		{true, []rune("1-2"), 1, "1", nil},
+		{true, []rune("1+2"), 1, "1", nil},
+		{false, []rune("0x1F"), 0, "", nil},
 	}
-	processTest(t, decimalData, "DecimalLiteral", ProcessDecimalLiteral)
+	processTest(t, decimalData, DecimalLiteralToken, ProcessDecimalLiteral)
 }
 
 func TestProcessBinaryIntegerLiteral(t *testing.T) {
@@ -82,7 +153,7 @@ func TestProcessBinaryIntegerLiteral(t *testing.T) {
 		{false, []rune("0"), 0, "", nil},
 		{false, []rune("ab"), 0, "", nil},
 	}
-	processTest(t, binaryIntData, "BinaryIntegerLiteral", ProcessBinaryIntegerLiteral)
+	processTest(t, binaryIntData, BinaryIntegerLiteralToken, ProcessBinaryIntegerLiteral)
 }
 
 func TestProcessOctalIntegerLiteral(t *testing.T) {
@@ -95,7 +166,7 @@ func TestProcessOctalIntegerLiteral(t *testing.T) {
 		{false, []rune("0"), 0, "", nil},
 		{false, []rune("ao"), 0, "", nil},
 	}
-	processTest(t, octalIntData, "OctalIntegerLiteral", ProcessOctalIntegerLiteral)
+	processTest(t, octalIntData, OctalIntegerLiteralToken, ProcessOctalIntegerLiteral)
 }
 
 func TestProcessHexIntegerLiteral(t *testing.T) {
@@ -108,7 +179,7 @@ func TestProcessHexIntegerLiteral(t *testing.T) {
 		{false, []rune("0"), 0, "", nil},
 		{false, []rune("ax"), 0, "", nil},
 	}
-	processTest(t, hexIntData, "HexIntegerLiteral", ProcessHexIntegerLiteral)
+	processTest(t, hexIntData, HexIntegerLiteralToken, ProcessHexIntegerLiteral)
 }
 
 func TestProcessStringLiteral(t *testing.T) {
@@ -127,7 +198,7 @@ func TestProcessStringLiteral(t *testing.T) {
 	charMap := map[string]map[rune]rune{
 		"lineTerminators": LineTerminators(),
 	}
-	processTestWithCharMaps(t, stringData, "StringLiteral", charMap, ProcessStringLiteral)
+	processTestWithCharMaps(t, stringData, StringLiteralToken, charMap, ProcessStringLiteral)
 }
 
 func TestProcessRegExpLiteral(t *testing.T) {
@@ -145,7 +216,7 @@ func TestProcessRegExpLiteral(t *testing.T) {
 	charMap := map[string]map[rune]rune{
 		"lineTerminators": LineTerminators(),
 	}
-	processTestWithCharMaps(t, regexpData, "RegularExpressionLiteral", charMap, ProcessRegExpLiteral)
+	processTestWithCharMaps(t, regexpData, RegularExpressionLiteralToken, charMap, ProcessRegExpLiteral)
 }
 
 func TestProcessTemplateLiteralNoSubstitutions(t *testing.T) {
@@ -159,7 +230,7 @@ func TestProcessTemplateLiteralNoSubstitutions(t *testing.T) {
 	charMap := map[string]map[rune]rune{
 		"lineTerminators": LineTerminators(),
 	}
-	processTestWithCharMaps(t, templateData, "NoSubstitionTemplate", charMap, ProcessTemplateLiteral)
+	processTestWithCharMaps(t, templateData, NoSubstitutionTemplateToken, charMap, ProcessTemplateLiteral)
 }
 
 func TestProcessTemplateLiteralHead(t *testing.T) {
@@ -171,7 +242,7 @@ func TestProcessTemplateLiteralHead(t *testing.T) {
 	charMap := map[string]map[rune]rune{
 		"lineTerminators": LineTerminators(),
 	}
-	processTestWithCharMaps(t, templateData, "TemplateHead", charMap, ProcessTemplateLiteral)
+	processTestWithCharMaps(t, templateData, TemplateHeadToken, charMap, ProcessTemplateLiteral)
 }
 
 func TestProcessTemplateLiteralMiddle(t *testing.T) {
@@ -184,7 +255,7 @@ func TestProcessTemplateLiteralMiddle(t *testing.T) {
 	charMap := map[string]map[rune]rune{
 		"lineTerminators": LineTerminators(),
 	}
-	processTestWithCharMaps(t, templateData, "TemplateMiddle", charMap, ProcessTemplateLiteral)
+	processTestWithCharMaps(t, templateData, TemplateMiddleToken, charMap, ProcessTemplateLiteral)
 }
 
 func TestProcessTemplateLiteralTail(t *testing.T) {
@@ -196,5 +267,25 @@ func TestProcessTemplateLiteralTail(t *testing.T) {
 	charMap := map[string]map[rune]rune{
 		"lineTerminators": LineTerminators(),
 	}
-	processTestWithCharMaps(t, templateData, "TemplateTail", charMap, ProcessTemplateLiteral)
+	processTestWithCharMaps(t, templateData, TemplateTailToken, charMap, ProcessTemplateLiteral)
+}
+
+func TestIDTables(t *testing.T) {
+	if unicode.Version != idTablesUnicodeVersion {
+		t.Skipf("The tables are generated from Unicode %v but the toolchain provides Unicode %v",
+			idTablesUnicodeVersion, unicode.Version)
+	}
+	// The definitions of the properties the tables are generated from.
+	isPattern := func(c rune) bool { return unicode.In(c, unicode.Pattern_Syntax, unicode.Pattern_White_Space) }
+	isIDStart := func(c rune) bool {
+		return (unicode.IsLetter(c) || unicode.In(c, unicode.Nl, unicode.Other_ID_Start)) && !isPattern(c)
+	}
+	for c := rune(0); c <= unicode.MaxRune; c++ {
+		isIDContinue := (isIDStart(c) || unicode.In(c, unicode.Other_ID_Continue,
+			unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc)) && !isPattern(c)
+		if IsIDStart(c) != isIDStart(c) || IsIDContinue(c) != isIDContinue {
+			t.Fatalf("Expected %U to be an ID_Start code point %v and an ID_Continue code point %v",
+				c, isIDStart(c), isIDContinue)
+		}
+	}
 }
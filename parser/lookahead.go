@@ -0,0 +1,198 @@
+package parser
+
+// Matches determines whether the given token is an instance of the terminal symbol,
+// tokens match the terminal of their kind while IdentifierName tokens also match
+// the terminal named by their value, such as the contextual keyword let.
+func (t *ParseTables) Matches(token *Token, terminal Symbol) bool {
+	if terminal == 0 || int(terminal) >= len(t.SymbolNames) {
+		return false
+	}
+	t.loadTerminals()
+	return (int(token.Kind) < len(t.KindTerminals) && t.KindTerminals[token.Kind] == terminal) ||
+		(token.Kind == IdentifierNameToken && t.terminals[token.Value] == terminal)
+}
+
+// TerminalsOf provides the terminal symbols the given token is an instance of
+// in the order the parse table is consulted with them, the terminal named by the
+// value of an IdentifierName token comes before the terminal of its kind.
+// A nil token provides the end of input.
+func (t *ParseTables) TerminalsOf(token *Token) []Symbol {
+	if token == nil {
+		return []Symbol{t.EndOfInput}
+	}
+	t.loadTerminals()
+	terminals := []Symbol{}
+	if token.Kind == IdentifierNameToken {
+		if terminal, exists := t.terminals[token.Value]; exists {
+			terminals = append(terminals, terminal)
+		}
+	}
+	if int(token.Kind) < len(t.KindTerminals) && t.KindTerminals[token.Kind] != 0 {
+		terminals = append(terminals, t.KindTerminals[token.Kind])
+	}
+	return terminals
+}
+
+// Populates the terminals matched by the value of IdentifierName tokens, along with
+// the terminal of each token kind when the tables were not generated with them. The lexical
+// goal of each symbol and the number of tokens the predicates look ahead at are populated
+// for parsing a token stream.
+func (t *ParseTables) loadTerminals() {
+	t.terminalsOnce.Do(func() {
+		kindTerminals := make([]Symbol, tokenKindCount)
+		t.terminals = map[string]Symbol{}
+		t.goals = make([]LexicalGoalSymbol, len(t.SymbolNames))
+		for i := t.NonTerminalCount + 1; i < len(t.SymbolNames); i++ {
+			kinds := TerminalKinds(t.SymbolNames[i])
+			for _, kind := range kinds {
+				kindTerminals[kind] = Symbol(i)
+			}
+			if len(kinds) == 0 && Symbol(i) != t.EndOfInput {
+				t.terminals[t.SymbolNames[i]] = Symbol(i)
+			}
+			t.goals[i] = goalOf(t.SymbolNames[i])
+		}
+		if t.KindTerminals == nil {
+			t.KindTerminals = kindTerminals
+		}
+		for nonTerminal := 1; nonTerminal <= t.NonTerminalCount && nonTerminal < len(t.goals); nonTerminal++ {
+			expected := []string{}
+			for terminal := range t.ParseTable[Symbol(nonTerminal)] {
+				if int(terminal) < len(t.SymbolNames) {
+					expected = append(expected, t.SymbolNames[terminal])
+				}
+			}
+			t.goals[nonTerminal] = goalOf(expected...)
+		}
+		t.lookahead = 1
+		for _, rule := range t.Rules {
+			for _, predicate := range rule.Predicates {
+				for _, sequence := range predicate.Exclude {
+					if len(sequence) > t.lookahead {
+						t.lookahead = len(sequence)
+					}
+				}
+			}
+		}
+	})
+}
+
+// Provides the lexical goal symbol of the tokens where one of the given terminals
+// is expected, the goals which allow a regular expression literal or the continuation
+// of a template are only used where one of those can appear.
+func goalOf(terminals ...string) LexicalGoalSymbol {
+	regExp, templateTail := false, false
+	for _, terminal := range terminals {
+		switch terminal {
+		case "RegularExpressionLiteral":
+			regExp = true
+		case "TemplateMiddle", "TemplateTail":
+			templateTail = true
+		}
+	}
+	switch {
+	case regExp && templateTail:
+		return InputElementRegExpOrTemplateTail
+	case regExp:
+		return InputElementRegExp
+	case templateTail:
+		return InputElementTemplateTail
+	}
+	return InputElementDiv
+}
+
+// TerminalNames provides the names of the terminals of the syntactic grammar the given
+// token could be an instance of, in the same order as TerminalsOf. Names which are not
+// terminals of a particular grammar are included so they can be matched against any grammar.
+func TerminalNames(token *Token) []string {
+	names := []string{}
+	if token.Kind == IdentifierNameToken {
+		names = append(names, token.Value)
+	}
+	names = append(names, token.Kind.String())
+	if terminal := token.Kind.Terminal(); terminal != token.Kind.String() {
+		names = append(names, terminal)
+	}
+	return names
+}
+
+// Excludes determines whether the upcoming tokens start with one of the sequences
+// excluded by the predicate, line terminator tokens are skipped but a terminal marked
+// with NoLineTerminator is not matched when a line terminator precedes it.
+func (t *ParseTables) Excludes(predicate *LookaheadPredicate, tokens []*Token) bool {
+	excluded := false
+	i := 0
+	for !excluded && i < len(predicate.Exclude) {
+		excluded = t.startsWith(tokens, predicate.Exclude[i])
+		i++
+	}
+	return excluded
+}
+
+// Determines whether the tokens start with the given sequence of terminals.
+func (t *ParseTables) startsWith(tokens []*Token, sequence []PredicateTerminal) bool {
+	matches := len(sequence) > 0
+	position := 0
+	i := 0
+	for matches && i < len(sequence) {
+		precededByLineTerminator := false
+		for position < len(tokens) && tokens[position].Kind == LineTerminatorToken {
+			precededByLineTerminator = true
+			position++
+		}
+		matches = position < len(tokens) && t.Matches(tokens[position], sequence[i].Symbol) &&
+			!(sequence[i].NoLineTerminator && precededByLineTerminator)
+		position++
+		i++
+	}
+	return matches
+}
+
+// Predict provides the index of the rule to expand for the given non-terminal when
+// the upcoming tokens are those provided, a rule is only chosen when none of the
+// lookahead predicates at its start, or at the start of the rules it begins with,
+// exclude the tokens. Otherwise its fallbacks are tried in order before moving on
+// to the next terminal the token is an instance of.
+func (t *ParseTables) Predict(nonTerminal Symbol, tokens []*Token) (int, bool) {
+	return t.predict(nonTerminal, tokens, false)
+}
+
+// Provides the rule to expand for the non-terminal, the rules each candidate starts
+// with are checked when asked to or when there is another rule to choose,
+// otherwise they are checked as they are expanded.
+func (t *ParseTables) predict(nonTerminal Symbol, tokens []*Token, nested bool) (int, bool) {
+	var next *Token
+	position := skipLineTerminators(tokens, 0)
+	if position < len(tokens) {
+		next = tokens[position]
+	}
+	for _, terminal := range t.TerminalsOf(next) {
+		if ruleIndex, exists := t.ParseTable[nonTerminal][terminal]; exists {
+			fallbacks := t.Fallbacks[nonTerminal][terminal]
+			candidates := append([]int{ruleIndex}, fallbacks...)
+			for _, candidate := range candidates {
+				if !t.rejects(t.Rules[candidate], tokens, nested || len(fallbacks) > 0) {
+					return candidate, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// Determines whether the rule can not be parsed from the tokens, which is the case when
+// one of the predicates at its start excludes the tokens or, when the rules it starts
+// with are checked, it starts with a non-terminal which can not be predicted for the tokens.
+func (t *ParseTables) rejects(rule *ParseRule, tokens []*Token, nested bool) bool {
+	rejected := false
+	i := 0
+	for !rejected && i < len(rule.Predicates) {
+		rejected = rule.Predicates[i].Position == 0 && t.Excludes(rule.Predicates[i], tokens)
+		i++
+	}
+	if !rejected && nested && len(rule.Symbols) > 0 && int(rule.Symbols[0]) <= t.NonTerminalCount {
+		_, predicted := t.predict(rule.Symbols[0], tokens, true)
+		rejected = !predicted
+	}
+	return rejected
+}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"testing"
+)
+
+// Holds the tables generated for a statement whose expression
+// statement alternative starts with a lookahead restriction.
+func predicateTestTables() *ParseTables {
+	return &ParseTables{
+		SymbolNames: []string{
+			"", "Statement", "ExpressionStatement", "Expression", "LexicalDeclaration", "AsyncFunctionDeclaration",
+			"{", "let", "[", "async", "function", ";", "IdentifierName", "}", "]", "[eoi]",
+		},
+		NonTerminalCount: 5,
+		Starts:           map[string]Symbol{"Statement": 1},
+		EndOfInput:       15,
+		Rules: []*ParseRule{
+			{Production: 1, Symbols: []Symbol{2}},
+			{Production: 1, Symbols: []Symbol{4}},
+			{Production: 1, Symbols: []Symbol{5}},
+			{Production: 2, Symbols: []Symbol{3, 11}, Predicates: []*LookaheadPredicate{{Position: 0, Exclude: [][]PredicateTerminal{
+				{{Symbol: 6}}, {{Symbol: 7}, {Symbol: 8}}, {{Symbol: 9}, {Symbol: 10, NoLineTerminator: true}},
+			}}}},
+			{Production: 3, Symbols: []Symbol{12}},
+			{Production: 3, Symbols: []Symbol{7}},
+			{Production: 3, Symbols: []Symbol{9}},
+			{Production: 3, Symbols: []Symbol{6, 13}},
+			{Production: 4, Symbols: []Symbol{7, 8, 12, 14, 11}},
+			{Production: 5, Symbols: []Symbol{9, 10, 12}},
+		},
+		ParseTable: map[Symbol]map[Symbol]int{
+			1: {7: 0, 9: 0, 12: 0},
+			2: {7: 3, 9: 3, 12: 3},
+			3: {6: 7, 7: 5, 9: 6, 12: 4},
+			4: {7: 8},
+			5: {9: 9},
+		},
+		Fallbacks: map[Symbol]map[Symbol][]int{
+			1: {7: {1}, 9: {2}},
+		},
+	}
+}
+
+func TestParseTablesPredict(t *testing.T) {
+	tables := predicateTestTables()
+	for _, test := range []struct {
+		input       string
+		nonTerminal Symbol
+		rule        int
+		predicted   bool
+	}{
+		{"let x;", 1, 0, true},
+		{"let [a] = b;", 1, 1, true},
+		{"async function f() {}", 1, 2, true},
+		{"async\nfunction f() {}", 1, 0, true},
+		{"\nlet\n[a] = b;", 1, 1, true},
+		{"value;", 1, 0, true},
+		{"{ }", 2, 0, false},
+		{"let [a] = b;", 2, 0, false},
+		{"{ }", 3, 7, true},
+	} {
+		lexer := NewLexer()
+		tokens, err := lexer.Tokenise([]rune(test.input), InputElementDiv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rule, predicted := tables.Predict(test.nonTerminal, tokens)
+		if predicted != test.predicted || (predicted && rule != test.rule) {
+			t.Errorf(
+				"Expected %q to predict rule %v (%v) for %v but got rule %v (%v)",
+				test.input, test.rule, test.predicted, tables.SymbolNames[test.nonTerminal], rule, predicted,
+			)
+		}
+	}
+}
+
+func TestParseTablesExcludes(t *testing.T) {
+	tables := predicateTestTables()
+	predicate := tables.Rules[3].Predicates[0]
+	for input, excluded := range map[string]bool{
+		"{ }":                 true,
+		"let [a] = b;":        true,
+		"let\n[a] = b;":       true,
+		"let x;":              false,
+		"async function(){}":  true,
+		"async\nfunction(){}": false,
+		"async;":              false,
+		"'{';":                false,
+		"":                    false,
+	} {
+		lexer := NewLexer()
+		tokens, err := lexer.Tokenise([]rune(input), InputElementDiv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tables.Excludes(predicate, tokens) != excluded {
+			t.Errorf("Expected the predicate to exclude %q to be %v", input, excluded)
+		}
+	}
+}
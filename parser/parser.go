@@ -1,6 +1,5 @@
 package parser
 
-//go:generate esegrammar build -grammar grammar.yml -output grammar.go -package parser
 import (
 	"bytes"
 	"errors"
@@ -10,6 +9,18 @@ import (
 	"golang.org/x/text/encoding/unicode/utf32"
 )
 
+// Holds the tables generated from the syntactic grammar,
+// these are populated by a grammar.go source generated with esegrammar build.
+// The ECMAScript grammar is not LL(1) so the source is not generated
+// until its remaining conflicts have been resolved.
+var generatedTables = &ParseTables{
+	ParseTable: map[Symbol]map[Symbol]int{},
+}
+
+// Holds the LALR(1) tables generated from the syntactic grammar
+// when esegrammar is run with the lalr algorithm.
+var generatedLRTables = &LRTables{}
+
 var (
 	// ErrInvalidUnicodeSourceText provides the error when source text
 	// contains characters which are not valid unicode code points.
@@ -29,7 +40,8 @@ type Parser interface {
 func NewParser(lexer Lexer) Parser {
 	return &parserImpl{
 		lexer, false, InputElementDiv,
-		ParseStack{}, map[Symbol]map[Symbol]int{},
+		ParseStack{}, generatedTables.ParseTable,
+		generatedTables,
 	}
 }
 
@@ -41,6 +53,7 @@ type parserImpl struct {
 	lexicalGoal  LexicalGoalSymbol
 	parseStack   ParseStack
 	parseTable   map[Symbol]map[Symbol]int
+	tables       *ParseTables
 }
 
 // Parse deals with parsing the given set
@@ -74,14 +87,16 @@ func (p *parserImpl) ParseModule(sourceText []byte, realm *RealmRecord, hostDefi
 	} else if err != nil {
 		return nil, err
 	}
-	input := bytes.Runes(decoded)
+	// The source text is lexed from a sliding window rather than
+	// decoding all of it to code points up front.
+	stream := NewReaderTokenStream(bytes.NewReader(decoded))
 	errors := []error{}
 	tree := (*ParseNode)(nil)
-	p.parseModule(input, tree, errors)
+	p.parseModule(stream, tree, errors)
 	return nil, nil
 }
 
-func (p *parserImpl) parseModule(input []rune, tree *ParseNode, errors []error) {
+func (p *parserImpl) parseModule(stream *TokenStream, tree *ParseNode, errors []error) {
 }
 
 // Deals with validating the given source code contains nothing but
@@ -1 +1,234 @@
 package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnexpectedToken provides the error for the case when a token
+	// can not be parsed as part of the goal symbol.
+	ErrUnexpectedToken = errors.New("unexpected token")
+	// ErrUnexpectedEndOfInput provides the error for the case when the
+	// tokens end before the goal symbol has been parsed.
+	ErrUnexpectedEndOfInput = errors.New("unexpected end of input")
+	// ErrNotRefinement provides the error for the case when a node is reparsed
+	// with a goal symbol that does not refine the cover it was parsed as.
+	ErrNotRefinement = errors.New("not a refinement of the cover")
+)
+
+// Holds a non-terminal node being parsed along with the
+// rule it was expanded with and the next symbol of the rule to parse.
+type parseFrame struct {
+	node  *ParseNode
+	rule  *ParseRule
+	next  int
+	start int
+}
+
+// Holds the tokens being parsed along with the position of the next token to parse.
+// When parsing a stream only the tokens consumed so far are held, the upcoming tokens
+// are pulled from the stream with the lexical goal the parse expects.
+type parseInput struct {
+	tokens   []*Token
+	position int
+	stream   *TokenStream
+	// Holds the number of tokens other than line terminators
+	// looked ahead at when parsing a stream.
+	lookahead int
+}
+
+// Parse deals with parsing all of the given tokens as an instance of the goal symbol
+// by expanding the rules predicted by the parse table, the lookahead predicates
+// of each rule are checked before the symbol they precede is parsed.
+func (t *ParseTables) Parse(goal Symbol, tokens []*Token) (*ParseNode, error) {
+	return t.parse(goal, &parseInput{tokens: tokens})
+}
+
+// ParseStream deals with parsing the tokens pulled from the stream as an instance of the goal
+// symbol. Each token is lexed with the lexical goal for the terminals the parse table expects
+// in its place, such as InputElementRegExp where a regular expression literal can start, and
+// enough tokens are looked ahead at for the lookahead predicates of the rules.
+func (t *ParseTables) ParseStream(goal Symbol, stream *TokenStream) (*ParseNode, error) {
+	return t.parse(goal, &parseInput{stream: stream})
+}
+
+// Provides the parse tree of the goal symbol parsed from the input.
+func (t *ParseTables) parse(goal Symbol, input *parseInput) (*ParseNode, error) {
+	t.loadTerminals()
+	input.lookahead = t.lookahead
+	root := &ParseNode{Symbol: goal}
+	frame, err := t.expand(root, input)
+	if err != nil {
+		return nil, err
+	}
+	stack := []*parseFrame{frame}
+	for len(stack) > 0 {
+		frame := stack[len(stack)-1]
+		for _, predicate := range frame.rule.Predicates {
+			if predicate.Position == frame.next && frame.next > 0 {
+				upcoming, err := input.upcoming(t.goalBefore(frame))
+				if err != nil {
+					return nil, err
+				}
+				if t.Excludes(predicate, upcoming) {
+					return nil, t.unexpected(upcoming)
+				}
+			}
+		}
+		if frame.next == len(frame.rule.Symbols) {
+			frame.node.Tokens = input.tokens[frame.start:input.position]
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		symbol := frame.rule.Symbols[frame.next]
+		frame.next++
+		if int(symbol) <= t.NonTerminalCount {
+			child := &ParseNode{Symbol: symbol}
+			frame.node.Children = append(frame.node.Children, child)
+			childFrame, err := t.expand(child, input)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, childFrame)
+		} else {
+			token, err := input.next(t.goals[symbol])
+			if err != nil {
+				return nil, err
+			}
+			if token == nil {
+				return nil, ErrUnexpectedEndOfInput
+			}
+			if !t.Matches(token, symbol) {
+				return nil, t.unexpected([]*Token{token})
+			}
+			frame.node.Children = append(frame.node.Children, &ParseNode{
+				Symbol: symbol, Terminal: true, Tokens: input.tokens[input.position-1 : input.position],
+			})
+		}
+	}
+	upcoming, err := input.upcoming(InputElementDiv)
+	if err != nil {
+		return nil, err
+	}
+	if position := skipLineTerminators(upcoming, 0); position < len(upcoming) {
+		return nil, t.unexpected(upcoming)
+	}
+	return root, nil
+}
+
+// Reparse deals with parsing the tokens a cover node was parsed from again
+// with a production which refines the cover as the goal symbol, as is required
+// for the cover grammars of the ECMAScript syntactic grammar.
+func (t *ParseTables) Reparse(node *ParseNode, refining Symbol) (*ParseNode, error) {
+	found := false
+	i := 0
+	for !found && i < len(t.Refinements[node.Symbol]) {
+		if t.Refinements[node.Symbol][i] == refining {
+			found = true
+		} else {
+			i++
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf(
+			"%w: <%v> does not refine <%v>", ErrNotRefinement,
+			t.SymbolNames[refining], t.SymbolNames[node.Symbol],
+		)
+	}
+	return t.Parse(refining, node.Tokens)
+}
+
+// Provides the frame which parses the given non-terminal node with
+// the rule predicted for the upcoming tokens of the input.
+func (t *ParseTables) expand(node *ParseNode, input *parseInput) (*parseFrame, error) {
+	upcoming, err := input.upcoming(t.goals[node.Symbol])
+	if err != nil {
+		return nil, err
+	}
+	ruleIndex, predicted := t.Predict(node.Symbol, upcoming)
+	if !predicted {
+		return nil, t.unexpected(upcoming)
+	}
+	node.Rule = ruleIndex
+	return &parseFrame{node: node, rule: t.Rules[ruleIndex], start: input.position}, nil
+}
+
+// Provides the lexical goal of the tokens which follow the symbols of the
+// rule of the frame parsed so far, the goal of the next symbol of the rule.
+func (t *ParseTables) goalBefore(frame *parseFrame) LexicalGoalSymbol {
+	if frame.next < len(frame.rule.Symbols) {
+		return t.goals[frame.rule.Symbols[frame.next]]
+	}
+	return InputElementDiv
+}
+
+// Provides the error for the first of the upcoming tokens which is not a line
+// terminator as it could not be parsed, the end of input when there is none.
+func (t *ParseTables) unexpected(upcoming []*Token) error {
+	position := skipLineTerminators(upcoming, 0)
+	if position >= len(upcoming) {
+		return ErrUnexpectedEndOfInput
+	}
+	token := upcoming[position]
+	// Only tokens produced by a Lexer have a span.
+	if token.Span.Start.Line > 0 {
+		return fmt.Errorf("%w: %q at %v", ErrUnexpectedToken, token.Value, token.Span.Start)
+	}
+	return fmt.Errorf("%w: %q at %v", ErrUnexpectedToken, token.Value, token.Pos)
+}
+
+// Provides the upcoming tokens from the position of the input, when parsing a stream as many
+// tokens are looked ahead at as the lookahead of the parse table with the given goal.
+func (in *parseInput) upcoming(goal LexicalGoalSymbol) ([]*Token, error) {
+	if in.stream == nil {
+		return in.tokens[in.position:], nil
+	}
+	upcoming := []*Token{}
+	significant := 0
+	for significant < in.lookahead {
+		token, err := in.stream.Peek(len(upcoming)+1, goal)
+		if token == nil || err != nil {
+			return upcoming, err
+		}
+		upcoming = append(upcoming, token)
+		if token.Kind != LineTerminatorToken {
+			significant++
+		}
+	}
+	return upcoming, nil
+}
+
+// Provides the next token of the input which is not a line terminator lexed with the
+// given goal and moves past it, along with the line terminators before it. A nil token
+// is provided at the end of input.
+func (in *parseInput) next(goal LexicalGoalSymbol) (*Token, error) {
+	if in.stream == nil {
+		in.position = skipLineTerminators(in.tokens, in.position)
+		if in.position == len(in.tokens) {
+			return nil, nil
+		}
+		in.position++
+		return in.tokens[in.position-1], nil
+	}
+	for {
+		token, err := in.stream.Next(goal)
+		if token == nil || err != nil {
+			return nil, err
+		}
+		in.tokens = append(in.tokens, token)
+		in.position++
+		if token.Kind != LineTerminatorToken {
+			return token, nil
+		}
+	}
+}
+
+// Provides the position of the first token at or after the given
+// position which is not a line terminator.
+func skipLineTerminators(tokens []*Token, position int) int {
+	for position < len(tokens) && tokens[position].Kind == LineTerminatorToken {
+		position++
+	}
+	return position
+}
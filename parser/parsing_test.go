@@ -1 +1,157 @@
 package parser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// Holds the tables generated for a primary expression with a cover
+// production which is refined by a parenthesised expression and the
+// parameters of an arrow function in the supplemental grammar.
+func coverTestTables() *ParseTables {
+	return &ParseTables{
+		SymbolNames: []string{
+			"", "Primary", "Cover", "CoverTail", "Parenthesized", "ArrowParameters", "Parameters",
+			"(", ")", "IdentifierName", "[eoi]",
+		},
+		NonTerminalCount: 6,
+		Starts:           map[string]Symbol{"Primary": 1},
+		EndOfInput:       10,
+		Rules: []*ParseRule{
+			{Production: 1, Symbols: []Symbol{2}},
+			{Production: 1, Symbols: []Symbol{9}},
+			{Production: 2, Symbols: []Symbol{7, 3}},
+			{Production: 3, Symbols: []Symbol{9, 8}},
+			{Production: 3, Symbols: []Symbol{8}},
+			{Production: 4, Symbols: []Symbol{7, 9, 8}},
+			{Production: 5, Symbols: []Symbol{7, 6, 8}},
+			{Production: 6, Symbols: []Symbol{9}},
+			{Production: 6, Symbols: []Symbol{}},
+		},
+		ParseTable: map[Symbol]map[Symbol]int{
+			1: {7: 0, 9: 1},
+			2: {7: 2},
+			3: {8: 4, 9: 3},
+			4: {7: 5},
+			5: {7: 6},
+			6: {8: 8, 9: 7},
+		},
+		Refinements: map[Symbol][]Symbol{
+			2: {4, 5},
+		},
+	}
+}
+
+// Provides the tokens with the given values, identifiers are separated
+// by spaces from punctuators and a new line provides a line terminator,
+// keywords are tokens of their own kind.
+func tokensOf(input string) []*Token {
+	tokens := []*Token{}
+	pos := 0
+	for _, value := range strings.Split(input, " ") {
+		kind := Punctuators()[value]
+		switch {
+		case value == "\n":
+			kind = LineTerminatorToken
+		case Keywords()[value] != 0:
+			kind = Keywords()[value]
+		case unicode.IsLetter(rune(value[0])):
+			kind = IdentifierNameToken
+		}
+		tokens = append(tokens, &Token{Kind: kind, Value: value, Pos: pos})
+		pos += len(value) + 1
+	}
+	return tokens
+}
+
+// Provides the symbols of the parse tree in pre-order.
+func treeSymbols(node *ParseNode) []Symbol {
+	symbols := []Symbol{node.Symbol}
+	for _, child := range node.Children {
+		symbols = append(symbols, treeSymbols(child)...)
+	}
+	return symbols
+}
+
+func TestParseTablesParse(t *testing.T) {
+	tables := coverTestTables()
+	tree, err := tables.Parse(1, tokensOf("( \n x )"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Symbol{1, 2, 7, 3, 9, 8}
+	if !reflect.DeepEqual(treeSymbols(tree), expected) {
+		t.Errorf("Expected the parse tree %v but got %v", expected, treeSymbols(tree))
+	}
+	if len(tree.Tokens) != 4 || len(tree.Children[0].Children[1].Tokens) != 3 {
+		t.Errorf("Expected the tokens of each node to be kept including line terminators")
+	}
+	leaf := tree.Children[0].Children[1].Children[0]
+	if !leaf.Terminal || leaf.Tokens[0].Value != "x" {
+		t.Errorf("Expected a terminal node for x but got %+v", leaf)
+	}
+
+	for _, test := range []struct {
+		input string
+		err   error
+	}{
+		{"( x", ErrUnexpectedEndOfInput},
+		{"( x ) y", ErrUnexpectedToken},
+		{")", ErrUnexpectedToken},
+	} {
+		if _, err := tables.Parse(1, tokensOf(test.input)); !errors.Is(err, test.err) {
+			t.Errorf("Expected parsing %q to fail with %v but got %v", test.input, test.err, err)
+		}
+	}
+}
+
+func TestParseTablesParsePredicates(t *testing.T) {
+	tables := predicateTestTables()
+	for _, test := range []struct {
+		input string
+		rule  Symbol
+	}{
+		{"let ;", 2},
+		{"let [ x ] ;", 4},
+		{"async function f", 5},
+	} {
+		tree, err := tables.Parse(1, tokensOf(test.input))
+		if err != nil {
+			t.Fatalf("Expected %q to be parsed but got %v", test.input, err)
+		}
+		if tree.Children[0].Symbol != test.rule {
+			t.Errorf("Expected %q to be parsed as %v but got %v", test.input, test.rule, tree.Children[0].Symbol)
+		}
+	}
+}
+
+func TestParseTablesReparse(t *testing.T) {
+	tables := coverTestTables()
+	for _, test := range []struct {
+		input    string
+		refining Symbol
+		expected []Symbol
+		err      error
+	}{
+		{"( x )", 4, []Symbol{4, 7, 9, 8}, nil},
+		{"( x )", 5, []Symbol{5, 7, 6, 9, 8}, nil},
+		{"( )", 5, []Symbol{5, 7, 6, 8}, nil},
+		{"( )", 4, nil, ErrUnexpectedToken},
+		{"( x )", 6, nil, ErrNotRefinement},
+	} {
+		tree, err := tables.Parse(1, tokensOf(test.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		refined, err := tables.Reparse(tree.Children[0], test.refining)
+		if !errors.Is(err, test.err) {
+			t.Errorf("Expected reparsing %q as %v to fail with %v but got %v", test.input, test.refining, test.err, err)
+		}
+		if err == nil && !reflect.DeepEqual(treeSymbols(refined), test.expected) {
+			t.Errorf("Expected reparsing %q to give %v but got %v", test.input, test.expected, treeSymbols(refined))
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package parser
+
+import (
+	"unicode/utf8"
+)
+
+const (
+	// Holds the number of tokens allocated at once by a scanner.
+	scannerSlabSize = 256
+	// Holds the longest value in code points which is interned by a scanner
+	// and the number of values after which a scanner stops interning them.
+	maxInternedLength = 32
+	maxInternedValues = 1 << 14
+)
+
+// asciiClass provides a type alias to distinguish the classes of ASCII code points,
+// which determine how the fast path of a scanner lexes a token starting with one.
+type asciiClass uint8
+
+const (
+	// Holds the code points which are only lexed by NextToken.
+	otherClass asciiClass = iota
+	whiteSpaceClass
+	lineTerminatorClass
+	identifierClass
+	digitClass
+	punctuatorClass
+	quoteClass
+	slashClass
+	rightBraceClass
+)
+
+// Holds the string of each ASCII code point so the values of tokens
+// made up of a single code point don't need to be allocated.
+var asciiStrings = func() (strings [utf8.RuneSelf]string) {
+	for c := range strings {
+		strings[c] = string(rune(c))
+	}
+	return strings
+}()
+
+// Deals with lexing tokens through a fast path for tokens which start with an ASCII code point,
+// which are most of the tokens of real source text. The fast path switches on the class of the
+// code point rather than trying each kind of token in turn, punctuators are matched through a trie
+// and reserved words through a perfect hash table, and tokens are allocated in slabs with values
+// which are interned, so lexing a token doesn't usually allocate. Tokens the fast path doesn't
+// deal with, such as those starting with other code points or holding escape sequences,
+// regular expression literals and templates, are lexed by NextToken.
+//
+// A scanner produces the same tokens as NextToken with the same maps, it is non-threadsafe
+// as it appends tokens to its current slab and interns values in a shared map. Slabs are never
+// reused, a new one is only allocated once the current one is full so tokens stay valid.
+type scanner struct {
+	charMap map[string]map[rune]rune
+	pMap    map[string]TokenKind
+	kwMap   map[string]TokenKind
+	frwMap  map[string]TokenKind
+	classes [utf8.RuneSelf]asciiClass
+	// Holds the root of the trie of punctuators for each ASCII code point.
+	punctuators   [utf8.RuneSelf]*punctuatorNode
+	reservedWords *reservedWordTable
+	slab          []Token
+	values        map[string]string
+	encoded       []byte
+}
+
+// Holds a node of the trie of punctuators, the edges are
+// keyed by the code point which follows the node.
+type punctuatorNode struct {
+	value    string
+	kind     TokenKind
+	terminal bool
+	edges    []punctuatorEdge
+}
+
+type punctuatorEdge struct {
+	c    rune
+	node *punctuatorNode
+}
+
+// Creates a new scanner for the given code point and reserved word maps.
+func newScanner(charMap map[string]map[rune]rune,
+	pMap map[string]TokenKind, kwMap map[string]TokenKind, frwMap map[string]TokenKind) *scanner {
+	s := &scanner{
+		charMap:       charMap,
+		pMap:          pMap,
+		kwMap:         kwMap,
+		frwMap:        frwMap,
+		reservedWords: newReservedWordTable(kwMap, frwMap),
+		values:        map[string]string{},
+	}
+	// The classes are assigned in the reverse of the order NextToken
+	// checks for each kind of token so the first check wins.
+	for punctuator, kind := range pMap {
+		s.addPunctuator([]rune(punctuator), kind)
+	}
+	for c := rune(0); c < utf8.RuneSelf; c++ {
+		switch {
+		case c == '\'' || c == '"':
+			s.classes[c] = quoteClass
+		case '0' <= c && c <= '9':
+			s.classes[c] = digitClass
+		case IsIDStart(c) || c == '$' || c == '_':
+			s.classes[c] = identifierClass
+		case c == '/':
+			s.classes[c] = slashClass
+		case c == '}':
+			s.classes[c] = rightBraceClass
+		}
+		if IsLineTerminator(c, charMap) {
+			s.classes[c] = lineTerminatorClass
+		}
+		if _, isWhiteSpace := charMap["whitespace"][c]; isWhiteSpace {
+			s.classes[c] = whiteSpaceClass
+		}
+	}
+	return s
+}
+
+// Deals with adding a punctuator which starts with an ASCII code point to the trie of punctuators.
+func (s *scanner) addPunctuator(punctuator []rune, kind TokenKind) {
+	if len(punctuator) == 0 || punctuator[0] >= utf8.RuneSelf {
+		return
+	}
+	s.classes[punctuator[0]] = punctuatorClass
+	if s.punctuators[punctuator[0]] == nil {
+		s.punctuators[punctuator[0]] = &punctuatorNode{}
+	}
+	node := s.punctuators[punctuator[0]]
+	for _, c := range punctuator[1:] {
+		var next *punctuatorNode
+		for _, edge := range node.edges {
+			if edge.c == c {
+				next = edge.node
+			}
+		}
+		if next == nil {
+			next = &punctuatorNode{}
+			node.edges = append(node.edges, punctuatorEdge{c: c, node: next})
+		}
+		node = next
+	}
+	node.value, node.kind, node.terminal = string(punctuator), kind, true
+}
+
+// Deals with lexing the next token from the given position in the same way as NextToken,
+// a nil token is returned when white space or a comment is skipped or at the end of input.
+func (s *scanner) next(pos int, buf []rune, goal LexicalGoalSymbol) (*Token, int, error) {
+	if pos >= len(buf) {
+		return nil, pos, nil
+	}
+	c := buf[pos]
+	if c >= utf8.RuneSelf {
+		return s.nextToken(pos, buf, goal)
+	}
+	switch s.classes[c] {
+	case whiteSpaceClass:
+		end := pos + 1
+		for end < len(buf) && buf[end] < utf8.RuneSelf && s.classes[buf[end]] == whiteSpaceClass {
+			end++
+		}
+		return nil, end, nil
+	case lineTerminatorClass:
+		if c == '\u000D' && pos+1 < len(buf) && buf[pos+1] == '\u000A' {
+			return s.token(LineTerminatorToken, "\r\n", pos), pos + 2, nil
+		}
+		return s.token(LineTerminatorToken, asciiStrings[c], pos), pos + 1, nil
+	case identifierClass:
+		end := pos + 1
+		for end < len(buf) && buf[end] < utf8.RuneSelf && s.isIdentifierPart(buf[end]) {
+			end++
+		}
+		if end < len(buf) && (buf[end] >= utf8.RuneSelf || buf[end] == '\\') {
+			// The identifier could continue with a code point which isn't ASCII
+			// or a unicode escape sequence.
+			return s.nextToken(pos, buf, goal)
+		}
+		if word, kind, isReservedWord := s.reservedWords.lookup(buf[pos:end]); isReservedWord {
+			return s.token(kind, word, pos), end, nil
+		}
+		return s.token(IdentifierNameToken, s.intern(buf[pos:end]), pos), end, nil
+	case digitClass:
+		end := pos + 1
+		if c != '0' {
+			for end < len(buf) && '0' <= buf[end] && buf[end] <= '9' {
+				end++
+			}
+		}
+		if end < len(buf) && (buf[end] == '.' || buf[end] == 'e' || buf[end] == 'E' ||
+			c == '0' && buf[end] < utf8.RuneSelf && s.isIdentifierPart(buf[end])) {
+			// Fractions, exponents and the integer literals with
+			// a prefix are lexed by NextToken.
+			return s.nextToken(pos, buf, goal)
+		}
+		return s.token(DecimalLiteralToken, s.intern(buf[pos:end]), pos), end, nil
+	case punctuatorClass:
+		if c == '.' && pos+1 < len(buf) && '0' <= buf[pos+1] && buf[pos+1] <= '9' {
+			return s.nextToken(pos, buf, goal)
+		}
+		if node, end := s.punctuator(pos, buf); node != nil {
+			return s.token(node.kind, node.value, pos), end, nil
+		}
+	case quoteClass:
+		end := pos + 1
+		for end < len(buf) && buf[end] != c {
+			if buf[end] == '\\' || s.isLineTerminator(buf[end]) {
+				// Escape sequences, line continuations and invalid
+				// line terminators are lexed by NextToken.
+				return s.nextToken(pos, buf, goal)
+			}
+			end++
+		}
+		if end < len(buf) {
+			return s.token(StringLiteralToken, s.intern(buf[pos+1:end]), pos), end + 1, nil
+		}
+	case slashClass:
+		if pos+1 < len(buf) && buf[pos+1] == '/' {
+			end := pos + 2
+			for end < len(buf) && !s.isLineTerminator(buf[end]) {
+				end++
+			}
+			return nil, end, nil
+		} else if pos+1 < len(buf) && buf[pos+1] == '*' {
+			containsLineTerminator := false
+			for end := pos + 2; end+1 < len(buf); end++ {
+				if buf[end] == '*' && buf[end+1] == '/' && containsLineTerminator {
+					return s.token(LineTerminatorToken, string(buf[pos:end+2]), pos), end + 2, nil
+				} else if buf[end] == '*' && buf[end+1] == '/' {
+					return nil, end + 2, nil
+				}
+				containsLineTerminator = containsLineTerminator || s.isLineTerminator(buf[end])
+			}
+		} else if goal == InputElementDiv || goal == InputElementTemplateTail {
+			if pos+1 < len(buf) && buf[pos+1] == '=' {
+				return s.token(SlashEqualsToken, "/=", pos), pos + 2, nil
+			}
+			return s.token(SlashToken, "/", pos), pos + 1, nil
+		}
+	case rightBraceClass:
+		if goal == InputElementDiv || goal == InputElementRegExp {
+			return s.token(RightBraceToken, "}", pos), pos + 1, nil
+		}
+	}
+	return s.nextToken(pos, buf, goal)
+}
+
+// Provides the next token lexed by NextToken for the cases the fast path doesn't deal with.
+func (s *scanner) nextToken(pos int, buf []rune, goal LexicalGoalSymbol) (*Token, int, error) {
+	return NextToken(pos, buf, s.charMap, s.pMap, s.kwMap, s.frwMap, goal)
+}
+
+// Provides the longest punctuator starting at the given position along with the position just after it.
+func (s *scanner) punctuator(pos int, buf []rune) (*punctuatorNode, int) {
+	var match *punctuatorNode
+	end := pos
+	node := s.punctuators[buf[pos]]
+	for i := pos + 1; node != nil; i++ {
+		if node.terminal {
+			match, end = node, i
+		}
+		var next *punctuatorNode
+		for j := 0; i < len(buf) && j < len(node.edges); j++ {
+			if node.edges[j].c == buf[i] {
+				next = node.edges[j].node
+			}
+		}
+		node = next
+	}
+	return match, end
+}
+
+// Determines whether the given ASCII code point is part of an identifier.
+func (s *scanner) isIdentifierPart(c rune) bool {
+	return s.classes[c] == identifierClass || s.classes[c] == digitClass
+}
+
+func (s *scanner) isLineTerminator(c rune) bool {
+	if c < utf8.RuneSelf {
+		return s.classes[c] == lineTerminatorClass
+	}
+	return IsLineTerminator(c, s.charMap)
+}
+
+// Provides a token allocated from the current slab of tokens.
+func (s *scanner) token(kind TokenKind, value string, pos int) *Token {
+	if len(s.slab) == cap(s.slab) {
+		s.slab = make([]Token, 0, scannerSlabSize)
+	}
+	s.slab = append(s.slab, Token{Kind: kind, Value: value, Pos: pos})
+	return &s.slab[len(s.slab)-1]
+}
+
+// Provides the string of the given code points. Short values are interned so
+// the values of identifiers and literals which are repeated share a single string,
+// looking them up through the UTF-8 encoding of the code points doesn't allocate.
+func (s *scanner) intern(value []rune) string {
+	if len(value) > maxInternedLength {
+		return string(value)
+	}
+	var encoded [utf8.UTFMax]byte
+	s.encoded = s.encoded[:0]
+	for _, c := range value {
+		s.encoded = append(s.encoded, encoded[:utf8.EncodeRune(encoded[:], c)]...)
+	}
+	if interned, exists := s.values[string(s.encoded)]; exists {
+		return interned
+	}
+	interned := string(s.encoded)
+	if len(s.values) < maxInternedValues {
+		s.values[interned] = interned
+	}
+	return interned
+}
+
+// Holds the reserved words of a scanner in a perfect hash table. The seed of the hash is chosen
+// when the table is created so that each word has a slot of its own, an identifier is then
+// a reserved word when it is the word in the slot it hashes to.
+type reservedWordTable struct {
+	seed      uint32
+	mask      uint32
+	maxLength int
+	slots     []reservedWord
+}
+
+type reservedWord struct {
+	word  string
+	runes []rune
+	kind  TokenKind
+}
+
+// Creates the perfect hash table of the keywords, future reserved words and the null and boolean
+// literals, where keywords take precedence over future reserved words as in ProcessReservedWord.
+func newReservedWordTable(kwMap map[string]TokenKind, frwMap map[string]TokenKind) *reservedWordTable {
+	words := map[string]TokenKind{"null": NullLiteralToken, "true": BooleanLiteralToken, "false": BooleanLiteralToken}
+	for word, kind := range frwMap {
+		words[word] = kind
+	}
+	for word, kind := range kwMap {
+		words[word] = kind
+	}
+	size := 1
+	for size < 4*len(words) {
+		size *= 2
+	}
+	table := &reservedWordTable{}
+	for ; ; size *= 2 {
+		for seed := uint32(0); seed < 256; seed++ {
+			if table.fill(words, seed, size) {
+				return table
+			}
+		}
+	}
+}
+
+// Deals with filling the slots of the table with the given seed,
+// determines whether none of the words share a slot.
+func (t *reservedWordTable) fill(words map[string]TokenKind, seed uint32, size int) bool {
+	t.seed, t.mask, t.maxLength = seed, uint32(size-1), 0
+	t.slots = make([]reservedWord, size)
+	for word, kind := range words {
+		runes := []rune(word)
+		slot := &t.slots[t.hash(runes)]
+		if slot.runes != nil {
+			return false
+		}
+		*slot = reservedWord{word: word, runes: runes, kind: kind}
+		if len(runes) > t.maxLength {
+			t.maxLength = len(runes)
+		}
+	}
+	return true
+}
+
+// Provides the FNV-1a hash of the given code points.
+func (t *reservedWordTable) hash(value []rune) uint32 {
+	h := 2166136261 ^ t.seed
+	for _, c := range value {
+		h = (h ^ uint32(c)) * 16777619
+	}
+	return h & t.mask
+}
+
+// Provides the reserved word the given code points make up along with its kind,
+// determines whether they make up a reserved word.
+func (t *reservedWordTable) lookup(value []rune) (string, TokenKind, bool) {
+	if len(value) > t.maxLength {
+		return "", 0, false
+	}
+	slot := &t.slots[t.hash(value)]
+	if slot.runes == nil || len(slot.runes) != len(value) {
+		return "", 0, false
+	}
+	for i, c := range slot.runes {
+		if value[i] != c {
+			return "", 0, false
+		}
+	}
+	return slot.word, slot.kind, true
+}
@@ -0,0 +1,209 @@
+package parser
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Holds the minified libraries the lexer is tested and benchmarked against,
+// their licenses are kept in the comments at the start of each of them.
+var minifiedLibraries = []string{"jquery.min.js", "js-yaml.min.js", "bluebird.min.js"}
+
+func readMinifiedLibrary(tb testing.TB, name string) []byte {
+	source, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return source
+}
+
+// Provides the goal a parser would ask for after the given token, which is approximated
+// as the source is only lexed. A / is a division after an identifier, literal or
+// closing bracket and a regular expression otherwise.
+func goalAfter(token *Token, goal LexicalGoalSymbol) LexicalGoalSymbol {
+	switch {
+	case token.Kind == LineTerminatorToken:
+		return goal
+	case token.Kind < LeftBraceToken || token.Kind == RightParenToken ||
+		token.Kind == RightBracketToken || token.Kind == ThisToken:
+		return InputElementDiv
+	}
+	return InputElementRegExp
+}
+
+// Deals with pulling all of the tokens from the stream with the goal a parser
+// would ask for, provides the number of tokens.
+func lexWithGoals(stream *TokenStream) (int, error) {
+	count := 0
+	goal := InputElementRegExp
+	for {
+		token, err := stream.Next(goal)
+		if token == nil || err != nil {
+			return count, err
+		}
+		count++
+		goal = goalAfter(token, goal)
+	}
+}
+
+// Provides the tokens of the input lexed with the given function, skipping white space
+// and comments, along with the error lexing stopped at.
+func lexAll(input []rune, next func(int, []rune, LexicalGoalSymbol) (*Token, int, error)) ([]Token, error) {
+	tokens := []Token{}
+	goal := InputElementRegExp
+	for pos := 0; pos < len(input); {
+		token, nextPos, err := next(pos, input, goal)
+		if err != nil {
+			return tokens, err
+		} else if token != nil {
+			tokens = append(tokens, *token)
+			goal = goalAfter(token, goal)
+		}
+		pos = nextPos
+	}
+	return tokens, nil
+}
+
+func TestScannerMatchesNextToken(t *testing.T) {
+	inputs := []string{
+		"a>>>=b; x>>=", "x.y...z", "$_a1 _$ a/=b/c; 1-2", "true false null enum await yield awaits instanceof",
+		"é = 1; ñ\\u0061 = 2; a\\u0062c", "'it\\'s' \"q\\x41\" 'ü' \" \"", "'unterminated",
+		"/* a\n b */ c // d\n e /* f */", "/* unterminated", "a b c\r\n\r\n\r",
+		"0x1F 0b101 0o17 0 01 0a 1.5e-3 .5 5. 1e5 10n", "`t ${a} u` `v`", "}/a/g", "#", "a = {}\n}",
+	}
+	for _, name := range minifiedLibraries {
+		inputs = append(inputs, string(readMinifiedLibrary(t, name)))
+	}
+	lexer := NewLexer().(*lexerImpl)
+	scanner := lexer.scanner
+	for _, input := range inputs {
+		expected, expectedErr := lexAll([]rune(input), func(pos int, buf []rune, goal LexicalGoalSymbol) (*Token, int, error) {
+			return NextToken(pos, buf, scanner.charMap, scanner.pMap, scanner.kwMap, scanner.frwMap, goal)
+		})
+		actual, err := lexAll([]rune(input), scanner.next)
+		if len(actual) != len(expected) || (err == nil) != (expectedErr == nil) {
+			t.Errorf("Expected %v tokens and the error %v lexing %.40q but got %v tokens and %v",
+				len(expected), expectedErr, input, len(actual), err)
+			continue
+		}
+		for i := range expected {
+			if actual[i].Kind != expected[i].Kind || actual[i].Value != expected[i].Value || actual[i].Pos != expected[i].Pos {
+				t.Errorf("Expected the token %+v lexing %.40q but got %+v", expected[i], input, actual[i])
+				break
+			}
+		}
+	}
+}
+
+func TestScannerAllocations(t *testing.T) {
+	source := []rune(strings.Repeat("if (a.b >= 10) { c = 'd' } else return null;\n", 100))
+	scanner := NewLexer().(*lexerImpl).scanner
+	count := 0
+	allocs := testing.AllocsPerRun(10, func() {
+		count = 0
+		for pos := 0; pos < len(source); {
+			token, nextPos, err := scanner.next(pos, source, InputElementDiv)
+			if err != nil {
+				t.Fatal(err)
+			} else if token != nil {
+				count++
+			}
+			pos = nextPos
+		}
+	})
+	// Tokens are allocated in slabs and their values are
+	// interned, so the only allocations are of the slabs.
+	if allocs > float64(count/scannerSlabSize+1) {
+		t.Errorf("Expected lexing %v tokens to allocate at most %v times but got %v", count, count/scannerSlabSize+1, allocs)
+	}
+}
+
+func TestReservedWordTable(t *testing.T) {
+	table := newReservedWordTable(Keywords(), FutureReservedWords())
+	expected := map[string]TokenKind{"true": BooleanLiteralToken, "false": BooleanLiteralToken, "null": NullLiteralToken}
+	for word, kind := range Keywords() {
+		expected[word] = kind
+	}
+	for word, kind := range FutureReservedWords() {
+		expected[word] = kind
+	}
+	for word, kind := range expected {
+		if actual, actualKind, isReservedWord := table.lookup([]rune(word)); !isReservedWord || actual != word || actualKind != kind {
+			t.Errorf("Expected %v to be the reserved word %#v but got %v, %#v", word, kind, actual, actualKind)
+		}
+	}
+	for _, word := range []string{"", "i", "awaits", "instanceoff", "Null", "undefined", "let"} {
+		if _, kind, isReservedWord := table.lookup([]rune(word)); isReservedWord {
+			t.Errorf("Expected %q not to be a reserved word but got %#v", word, kind)
+		}
+	}
+}
+
+func TestMinifiedLibraries(t *testing.T) {
+	for _, name := range minifiedLibraries {
+		source := readMinifiedLibrary(t, name)
+		count, err := lexWithGoals(NewReaderTokenStream(bytes.NewReader(source)))
+		if err != nil {
+			t.Errorf("Expected %v to be lexed but got %v after %v tokens", name, err, count)
+		}
+	}
+}
+
+func BenchmarkLexMinifiedLibraries(b *testing.B) {
+	for _, name := range minifiedLibraries {
+		source := readMinifiedLibrary(b, name)
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(source)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := lexWithGoals(NewReaderTokenStream(bytes.NewReader(source))); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Benchmarks lexing the code points of the libraries with the scanner alone,
+// which is compared like for like with NextToken.
+func BenchmarkScannerMinifiedLibraries(b *testing.B) {
+	scanner := NewLexer().(*lexerImpl).scanner
+	for _, name := range minifiedLibraries {
+		source := readMinifiedLibrary(b, name)
+		input := []rune(string(source))
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(source)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := lexAll(input, scanner.next); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Benchmarks lexing the code points of the libraries with NextToken alone,
+// which the fast path of the scanner is compared with.
+func BenchmarkNextTokenMinifiedLibraries(b *testing.B) {
+	scanner := NewLexer().(*lexerImpl).scanner
+	for _, name := range minifiedLibraries {
+		source := readMinifiedLibrary(b, name)
+		input := []rune(string(source))
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(source)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := lexAll(input, func(pos int, buf []rune, goal LexicalGoalSymbol) (*Token, int, error) {
+					return NextToken(pos, buf, scanner.charMap, scanner.pMap, scanner.kwMap, scanner.frwMap, goal)
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// Position provides a location in source text.
+type Position struct {
+	// Offset holds the number of bytes of the UTF-8 encoded
+	// source text which precede the position.
+	Offset int
+	// Line holds the line of the position starting from 1.
+	Line int
+	// Column holds the number of code points on the line
+	// which precede the position plus 1.
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%v:%v", p.Line, p.Column)
+}
+
+// Span provides the source text between two positions,
+// the end position is just after the last code point.
+type Span struct {
+	Start Position
+	End   Position
+}
+
+func (s Span) String() string {
+	return fmt.Sprintf("%v-%v", s.Start, s.End)
+}
+
+// SourceFile provides the lines of source text, used to map the code point index
+// or byte offset of a location to its position and a position back to its offset.
+// Lines are broken by each of the ECMAScript line terminators, where <CR><LF>
+// is a single line break.
+type SourceFile struct {
+	source []rune
+	// Holds the code point index and byte offset
+	// that each line starts at.
+	lineIndices []int
+	lineOffsets []int
+	size        int
+}
+
+// NewSourceFile deals with creating a source file for the given source text.
+func NewSourceFile(source []rune) *SourceFile {
+	file := &SourceFile{source: source, lineIndices: []int{0}, lineOffsets: []int{0}}
+	lineTerminators := LineTerminators()
+	offset := 0
+	for i, c := range source {
+		offset += utf8.RuneLen(c)
+		if breaksLine(source, i, lineTerminators) {
+			file.lineIndices = append(file.lineIndices, i+1)
+			file.lineOffsets = append(file.lineOffsets, offset)
+		}
+	}
+	file.size = offset
+	return file
+}
+
+// Determines whether the code point at the given index of the source text breaks a line,
+// which every line terminator does apart from a <CR> followed by a <LF> as <CR><LF>
+// is a single line break after the <LF>.
+func breaksLine(source []rune, i int, lineTerminators map[rune]rune) bool {
+	c := source[i]
+	_, isLT := lineTerminators[c]
+	return isLT && !(c == '\u000D' && i+1 < len(source) && source[i+1] == '\u000A')
+}
+
+// Lines provides the number of lines of the source text.
+func (f *SourceFile) Lines() int {
+	return len(f.lineIndices)
+}
+
+// PositionOfIndex provides the position of the code point at the given index,
+// such as Token.Pos. Indices beyond the source text are clamped to its end.
+func (f *SourceFile) PositionOfIndex(index int) Position {
+	if index < 0 {
+		index = 0
+	} else if index > len(f.source) {
+		index = len(f.source)
+	}
+	line := sort.Search(len(f.lineIndices), func(i int) bool { return f.lineIndices[i] > index }) - 1
+	offset := f.lineOffsets[line]
+	for _, c := range f.source[f.lineIndices[line]:index] {
+		offset += utf8.RuneLen(c)
+	}
+	return Position{Offset: offset, Line: line + 1, Column: index - f.lineIndices[line] + 1}
+}
+
+// PositionOfOffset provides the position of the given byte offset. Offsets within
+// the encoding of a code point are rounded down to the start of the code point and
+// offsets beyond the source text are clamped to its end.
+func (f *SourceFile) PositionOfOffset(offset int) Position {
+	if offset < 0 {
+		offset = 0
+	} else if offset > f.size {
+		offset = f.size
+	}
+	line := sort.Search(len(f.lineOffsets), func(i int) bool { return f.lineOffsets[i] > offset }) - 1
+	position := Position{Offset: f.lineOffsets[line], Line: line + 1, Column: 1}
+	index := f.lineIndices[line]
+	for index < len(f.source) && position.Offset+utf8.RuneLen(f.source[index]) <= offset {
+		position.Offset += utf8.RuneLen(f.source[index])
+		position.Column++
+		index++
+	}
+	return position
+}
+
+// Offset provides the byte offset of the given line and column, the second
+// value is false when the line or column is not within the source text.
+func (f *SourceFile) Offset(line int, column int) (int, bool) {
+	index, exists := f.Index(line, column)
+	if !exists {
+		return 0, false
+	}
+	offset := f.lineOffsets[line-1]
+	for _, c := range f.source[f.lineIndices[line-1]:index] {
+		offset += utf8.RuneLen(c)
+	}
+	return offset, true
+}
+
+// Index provides the code point index of the given line and column, the second
+// value is false when the line or column is not within the source text.
+func (f *SourceFile) Index(line int, column int) (int, bool) {
+	if line < 1 || line > len(f.lineIndices) || column < 1 {
+		return 0, false
+	}
+	// The line terminator is the last code point of each line but the last,
+	// the position just after it is on the following line.
+	end := len(f.source)
+	if line < len(f.lineIndices) {
+		end = f.lineIndices[line] - 1
+	}
+	index := f.lineIndices[line-1] + column - 1
+	if index > end {
+		return 0, false
+	}
+	return index, true
+}
+
+// Span provides the span between the code points at the given indices.
+func (f *SourceFile) Span(start int, end int) Span {
+	return Span{Start: f.PositionOfIndex(start), End: f.PositionOfIndex(end)}
+}
+
+// LexError provides the error for the case when source text
+// could not be tokenised, along with where it occurred.
+type LexError struct {
+	Position Position
+	Err      error
+}
+
+func (e *LexError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Position, e.Err)
+}
+
+func (e *LexError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSourceFilePositions(t *testing.T) {
+	// Each of the line terminators breaks a line, <CR><LF> only once.
+	file := NewSourceFile([]rune("a\r\nbé\rc d \ne"))
+	if file.Lines() != 6 {
+		t.Errorf("Expected 6 lines but got %v", file.Lines())
+	}
+	for index, expected := range map[int]Position{
+		0:  {Offset: 0, Line: 1, Column: 1},
+		3:  {Offset: 3, Line: 2, Column: 1},
+		5:  {Offset: 6, Line: 2, Column: 3},
+		6:  {Offset: 7, Line: 3, Column: 1},
+		8:  {Offset: 11, Line: 4, Column: 1},
+		10: {Offset: 15, Line: 5, Column: 1},
+		11: {Offset: 16, Line: 6, Column: 1},
+		12: {Offset: 17, Line: 6, Column: 2},
+	} {
+		if actual := file.PositionOfIndex(index); actual != expected {
+			t.Errorf("Expected index %v to be at %+v but got %+v", index, expected, actual)
+		}
+		if actual := file.PositionOfOffset(expected.Offset); actual != expected {
+			t.Errorf("Expected offset %v to be at %+v but got %+v", expected.Offset, expected, actual)
+		}
+		if actual, exists := file.Index(expected.Line, expected.Column); !exists || actual != index {
+			t.Errorf("Expected %v to be index %v but got %v", expected, index, actual)
+		}
+		if actual, exists := file.Offset(expected.Line, expected.Column); !exists || actual != expected.Offset {
+			t.Errorf("Expected %v to be offset %v but got %v", expected, expected.Offset, actual)
+		}
+	}
+	// Offsets within a code point belong to the code point.
+	if actual := file.PositionOfOffset(5); actual != (Position{Offset: 4, Line: 2, Column: 2}) {
+		t.Errorf("Expected the second byte of é to be at 2:2 but got %+v", actual)
+	}
+	for _, position := range []Position{{Line: 0, Column: 1}, {Line: 2, Column: 5}, {Line: 6, Column: 3}, {Line: 7, Column: 1}} {
+		if _, exists := file.Offset(position.Line, position.Column); exists {
+			t.Errorf("Expected %v not to be within the source", position)
+		}
+	}
+}
+
+func TestLexerTokenSpans(t *testing.T) {
+	tokens, err := NewLexer().Tokenise([]rune("let é =\r\n  'ü';"), InputElementDiv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Span{
+		{Position{0, 1, 1}, Position{3, 1, 4}},
+		{Position{4, 1, 5}, Position{6, 1, 6}},
+		{Position{7, 1, 7}, Position{8, 1, 8}},
+		{Position{8, 1, 8}, Position{10, 2, 1}},
+		{Position{12, 2, 3}, Position{16, 2, 6}},
+		{Position{16, 2, 6}, Position{17, 2, 7}},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %v tokens but got %v", len(expected), len(tokens))
+	}
+	for i, token := range tokens {
+		if token.Span != expected[i] {
+			t.Errorf("Expected %q to span %v but got %v", token.Value, expected[i], token.Span)
+		}
+	}
+}
+
+func TestLexerTokenRaw(t *testing.T) {
+	source := "let é =\r\n  'ü'; /* a */ 0x1F" + strings.Repeat(" b", windowChunkSize)
+	tokens, err := NewLexer().Tokenise([]rune(source), InputElementDiv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, token := range tokens {
+		if string(token.Raw) != source[token.Span.Start.Offset:token.Span.End.Offset] {
+			t.Fatalf("Expected the raw source text of %q to be %q but got %q",
+				token.Value, source[token.Span.Start.Offset:token.Span.End.Offset], token.Raw)
+		}
+	}
+	// The raw source text of the tokens share a buffer but their capacity ends with
+	// them, so appending to one doesn't overwrite the one which follows.
+	_ = append(tokens[0].Raw, "xyz"...)
+	if string(tokens[1].Raw) != "é" {
+		t.Errorf("Expected the raw source text of é to be unchanged but got %q", tokens[1].Raw)
+	}
+}
+
+func TestLexerErrorPosition(t *testing.T) {
+	_, err := NewLexer().Tokenise([]rune("let a;\n  #b"), InputElementDiv)
+	var lexErr *LexError
+	if !errors.As(err, &lexErr) || lexErr.Position != (Position{Offset: 9, Line: 2, Column: 3}) {
+		t.Errorf("Expected an error at 2:3 but got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package parser
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	// ErrDiscardedSource provides the error for the case when a token stream read from
+	// a reader is asked to lex tokens again whose source text has been discarded.
+	ErrDiscardedSource = errors.New("the source text of the token has been discarded")
+	// ErrInvalidMark provides the error for the case when a token stream is reset to a mark
+	// which has been released or which it didn't provide.
+	ErrInvalidMark = errors.New("the mark is not held by the token stream")
+)
+
+// TokenStream provides the tokens of source text as they are pulled by a parser rather
+// than tokenising all of it up front. Whether a / or } starts a regular expression, a division
+// or a template continuation depends on the syntactic context, so each token is lexed with the
+// lexical goal symbol asked for when it is pulled. Tokens which are looked ahead at are buffered
+// and only lexed again when they are asked for with a different goal.
+//
+// Once a mark has been made consumed tokens are kept so the stream can be reset to the mark and
+// parsed again until the mark is released, otherwise they are dropped so the stream only holds
+// the tokens looked ahead at. This is non-threadsafe as a stream is only pulled from by a single parse.
+type TokenStream struct {
+	source  *sourceWindow
+	scanner *scanner
+	// Holds the tokens lexed so far from the token at the index base, those from next onwards
+	// have only been looked ahead at. The tokens before base have been dropped and
+	// the index and position just after them are held in baseEnd and basePosition.
+	tokens       []streamToken
+	base         int
+	baseEnd      int
+	basePosition Position
+	next         int
+	// Holds the latest mark or -1 when no mark is held, the source text of
+	// the tokens after it is kept so they can be lexed again with another goal.
+	mark int
+}
+
+// Holds a token of the stream along with the goal it was lexed with and the index
+// and position just after it, which the following token is lexed from.
+type streamToken struct {
+	token    *Token
+	goal     LexicalGoalSymbol
+	end      int
+	position Position
+}
+
+// NewTokenStream creates a new stream of the tokens of the given input.
+func NewTokenStream(input []rune) *TokenStream {
+	return newTokenStream(newRuneWindow(input), nil)
+}
+
+// NewReaderTokenStream creates a new stream of the tokens of UTF-8 source text read from the
+// given reader. The source is read incrementally and only the window of it which holds the tokens
+// that can still be lexed again is kept in memory, which is the tokens after the latest mark until
+// it is released or those which have only been looked ahead at. Errors reading the source, including
+// ErrInvalidUnicodeSourceText for invalid UTF-8, are returned when the tokens are pulled.
+func NewReaderTokenStream(reader io.Reader) *TokenStream {
+	return newTokenStream(newReaderWindow(reader), nil)
+}
+
+// Provides the stream of the tokens of the given source,
+// lexed with the scanner of the lexer when one is given.
+func newTokenStream(source *sourceWindow, lexer *lexerImpl) *TokenStream {
+	if lexer == nil {
+		lexer = NewLexer().(*lexerImpl)
+	}
+	return &TokenStream{
+		source:       source,
+		scanner:      lexer.scanner,
+		basePosition: Position{Line: 1, Column: 1},
+		mark:         -1,
+	}
+}
+
+// Next deals with consuming the next token of the stream lexed with the given goal,
+// a nil token is returned at the end of input. Line terminators are tokens of the
+// stream, as they are when tokenising, so the parser can check [no LineTerminator here]
+// restrictions. Errors are returned as a *LexError holding the position they occurred at.
+func (s *TokenStream) Next(goal LexicalGoalSymbol) (*Token, error) {
+	token, err := s.Peek(1, goal)
+	if token != nil {
+		s.next++
+	}
+	return token, err
+}
+
+// Peek provides the nth upcoming token lexed with the given goal without consuming it,
+// where Peek(1, goal) is the token Next(goal) returns. The tokens up to the nth are
+// buffered so k tokens can be looked ahead at, a nil token is returned when the input
+// ends before the nth token.
+func (s *TokenStream) Peek(n int, goal LexicalGoalSymbol) (*Token, error) {
+	if s.mark < 0 && s.next > s.base {
+		s.drop()
+	}
+	for i := s.next; i < s.next+n; i++ {
+		if i-s.base < len(s.tokens) && s.tokens[i-s.base].goal == goal {
+			continue
+		}
+		// The tokens which follow one lexed with a different goal
+		// start in a different place so are discarded as well.
+		start, position := s.after(i)
+		if start < s.source.start {
+			return nil, ErrDiscardedSource
+		}
+		if i-s.base < len(s.tokens) {
+			s.tokens = s.tokens[:i-s.base]
+		}
+		token, end, endPosition, err := s.lex(start, position, goal)
+		if token == nil || err != nil {
+			return nil, err
+		}
+		s.tokens = append(s.tokens, streamToken{token: token, goal: goal, end: end, position: endPosition})
+	}
+	if n < 1 {
+		return nil, nil
+	}
+	return s.tokens[s.next+n-1-s.base].token, nil
+}
+
+// Mark provides the position of the stream before the next token, the stream can be reset
+// to the mark to consume the tokens after it again until the marks are released.
+func (s *TokenStream) Mark() int {
+	s.mark = s.next
+	return s.next
+}
+
+// Reset deals with moving the stream back to a mark provided by Mark, the tokens consumed
+// since are kept and provided again unless they are asked for with a different goal. When the
+// source is read from a reader only the tokens after the latest mark can be lexed again, asking
+// for the tokens after an earlier mark with a different goal fails with ErrDiscardedSource.
+// The marks are held after a reset so the tokens can be parsed again more than once, resetting
+// to a mark which has been released or which the stream didn't provide fails with ErrInvalidMark.
+func (s *TokenStream) Reset(mark int) error {
+	if s.mark < 0 || mark < s.base || mark > s.mark {
+		return ErrInvalidMark
+	}
+	s.next = mark
+	return nil
+}
+
+// Release deals with releasing the marks made so far once the stream no longer needs
+// to be reset to them, from then on the consumed tokens and their source text are dropped.
+func (s *TokenStream) Release() {
+	s.mark = -1
+}
+
+// Provides the index and position just after the token before the one at the given index,
+// which is where the token at the index is lexed from.
+func (s *TokenStream) after(index int) (int, Position) {
+	if index == s.base {
+		return s.baseEnd, s.basePosition
+	}
+	return s.tokens[index-1-s.base].end, s.tokens[index-1-s.base].position
+}
+
+// Deals with dropping the consumed tokens, those which have
+// only been looked ahead at are moved to the front.
+func (s *TokenStream) drop() {
+	s.baseEnd, s.basePosition = s.after(s.next)
+	s.tokens = s.tokens[:copy(s.tokens, s.tokens[s.next-s.base:])]
+	s.base = s.next
+}
+
+// Provides the next token lexed from the given index and position skipping white space and
+// comments, along with the index and position just after the token. A nil token is provided at
+// the end of input. The window of the source is extended until it holds enough of the source
+// after the token to be sure where the token ends, so a token which fails to be lexed is only
+// reported once the window reaches the end of the source.
+func (s *TokenStream) lex(index int, position Position, goal LexicalGoalSymbol) (*Token, int, Position, error) {
+	keep, keepPosition := s.after(s.next)
+	if s.mark >= 0 && s.mark < s.next {
+		keep, keepPosition = s.after(s.mark)
+	}
+	ahead := windowLookahead
+	for {
+		s.source.fill(index+ahead, keep, keepPosition)
+		pos := index - s.source.start
+		if pos >= len(s.source.runes) {
+			return nil, index, position, s.source.err
+		}
+		token, nextPos, err := s.scanner.next(pos, s.source.runes, goal)
+		if !s.source.eof && (err != nil || nextPos+windowLookahead > len(s.source.runes)) {
+			// The token could continue beyond the window.
+			ahead = 2*ahead + len(s.source.runes) - pos
+			continue
+		}
+		if err != nil && s.source.err != nil {
+			// The token was cut short by the error reading the source.
+			return nil, index, position, s.source.err
+		} else if err != nil {
+			return nil, index, position, &LexError{Position: position, Err: err}
+		}
+		next := s.source.start + nextPos
+		if token == nil {
+			position = s.source.advance(position, index, next)
+			index = next
+			continue
+		}
+		token.Pos += s.source.start
+		start := s.source.advance(position, index, token.Pos)
+		end := s.source.advance(start, token.Pos, next)
+		token.Span = Span{Start: start, End: end}
+		token.Raw = s.source.raw(token.Pos, next, start, end)
+		return token, next, end, nil
+	}
+}
@@ -0,0 +1,233 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestTokenStreamGoals(t *testing.T) {
+	stream := NewTokenStream([]rune("/a/ 1"))
+	if token, err := stream.Peek(1, InputElementRegExp); err != nil || token.Kind != RegularExpressionLiteralToken {
+		t.Fatalf("Expected a regular expression literal but got %+v, %v", token, err)
+	}
+	if token, err := stream.Peek(2, InputElementRegExp); err != nil || token.Value != "1" {
+		t.Fatalf("Expected to look ahead at 1 but got %+v, %v", token, err)
+	}
+	// The tokens looked ahead at are lexed again with the goal they are consumed with.
+	expected := []TokenKind{SlashToken, IdentifierNameToken, SlashToken, DecimalLiteralToken}
+	actual := []TokenKind{}
+	for token, err := stream.Next(InputElementDiv); token != nil || err != nil; token, err = stream.Next(InputElementDiv) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual = append(actual, token.Kind)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected the kinds %v but got %v", expected, actual)
+	}
+}
+
+func TestTokenStreamMarkAndReset(t *testing.T) {
+	stream := NewTokenStream([]rune("let a\n= b;"))
+	first, _ := stream.Next(InputElementDiv)
+	mark := stream.Mark()
+	second, _ := stream.Next(InputElementDiv)
+	if lineTerminator, _ := stream.Next(InputElementDiv); lineTerminator.Kind != LineTerminatorToken {
+		t.Errorf("Expected a line terminator token but got %+v", lineTerminator)
+	}
+	if err := stream.Reset(mark); err != nil {
+		t.Fatal(err)
+	}
+	if token, _ := stream.Next(InputElementDiv); token != second || first.Value != "let" {
+		t.Errorf("Expected the stream to be reset to %+v but got %+v", second, token)
+	}
+	if token, _ := stream.Peek(3, InputElementDiv); token.Value != "b" {
+		t.Errorf("Expected to look ahead at b but got %+v", token)
+	}
+	// The mark is held after a reset until it is released.
+	if err := stream.Reset(mark); err != nil {
+		t.Errorf("Expected the stream to be reset to the mark again but got %v", err)
+	}
+	for _, invalid := range []int{mark + 1, -1} {
+		if err := stream.Reset(invalid); !errors.Is(err, ErrInvalidMark) {
+			t.Errorf("Expected resetting to %v to fail with an invalid mark error but got %v", invalid, err)
+		}
+	}
+	stream.Release()
+	if err := stream.Reset(mark); !errors.Is(err, ErrInvalidMark) {
+		t.Errorf("Expected resetting to a released mark to fail with an invalid mark error but got %v", err)
+	}
+}
+
+func TestReaderTokenStreamRelease(t *testing.T) {
+	source := strings.Repeat("let a = b;\n", 4000)
+	stream := NewReaderTokenStream(iotest.OneByteReader(strings.NewReader(source)))
+	stream.Next(InputElementDiv)
+	mark := stream.Mark()
+	for i := 0; i < 1000; i++ {
+		stream.Next(InputElementDiv)
+	}
+	if err := stream.Reset(mark); err != nil {
+		t.Fatal(err)
+	}
+	// Once the mark is released the consumed tokens and
+	// the source text before them are dropped again.
+	stream.Release()
+	count := 0
+	for token, err := stream.Next(InputElementDiv); token != nil; token, err = stream.Next(InputElementDiv) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The tokens consumed before the reset are provided again first.
+		if count > 1000 && len(stream.tokens) > 1 {
+			t.Fatalf("Expected the consumed tokens to be dropped but the stream holds %v tokens", len(stream.tokens))
+		}
+		if count++; count == 20000 && len(stream.source.runes) > 4*windowChunkSize {
+			t.Errorf("Expected the window of the source to be discarded but it holds %v code points", len(stream.source.runes))
+		}
+	}
+	if count != 6*4000-1 {
+		t.Errorf("Expected %v tokens after the first but got %v", 6*4000-1, count)
+	}
+}
+
+func TestTokenStreamLexError(t *testing.T) {
+	stream := NewTokenStream([]rune("a\n #"))
+	stream.Next(InputElementDiv)
+	_, err := stream.Peek(2, InputElementDiv)
+	var lexErr *LexError
+	if !errors.As(err, &lexErr) || lexErr.Position.String() != "2:2" {
+		t.Errorf("Expected a lexical error at 2:2 but got %v", err)
+	}
+}
+
+// Holds the tables generated for expressions of identifiers and
+// regular expression literals separated by division.
+func streamTestTables() *ParseTables {
+	return &ParseTables{
+		SymbolNames: []string{
+			"", "Script", "Expr", "Tail", "RegularExpressionLiteral", "IdentifierName", "/", "[eoi]",
+		},
+		NonTerminalCount: 3,
+		Starts:           map[string]Symbol{"Script": 1},
+		EndOfInput:       7,
+		Rules: []*ParseRule{
+			{Production: 1, Symbols: []Symbol{2}},
+			{Production: 2, Symbols: []Symbol{5, 3}},
+			{Production: 2, Symbols: []Symbol{4, 3}},
+			{Production: 3, Symbols: []Symbol{6, 2}},
+			{Production: 3, Symbols: []Symbol{}},
+		},
+		ParseTable: map[Symbol]map[Symbol]int{
+			1: {4: 0, 5: 0},
+			2: {4: 2, 5: 1},
+			3: {6: 3, 7: 4},
+		},
+	}
+}
+
+func TestParseTablesParseStream(t *testing.T) {
+	tables := streamTestTables()
+	tree, err := tables.ParseStream(1, NewTokenStream([]rune("a / /b/g /\n c")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Symbol{1, 2, 5, 3, 6, 2, 4, 3, 6, 2, 5, 3}
+	if !reflect.DeepEqual(treeSymbols(tree), expected) {
+		t.Errorf("Expected the parse tree %v but got %v", expected, treeSymbols(tree))
+	}
+	if len(tree.Tokens) != 6 || tree.Tokens[2].Value != "/b/g" {
+		t.Errorf("Expected the tokens of the tree to include the regular expression but got %v", tree.Tokens)
+	}
+	for input, expected := range map[string]error{
+		"a / /b":  &LexError{},
+		"a /":     ErrUnexpectedEndOfInput,
+		"/b/ c":   ErrUnexpectedToken,
+		"a / b /": ErrUnexpectedEndOfInput,
+	} {
+		_, err := streamTestTables().ParseStream(1, NewTokenStream([]rune(input)))
+		var lexErr *LexError
+		if _, isLexErr := expected.(*LexError); (isLexErr && !errors.As(err, &lexErr)) || (!isLexErr && !errors.Is(err, expected)) {
+			t.Errorf("Expected parsing %q to fail with %v but got %v", input, expected, err)
+		}
+	}
+}
+
+func TestReaderTokenStream(t *testing.T) {
+	source := strings.Repeat("let aé = 'bü' / 0x1F;\r\n", 2000) + "`" + strings.Repeat("c", 3*windowChunkSize) + "`"
+	expected := NewTokenStream([]rune(source))
+	stream := NewReaderTokenStream(iotest.OneByteReader(strings.NewReader(source)))
+	count := 0
+	for {
+		token, err := stream.Next(InputElementDiv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedToken, _ := expected.Next(InputElementDiv)
+		if !reflect.DeepEqual(token, expectedToken) {
+			t.Fatalf("Expected the token %+v but got %+v", expectedToken, token)
+		}
+		if token == nil {
+			break
+		}
+		if string(token.Raw) != source[token.Span.Start.Offset:token.Span.End.Offset] {
+			t.Fatalf("Expected the raw source text of %+v to be a slice of the source", token)
+		}
+		if len(stream.tokens) > 1 {
+			t.Fatalf("Expected the consumed tokens to be dropped but the stream holds %v tokens", len(stream.tokens))
+		}
+		if count++; count == 8*1000 && len(stream.source.runes) > 4*windowChunkSize {
+			t.Errorf("Expected the window of the source to be discarded but it holds %v code points", len(stream.source.runes))
+		}
+	}
+	if count != 8*2000+1 {
+		t.Errorf("Expected %v tokens but got %v", 8*2000+1, count)
+	}
+}
+
+func TestReaderTokenStreamEscapes(t *testing.T) {
+	// Tokens full of escape sequences are cut off at the end of the window wherever
+	// it ends up, they must be lexed once the window has been extended.
+	for _, source := range []string{
+		"x = '" + strings.Repeat("a\\n", 4000) + "';",
+		"x = \"" + strings.Repeat("\\u0041\\x42\\\n", 1000) + "\";",
+		"x = `" + strings.Repeat("\\u{41}$\\x42\\\n", 1000) + "`;",
+		strings.Repeat("a\\u0062\\u{63} = /[\\]\\/]\\//g;\n", 1000),
+	} {
+		expected := NewTokenStream([]rune(source))
+		stream := NewReaderTokenStream(iotest.OneByteReader(strings.NewReader(source)))
+		goal := InputElementRegExp
+		for {
+			token, err := stream.Next(goal)
+			if err != nil {
+				t.Fatalf("Expected %.20q to be lexed but got %v", source, err)
+			}
+			expectedToken, _ := expected.Next(goal)
+			if !reflect.DeepEqual(token, expectedToken) {
+				t.Fatalf("Expected the token %+v but got %+v", expectedToken, token)
+			}
+			if token == nil {
+				break
+			}
+			goal = InputElementDiv
+			if token.Kind == EqualsToken || token.Kind == LineTerminatorToken {
+				goal = InputElementRegExp
+			}
+		}
+	}
+}
+
+func TestReaderTokenStreamInvalidUnicode(t *testing.T) {
+	stream := NewReaderTokenStream(strings.NewReader("a b \xff c"))
+	for _, expected := range []string{"a", "b"} {
+		if token, err := stream.Next(InputElementDiv); err != nil || token.Value != expected {
+			t.Fatalf("Expected the token %v but got %+v, %v", expected, token, err)
+		}
+	}
+	if _, err := stream.Next(InputElementDiv); !errors.Is(err, ErrInvalidUnicodeSourceText) {
+		t.Errorf("Expected an invalid unicode error but got %v", err)
+	}
+}
@@ -1,15 +1,154 @@
 package parser
 
+import (
+	"sync"
+)
+
 // Token holds a token produced in the token table
 // of the lexical analysis stage.
 type Token struct {
-	Name  string
+	Kind  TokenKind
 	Value string
-	Pos   int
+	// Pos holds the index of the first code point of the token.
+	Pos int
+	// Span holds the positions of the start and end of the token,
+	// populated when the token is produced by a Lexer.
+	Span Span
+	// Raw holds the UTF-8 encoding of the source text of the token, populated when the token
+	// is produced by a Lexer. Unlike the value it includes the quotes of string literals and
+	// the prefixes of numeric literals. It is a copy rather than a slice of the source passed
+	// to the lexer, which is code points or read from an io.Reader, and the raw source text
+	// of consecutive tokens share the same buffer.
+	Raw []byte
 }
 
 type Symbol int
 
+// ParseTables holds the symbols, rules and LL(1) parse table
+// generated by esegrammar from the syntactic grammar.
+type ParseTables struct {
+	// SymbolNames holds the name of each symbol indexed by the symbol.
+	SymbolNames []string
+	// NonTerminalCount provides the number of non-terminal symbols,
+	// non-terminal symbols always precede terminal symbols.
+	NonTerminalCount int
+	// Starts maps the name of each goal symbol to its symbol.
+	Starts map[string]Symbol
+	// EndOfInput provides the terminal symbol which marks the end of the input.
+	EndOfInput Symbol
+	// Rules holds each right-hand side rule referred to from the parse table.
+	Rules []*ParseRule
+	// ParseTable maps a non-terminal and a lookahead terminal
+	// to the index of the rule to expand.
+	ParseTable map[Symbol]map[Symbol]int
+	// Fallbacks maps a non-terminal and a lookahead terminal whose rule starts
+	// with a lookahead predicate to the rules tried in order when the predicate
+	// rejects the upcoming tokens.
+	Fallbacks map[Symbol]map[Symbol][]int
+	// Refinements maps each cover non-terminal to the non-terminals
+	// of the supplemental grammar which refine it, the tokens matched by
+	// a cover are parsed again with one of these as the goal symbol.
+	Refinements map[Symbol][]Symbol
+	// Helpers maps each helper production introduced to make the grammar
+	// LL(1) to its kind, only provided when the grammar has AST annotations.
+	Helpers map[Symbol]HelperKind
+	// ASTRules maps the index of each rule which completes an annotated
+	// alternative of the source grammar to how it builds its node.
+	ASTRules map[int]*ASTRule
+	// ASTBuilders holds the generated functions which build the values of
+	// each annotated alternative from the values built for its symbols.
+	ASTBuilders []func([][]Node) []Node
+	// KindTerminals maps each token kind to the terminal symbol of the syntactic grammar
+	// its tokens are an instance of, 0 for kinds which are not terminals of the grammar.
+	// Populated from the symbol names when tokens are first matched if not generated.
+	KindTerminals []Symbol
+	// Holds the terminal symbol of each terminal which is only matched by the value
+	// of IdentifierName tokens, populated when tokens are first matched to terminals.
+	terminals map[string]Symbol
+	// Holds the lexical goal of the tokens where each symbol is expected and the
+	// number of tokens looked ahead at, populated along with the terminals.
+	goals         []LexicalGoalSymbol
+	lookahead     int
+	terminalsOnce sync.Once
+}
+
+// ParseRule provides a right-hand side rule
+// of a production in the parse tables.
+type ParseRule struct {
+	Production Symbol
+	Symbols    []Symbol
+	// Predicates holds the negative lookahead predicates
+	// compiled from the lookahead restrictions of the rule.
+	Predicates []*LookaheadPredicate
+}
+
+// LookaheadPredicate provides a negative lookahead predicate of a rule,
+// the rest of the rule can only be parsed when the upcoming tokens
+// do not start with any of the excluded sequences of terminals.
+type LookaheadPredicate struct {
+	// Position holds the number of symbols of the rule which precede the predicate,
+	// a predicate at position 0 is checked when the rule is predicted.
+	Position int
+	// Exclude holds the excluded sequences of terminals.
+	Exclude [][]PredicateTerminal
+}
+
+// PredicateTerminal provides a terminal of a sequence excluded by a lookahead predicate.
+type PredicateTerminal struct {
+	Symbol Symbol
+	// NoLineTerminator determines whether the terminal is only matched
+	// when no line terminator precedes it in the token stream.
+	NoLineTerminator bool
+}
+
+// LRTables holds the symbols, rules and LALR(1) action and goto
+// tables generated by esegrammar from the syntactic grammar.
+type LRTables struct {
+	// SymbolNames holds the name of each symbol indexed by the symbol.
+	SymbolNames []string
+	// NonTerminalCount provides the number of non-terminal symbols,
+	// non-terminal symbols always precede terminal symbols.
+	NonTerminalCount int
+	// Starts maps the name of each goal symbol to the state parsing it begins in.
+	Starts map[string]int
+	// EndOfInput provides the terminal symbol which marks the end of the input.
+	EndOfInput Symbol
+	// Rules holds each rule that can be reduced by.
+	Rules []*LRRule
+	// Action maps a state and a lookahead terminal to the action to take.
+	Action []map[Symbol]LRAction
+	// Goto maps a state and the non-terminal that has just
+	// been reduced to the state to move to.
+	Goto []map[Symbol]int
+}
+
+// LRRule provides the production of a rule in the LR tables
+// along with the number of symbols it pops when reduced by.
+type LRRule struct {
+	Production Symbol
+	Length     int
+}
+
+// LRActionKind provides a type alias to distinguish
+// between the actions of the LR action table.
+type LRActionKind int
+
+const (
+	_ LRActionKind = iota
+	// ShiftAction consumes the lookahead terminal and moves to the target state.
+	ShiftAction
+	// ReduceAction reduces by the rule at the target index.
+	ReduceAction
+	// AcceptAction completes the parse of a goal symbol.
+	AcceptAction
+)
+
+// LRAction provides an entry in the LR action table.
+type LRAction struct {
+	Kind   LRActionKind
+	Target int
+}
+
 // ParseNode represents a symbol in the
 // parse tree.
 type ParseNode struct {
@@ -23,6 +162,12 @@ type ParseNode struct {
 	// Children represents from left to right,
 	// the child nodes of our current root node.
 	Children []*ParseNode
+	// Rule holds the index of the rule a non-terminal node was expanded with.
+	Rule int
+	// Tokens holds the tokens the node was parsed from including
+	// the line terminators between them, a terminal node holds
+	// the token it matched.
+	Tokens []*Token
 }
 
 // ParseStack provides a stack data structure
@@ -1,9 +1,7 @@
 package parser
 
 import (
-	"encoding/binary"
-	"encoding/hex"
-	"fmt"
+	"strconv"
 	"unicode/utf16"
 )
 
@@ -12,28 +10,38 @@ import (
 // code points.
 func DecodeUnicodeEscSeq(input []rune) []rune {
 	var encoded []uint16
-	var sequenceStrings []string
-	var sequenceStr string
-	j := 0
 	for i := 0; i < len(input); i++ {
-		if input[i] != '\\' && input[i] != 'u' && input[i] != '{' && input[i] != '}' {
-			sequenceStr += string(input[i])
-			if (j+1)%4 == 0 {
-				sequenceStrings = append(sequenceStrings, sequenceStr)
-				sequenceStr = ""
+		if input[i] == '\\' || input[i] == 'u' {
+			continue
+		}
+		// The hex digits are either the four which follow \u
+		// or any number of them enclosed in braces.
+		braced := input[i] == '{'
+		start, end := i, i+4
+		if braced {
+			start, end = i+1, i+1
+			for end < len(input) && input[end] != '}' {
+				end++
 			}
-			j++
+		} else if end > len(input) {
+			end = len(input)
 		}
-	}
-	for i := 0; i < len(sequenceStrings); i++ {
-		sequenceStr = sequenceStrings[i]
-		hexBytes, err := hex.DecodeString(sequenceStr)
+		value, err := strconv.ParseUint(string(input[start:end]), 16, 32)
 		if err != nil {
 			panic(err)
 		}
-		encodedUint16 := binary.BigEndian.Uint16(hexBytes)
-		fmt.Println(encodedUint16)
-		encoded = append(encoded, encodedUint16)
+		// Each sequence is a UTF-16 code unit or a code point,
+		// consecutive code units can make up a surrogate pair.
+		if value > 0xFFFF {
+			encoded = append(encoded, utf16.Encode([]rune{rune(value)})...)
+		} else {
+			encoded = append(encoded, uint16(value))
+		}
+		// The closing brace is skipped along with the digits.
+		i = end
+		if !braced {
+			i--
+		}
 	}
 	decoded := utf16.Decode(encoded)
 	return decoded
@@ -3,4 +3,15 @@ package parser
 import "testing"
 
 func TestDecodeUnicodeEscSeq(t *testing.T) {
+	for input, expected := range map[string]string{
+		"\\u0041":        "A",
+		"\\u{41}":        "A",
+		"\\u{1F600}":     "\U0001F600",
+		"\\uD83D\\uDE00": "\U0001F600",
+		"\\u00e9\\u{62}": "éb",
+	} {
+		if actual := string(DecodeUnicodeEscSeq([]rune(input))); actual != expected {
+			t.Errorf("Expected %q to be decoded to %q but got %q", input, expected, actual)
+		}
+	}
 }
@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	// Holds the minimum number of code points read into the window of a source at once.
+	windowChunkSize = 4096
+	// Holds the number of code points which must follow a token in the window for it to be
+	// certain the token ends there, as lexing looks beyond the end of some tokens.
+	windowLookahead = MaxReservedWordLength
+)
+
+// Holds the part of the source text a stream lexes from. When the source is read from an
+// io.Reader the code points are decoded into the window as they are needed and those before
+// the tokens that could be lexed again are discarded, so only a sliding window of the source
+// is held in memory rather than all of it.
+type sourceWindow struct {
+	reader *bufio.Reader
+	runes  []rune
+	// Holds the UTF-8 encoding of the code points of the window when the source is read from
+	// a reader, the raw source text of tokens are slices of it. When the source is already in
+	// memory it holds the slab the raw source text of the tokens lexed so far is encoded into.
+	bytes []byte
+	// Holds the index of the first code point of the window
+	// and the byte offset of its encoding in the source.
+	start  int
+	offset int
+	eof    bool
+	err    error
+	// Holds the line terminators which break the lines of the source.
+	lineTerminators map[rune]rune
+}
+
+// Provides the window of source text which is already in memory as code points,
+// which are lexed from directly rather than being copied.
+func newRuneWindow(input []rune) *sourceWindow {
+	return &sourceWindow{runes: input, eof: true, lineTerminators: LineTerminators()}
+}
+
+// Provides the window of source text which is read incrementally from the reader.
+func newReaderWindow(reader io.Reader) *sourceWindow {
+	return &sourceWindow{reader: bufio.NewReader(reader), lineTerminators: LineTerminators()}
+}
+
+// Provides the index just after the last code point read into the window.
+func (w *sourceWindow) end() int {
+	return w.start + len(w.runes)
+}
+
+// Deals with reading code points into the window until it holds a chunk of the source beyond the
+// code point at the given index or the source has been read, the code points before keep are
+// discarded first when they make up most of the window. Keep is provided along with its position.
+// An error reading the source ends the window and is held in err, so the code points before it
+// can still be lexed.
+func (w *sourceWindow) fill(index int, keep int, keepPosition Position) {
+	if index < w.end() || w.eof {
+		return
+	}
+	if keep-w.start > windowChunkSize && keep-w.start > len(w.runes)/2 {
+		w.discard(keep, keepPosition)
+	}
+	var encoded [utf8.UTFMax]byte
+	for !w.eof && w.end() <= index+windowChunkSize {
+		c, size, err := w.reader.ReadRune()
+		if err == io.EOF {
+			w.eof = true
+		} else if err != nil {
+			w.eof, w.err = true, err
+		} else if c == utf8.RuneError && size == 1 {
+			w.eof, w.err = true, ErrInvalidUnicodeSourceText
+		} else {
+			w.runes = append(w.runes, c)
+			w.bytes = append(w.bytes, encoded[:utf8.EncodeRune(encoded[:], c)]...)
+		}
+	}
+}
+
+// Deals with discarding the code points of the window before the given index. The code points
+// kept are moved to the front of the window while their bytes are copied to a new buffer,
+// as the raw source text of the tokens lexed so far refers to the current one.
+func (w *sourceWindow) discard(index int, position Position) {
+	dropped := index - w.start
+	w.runes = w.runes[:copy(w.runes, w.runes[dropped:])]
+	w.bytes = append([]byte{}, w.bytes[position.Offset-w.offset:]...)
+	w.start, w.offset = index, position.Offset
+}
+
+// Provides the position of the code point at the index to, from the position
+// of the code point at the index from, both of which are within the window.
+func (w *sourceWindow) advance(position Position, from int, to int) Position {
+	for i := from - w.start; i < to-w.start; i++ {
+		position.Offset += utf8.RuneLen(w.runes[i])
+		if breaksLine(w.runes, i, w.lineTerminators) {
+			position.Line++
+			position.Column = 1
+		} else {
+			position.Column++
+		}
+	}
+	return position
+}
+
+// Provides the raw source text of the code points between the given indices within the window,
+// which are at the given positions. When the source is already in memory only the code points of
+// tokens are encoded, into a slab which is replaced rather than reused once it is full.
+func (w *sourceWindow) raw(from int, to int, start Position, end Position) []byte {
+	if w.reader != nil {
+		return w.bytes[start.Offset-w.offset : end.Offset-w.offset : end.Offset-w.offset]
+	}
+	size := end.Offset - start.Offset
+	if cap(w.bytes)-len(w.bytes) < size {
+		slabSize := windowChunkSize
+		if size > slabSize {
+			slabSize = size
+		}
+		w.bytes = make([]byte, 0, slabSize)
+	}
+	offset := len(w.bytes)
+	for _, c := range w.runes[from-w.start : to-w.start] {
+		w.bytes = utf8.AppendRune(w.bytes, c)
+	}
+	return w.bytes[offset:len(w.bytes):len(w.bytes)]
+}